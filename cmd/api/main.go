@@ -9,11 +9,9 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
+	fiberrecover "github.com/gofiber/fiber/v2/middleware/recover"
 
-	"sage-of-elements-backend/internal/adapters/cache/redis"
 	"sage-of-elements-backend/internal/adapters/primary/http/middleware"
-	"sage-of-elements-backend/internal/adapters/storage/postgres"
-	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/internal/modules/character"
 	"sage-of-elements-backend/internal/modules/combat"
 	"sage-of-elements-backend/internal/modules/deck"
@@ -22,14 +20,13 @@ import (
 	"sage-of-elements-backend/internal/modules/game_data"
 	"sage-of-elements-backend/internal/modules/player"
 	"sage-of-elements-backend/internal/modules/pve"
+	"sage-of-elements-backend/internal/modules/pvp"
 	"sage-of-elements-backend/pkg/appauth"
 	"sage-of-elements-backend/pkg/appconfig"
 	"sage-of-elements-backend/pkg/apperrors"
 	"sage-of-elements-backend/pkg/applogger"
 	"sage-of-elements-backend/pkg/appresponse"
 	"sage-of-elements-backend/pkg/appvalidator"
-	"sage-of-elements-backend/pkg/platform/apppostgres"
-	"sage-of-elements-backend/pkg/platform/appredis"
 )
 
 func main() {
@@ -47,26 +44,10 @@ func main() {
 	}
 	appLogger.Info("Starting Sage of the Elements Backend...", "env", cfg.Server.Mode)
 
-	redisClient, err := appredis.NewConnection(cfg.Redis, appLogger)
+	// --- 2. Storage (Postgres + Redis หรือ In-Memory) ---
+	repos, err := setupStorage(cfg, appLogger)
 	if err != nil {
-		appLogger.Error("could not connect to Redis", err)
-		os.Exit(1)
-	}
-
-	// --- 2. Platforms Connection ---
-	db, err := apppostgres.NewConnection(cfg.Postgres.Primary, appLogger)
-	if err != nil {
-		appLogger.Error("could not connect to database", err)
-		os.Exit(1)
-	}
-	appLogger.Success("Database connection successful.")
-
-	if err = postgres.AutoMigrate(db, appLogger); err != nil {
-		appLogger.Error("could not auto migrate database:", err)
-		os.Exit(1)
-	}
-	if err = postgres.Seed(db); err != nil {
-		appLogger.Error("could not seed database:", err)
+		appLogger.Error("could not setup storage", err, "storage", cfg.Server.Storage)
 		os.Exit(1)
 	}
 
@@ -74,66 +55,43 @@ func main() {
 	appValidator := appvalidator.New()
 	authSvc := appauth.NewAuthService(cfg.Auth.JWTAccessSecret, cfg.Auth.JWTRefreshSecret)
 
-	playerRepo := postgres.NewPlayerRepository(db)
-	playerSvc := player.NewPlayerService(appLogger, authSvc, playerRepo)
+	playerSvc := player.NewPlayerService(appLogger, authSvc, repos.player)
 	playerHandler := player.NewPlayerHandler(appValidator, playerSvc)
 
-	gameConfigCache := redis.NewGameConfigCacheRepository(redisClient) // สำหรับ Game Configs
-	gameDataCacheRepo := redis.NewGameDataCacheRepository(redisClient) // สำหรับ Master Data ก้อนใหญ่
-
-	// 1. Warm Game Configs (เหมือนเดิม)
-	var allConfigs []domain.GameConfig
-	if err := db.Find(&allConfigs).Error; err != nil {
-		log.Fatalf("FATAL: could not warm game_configs cache: %v", err)
-	}
-	if err := gameConfigCache.SetAllConfigs(allConfigs); err != nil {
-		log.Fatalf("FATAL: could not set game_configs in redis: %v", err)
-	}
-	appLogger.Success("Game configs have been warmed into Redis cache.")
-
-	// --- ⭐️ เพิ่มส่วนนี้เข้ามา! ⭐️ ---
-	// 2. Warm Elemental Matchups
-	var allMatchups []domain.ElementalMatchup
-	if err := db.Find(&allMatchups).Error; err != nil {
-		log.Fatalf("FATAL: could not warm elemental_matchups cache: %v", err)
-	}
-	if err := gameConfigCache.SetAllMatchups(allMatchups); err != nil {
-		log.Fatalf("FATAL: could not set elemental_matchups in redis: %v", err)
-	}
-	appLogger.Success("Elemental matchups have been warmed into Redis cache.")
-	// ------------------------------------------
-
-	gameDataDbRepo := postgres.NewGameDataRepository(db, gameConfigCache)
-	gameDataSvc := game_data.NewGameDataService(appLogger, gameDataDbRepo, gameDataCacheRepo)
+	gameDataSvc := game_data.NewGameDataService(appLogger, repos.gameData, repos.gameDataCache)
 	gameDataHandler := game_data.NewGameDataHandler(gameDataSvc)
 
-	characterRepo := postgres.NewCharacterRepository(db)
-	characterSvc := character.NewCharacterService(appLogger, characterRepo, gameDataDbRepo)
+	characterSvc := character.NewCharacterService(appLogger, repos.character, repos.gameData)
 	characterHandler := character.NewCharacterHandler(appValidator, characterSvc)
 
-	deckRepo := postgres.NewDeckRepository(db)
-	deckSvc := deck.NewDeckService(appLogger, deckRepo, characterRepo)
+	deckSvc := deck.NewDeckService(appLogger, repos.deck, repos.character)
 	deckHandler := deck.NewDeckHandler(appValidator, deckSvc)
 
-	fusionRepo := postgres.NewFusionRepository(db)
-	fusionSvc := fusion.NewFusionService(appLogger, db, fusionRepo, characterRepo, gameDataDbRepo)
+	fusionSvc := fusion.NewFusionService(appLogger, repos.fusionTx, repos.fusion, repos.character, repos.gameData)
 	fusionHandler := fusion.NewFusionHandler(appValidator, fusionSvc)
 
-	pveRepo := postgres.NewPveRepository(db)
-	pveSvc := pve.NewPveService(pveRepo)
+	pveSvc := pve.NewPveService(repos.pve, repos.character)
 	pveHandler := pve.NewPveHandler(pveSvc)
 
-	enemyRepo := postgres.NewEnemyRepository(db)
-	enemySvc := enemy.NewEnemyService(enemyRepo)
+	enemySvc := enemy.NewEnemyService(repos.enemy)
 	enemyHandler := enemy.NewEnemyHandler(appValidator, enemySvc)
 
-	combatRepo := postgres.NewCombatRepository(db)
-	combatSvc := combat.NewCombatService(appLogger, combatRepo, characterRepo, enemyRepo, pveRepo, gameDataDbRepo, deckRepo)
-	combatHandler := combat.NewCombatHandler(appLogger, appValidator, combatSvc)
+	liveHub := combat.NewLiveHub(appLogger)
+	combatSvc := combat.NewCombatService(appLogger, repos.combat, repos.character, repos.enemy, repos.pve, repos.gameData, repos.deck, repos.pvp, liveHub)
+	combatHandler := combat.NewCombatHandler(appLogger, appValidator, combatSvc, liveHub)
+
+	pvpSvc := pvp.NewPvpService(appLogger, repos.pvp, repos.pvpQueue, repos.character, repos.gameData, combatSvc)
+	pvpHandler := pvp.NewPvpHandler(appValidator, pvpSvc)
 
 	// 🧹 Setup Cleanup Job - ทำความสะอาด match ที่ค้าง
 	setupCleanupJob(combatSvc, appLogger, cfg.Cleanup)
 
+	// ⏱️ Setup Turn Timer Job - END_TURN แทนผู้เล่นที่หมดเวลา
+	setupTurnTimerJob(combatSvc, appLogger, cfg.TurnTimer)
+
+	// 🏆 Setup Matchmaking Job - จับคู่ผู้เล่นในคิว Ranked
+	setupMatchmakingJob(pvpSvc, appLogger, cfg.Matchmaking)
+
 	// --- 4. Setup Fiber App & Routes ---
 	app := fiber.New(fiber.Config{
 		AppName: "Sage of the Elements API " + cfg.App.Version,
@@ -153,6 +111,8 @@ func main() {
 	authMiddleware := middleware.AuthMiddleware(authSvc)
 
 	// --- 5. ติดตั้ง Middlewares ---
+	// panic ใน handler → ตอบ 500 ผ่าน ErrorHandler แทนการล่มทั้ง process
+	app.Use(fiberrecover.New(fiberrecover.Config{EnableStackTrace: true}))
 	app.Use(corsMiddleware)             // จัดการเรื่อง CORS
 	app.Use(logMiddleware)              // จัดการเรื่อง Log
 	app.Use(limiter.New(limiter.Config{ // ป้องกันการยิง Request ถี่ๆ
@@ -184,6 +144,7 @@ func main() {
 	pveGroup := apiV1.Group("/pve")
 	combatGroup := apiV1.Group("/combat")
 	enemyGroup := apiV1.Group("/enemies")
+	pvpGroup := apiV1.Group("/pvp")
 	// --- Public Routes ---
 	playerHandler.RegisterPublicRoutes(playerGroup)
 
@@ -195,6 +156,7 @@ func main() {
 	pveGroup.Use(authMiddleware)
 	combatGroup.Use(authMiddleware)
 	enemyGroup.Use(authMiddleware)
+	pvpGroup.Use(authMiddleware)
 	// comment out for test
 	// --- Protected Routes ---
 	// ลงทะเบียน Protected Routes
@@ -207,6 +169,7 @@ func main() {
 	pveHandler.RegisterProtectedRoutes(pveGroup)
 	enemyHandler.RegisterProtectedRoutes(enemyGroup)
 	combatHandler.RegisterProtectedRoutes(combatGroup)
+	pvpHandler.RegisterProtectedRoutes(pvpGroup)
 
 	// --- 5. Start Server & Graceful Shutdown ---
 	go func() {
@@ -267,3 +230,51 @@ func setupCleanupJob(combatSvc combat.CombatService, logger applogger.Logger, cf
 		}
 	}()
 }
+
+// setupTurnTimerJob เริ่ม background job สำหรับจัดการเทิร์น/match ที่หมดเวลา
+func setupTurnTimerJob(combatSvc combat.CombatService, logger applogger.Logger, cfg appconfig.TurnTimerConfig) {
+	intervalSeconds := cfg.CheckIntervalSeconds
+	if intervalSeconds <= 0 {
+		intervalSeconds = 5 // default 5 วินาที
+	}
+
+	checkInterval := time.Duration(intervalSeconds) * time.Second
+
+	logger.Info("⏱️ Turn timer job started", "interval", checkInterval.String())
+
+	ticker := time.NewTicker(checkInterval)
+	go func() {
+		for range ticker.C {
+			count, err := combatSvc.ProcessTimeouts()
+			if err != nil {
+				logger.Error("Failed to process turn timeouts", err)
+			} else if count > 0 {
+				logger.Info("Processed timed out turns", "count", count)
+			}
+		}
+	}()
+}
+
+// setupMatchmakingJob เริ่ม background job สำหรับจับคู่ผู้เล่นในคิว Ranked PvP
+func setupMatchmakingJob(pvpSvc pvp.PvpService, logger applogger.Logger, cfg appconfig.MatchmakingConfig) {
+	intervalSeconds := cfg.IntervalSeconds
+	if intervalSeconds <= 0 {
+		intervalSeconds = 2 // default 2 วินาที
+	}
+
+	matchInterval := time.Duration(intervalSeconds) * time.Second
+
+	logger.Info("🏆 Matchmaking job started", "interval", matchInterval.String())
+
+	ticker := time.NewTicker(matchInterval)
+	go func() {
+		for range ticker.C {
+			count, err := pvpSvc.ProcessQueue()
+			if err != nil {
+				logger.Error("Failed to process matchmaking queue", err)
+			} else if count > 0 {
+				logger.Info("Created ranked matches", "count", count)
+			}
+		}
+	}()
+}
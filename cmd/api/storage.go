@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+
+	"sage-of-elements-backend/internal/adapters/cache/redis"
+	"sage-of-elements-backend/internal/adapters/storage/memory"
+	"sage-of-elements-backend/internal/adapters/storage/postgres"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/internal/modules/combat"
+	"sage-of-elements-backend/internal/modules/deck"
+	"sage-of-elements-backend/internal/modules/enemy"
+	"sage-of-elements-backend/internal/modules/fusion"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/internal/modules/player"
+	"sage-of-elements-backend/internal/modules/pve"
+	"sage-of-elements-backend/internal/modules/pvp"
+	"sage-of-elements-backend/pkg/appconfig"
+	"sage-of-elements-backend/pkg/applogger"
+	"sage-of-elements-backend/pkg/platform/apppostgres"
+	"sage-of-elements-backend/pkg/platform/appredis"
+)
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+)
+
+// repositories คือชุด Repository ทั้งหมดที่ service ต้องใช้ (สลับ storage ได้โดยไม่ต้องแก้ service)
+type repositories struct {
+	player        player.PlayerRepository
+	character     character.CharacterRepository
+	deck          deck.DeckRepository
+	fusion        fusion.FusionRepository
+	fusionTx      fusion.Transactor
+	gameData      game_data.GameDataRepository
+	gameDataCache game_data.CacheRepository
+	enemy         enemy.EnemyRepository
+	pve           pve.PveRepository
+	combat        combat.CombatRepository
+	pvp           pvp.PvpRepository
+	pvpQueue      pvp.MatchmakingQueue
+}
+
+// setupStorage เลือก storage ตาม server.storage ใน config (ค่าเริ่มต้น: postgres)
+func setupStorage(cfg *appconfig.Config, appLogger applogger.Logger) (*repositories, error) {
+	switch cfg.Server.Storage {
+	case "", storagePostgres:
+		return setupPostgresStorage(cfg, appLogger)
+	case storageMemory:
+		return setupMemoryStorage(appLogger)
+	default:
+		return nil, fmt.Errorf("unknown storage %q (expected %s or %s)", cfg.Server.Storage, storagePostgres, storageMemory)
+	}
+}
+
+// setupPostgresStorage เชื่อมต่อ Postgres + Redis, migrate, seed และ warm cache
+func setupPostgresStorage(cfg *appconfig.Config, appLogger applogger.Logger) (*repositories, error) {
+	redisClient, err := appredis.NewConnection(cfg.Redis, appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to Redis: %w", err)
+	}
+
+	db, err := apppostgres.NewConnection(cfg.Postgres.Primary, appLogger)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	appLogger.Success("Database connection successful.")
+
+	if err = postgres.AutoMigrate(db, appLogger); err != nil {
+		return nil, fmt.Errorf("could not auto migrate database: %w", err)
+	}
+	if err = postgres.Seed(db); err != nil {
+		return nil, fmt.Errorf("could not seed database: %w", err)
+	}
+
+	gameConfigCache := redis.NewGameConfigCacheRepository(redisClient) // สำหรับ Game Configs
+	gameDataCacheRepo := redis.NewGameDataCacheRepository(redisClient) // สำหรับ Master Data ก้อนใหญ่
+
+	// 1. Warm Game Configs
+	var allConfigs []domain.GameConfig
+	if err := db.Find(&allConfigs).Error; err != nil {
+		return nil, fmt.Errorf("could not warm game_configs cache: %w", err)
+	}
+	if err := gameConfigCache.SetAllConfigs(allConfigs); err != nil {
+		return nil, fmt.Errorf("could not set game_configs in redis: %w", err)
+	}
+	appLogger.Success("Game configs have been warmed into Redis cache.")
+
+	// 2. Warm Elemental Matchups
+	var allMatchups []domain.ElementalMatchup
+	if err := db.Find(&allMatchups).Error; err != nil {
+		return nil, fmt.Errorf("could not warm elemental_matchups cache: %w", err)
+	}
+	if err := gameConfigCache.SetAllMatchups(allMatchups); err != nil {
+		return nil, fmt.Errorf("could not set elemental_matchups in redis: %w", err)
+	}
+	appLogger.Success("Elemental matchups have been warmed into Redis cache.")
+
+	return &repositories{
+		player:        postgres.NewPlayerRepository(db),
+		character:     postgres.NewCharacterRepository(db),
+		deck:          postgres.NewDeckRepository(db),
+		fusion:        postgres.NewFusionRepository(db),
+		fusionTx:      db,
+		gameData:      postgres.NewGameDataRepository(db, gameConfigCache),
+		gameDataCache: gameDataCacheRepo,
+		enemy:         postgres.NewEnemyRepository(db),
+		pve:           postgres.NewPveRepository(db),
+		combat:        postgres.NewCombatRepository(db),
+		pvp:           postgres.NewPvpRepository(db),
+		pvpQueue:      redis.NewMatchmakingQueue(redisClient),
+	}, nil
+}
+
+// setupMemoryStorage ใช้ Store ในหน่วยความจำที่โหลดข้อมูลชุดเดียวกับ seeder (ข้อมูลหายเมื่อปิด server)
+func setupMemoryStorage(appLogger applogger.Logger) (*repositories, error) {
+	store, err := memory.NewSeededStore()
+	if err != nil {
+		return nil, fmt.Errorf("could not seed memory storage: %w", err)
+	}
+	appLogger.Warn("Using in-memory storage: all data will be lost when the server stops.")
+
+	return &repositories{
+		player:        memory.NewPlayerRepository(store),
+		character:     memory.NewCharacterRepository(store),
+		deck:          memory.NewDeckRepository(store),
+		fusion:        memory.NewFusionRepository(store),
+		fusionTx:      memory.NewTransactor(store),
+		gameData:      memory.NewGameDataRepository(store),
+		gameDataCache: memory.NewGameDataCacheRepository(),
+		enemy:         memory.NewEnemyRepository(store),
+		pve:           memory.NewPveRepository(store),
+		combat:        memory.NewCombatRepository(store),
+		pvp:           memory.NewPvpRepository(store),
+		pvpQueue:      memory.NewMatchmakingQueue(),
+	}, nil
+}
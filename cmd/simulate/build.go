@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/internal/modules/deck"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"strconv"
+)
+
+// simPlayerID คือ Player ID สมมติที่เป็นเจ้าของตัวละครใน simulator
+const simPlayerID uint = 1
+
+// CharacterBuild คือ "สเปก" ของตัวละครที่จะนำไปจำลองการต่อสู้ (อ่านจากไฟล์ JSON ผ่าน -build)
+type CharacterBuild struct {
+	Name             string       `json:"name"`
+	PrimaryElementID uint         `json:"primary_element_id"`
+	Talents          TalentBuild  `json:"talents"`
+	Masteries        map[uint]int `json:"masteries"`            // mastery_id → level
+	Deck             []uint       `json:"deck"`                 // element_id ของแต่ละช่องในเด็ค (T1+)
+	CurrentMP        int          `json:"current_mp,omitempty"` // 0 = ใช้ MP สูงสุดตาม STAT_MP_BASE + TalentL
+}
+
+type TalentBuild struct {
+	S int `json:"s"`
+	L int `json:"l"`
+	G int `json:"g"`
+	P int `json:"p"`
+}
+
+// defaultBuild คือตัวละครมาตรฐาน (ธาตุ Potency, Talent กระจายเท่ากัน, Mastery Lv.1 ทุกศาสตร์)
+func defaultBuild() *CharacterBuild {
+	return &CharacterBuild{
+		Name:             "default",
+		PrimaryElementID: 4,
+		Talents:          TalentBuild{S: 25, L: 25, G: 25, P: 25},
+		Masteries:        map[uint]int{1: 1, 2: 1, 3: 1, 4: 1},
+	}
+}
+
+// loadBuild อ่าน CharacterBuild จากไฟล์ JSON (path ว่าง = defaultBuild)
+func loadBuild(path string) (*CharacterBuild, error) {
+	if path == "" {
+		return defaultBuild(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read build file: %w", err)
+	}
+	build := &CharacterBuild{}
+	if err := json.Unmarshal(raw, build); err != nil {
+		return nil, fmt.Errorf("could not parse build file: %w", err)
+	}
+	if build.PrimaryElementID == 0 {
+		return nil, fmt.Errorf("build %q: primary_element_id is required", build.Name)
+	}
+	if len(build.Deck) > 8 {
+		return nil, fmt.Errorf("build %q: deck can hold at most 8 slots", build.Name)
+	}
+	return build, nil
+}
+
+// createCharacter สร้างตัวละครและเด็คตาม build ลง storage แล้วคืน Character ID และ Deck ID (nil ถ้าไม่มีเด็ค)
+func createCharacter(
+	build *CharacterBuild,
+	characterRepo character.CharacterRepository,
+	deckRepo deck.DeckRepository,
+	gameDataRepo game_data.GameDataRepository,
+) (uint, *uint, error) {
+	currentMP := build.CurrentMP
+	if currentMP <= 0 {
+		baseMpStr, _ := gameDataRepo.GetGameConfigValue("STAT_MP_BASE")
+		mpPerTalentLStr, _ := gameDataRepo.GetGameConfigValue("STAT_MP_PER_TALENT_L")
+		baseMp, _ := strconv.Atoi(baseMpStr)
+		mpPerTalentL, _ := strconv.Atoi(mpPerTalentLStr)
+		currentMP = baseMp + (build.Talents.L * mpPerTalentL)
+	}
+
+	masteries := make([]*domain.CharacterMastery, 0, len(build.Masteries))
+	for masteryID, level := range build.Masteries {
+		masteries = append(masteries, &domain.CharacterMastery{MasteryID: masteryID, Level: level})
+	}
+
+	char, err := characterRepo.Create(&domain.Character{
+		PlayerID:         simPlayerID,
+		CharacterName:    build.Name,
+		Gender:           "MALE",
+		PrimaryElementID: build.PrimaryElementID,
+		CurrentMP:        currentMP,
+		TalentS:          build.Talents.S,
+		TalentL:          build.Talents.L,
+		TalentG:          build.Talents.G,
+		TalentP:          build.Talents.P,
+		Masteries:        masteries,
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not create character: %w", err)
+	}
+
+	if len(build.Deck) == 0 {
+		return char.ID, nil, nil
+	}
+
+	slots := make([]*domain.DeckSlot, 0, len(build.Deck))
+	for i, elementID := range build.Deck {
+		slots = append(slots, &domain.DeckSlot{SlotNum: i + 1, ElementID: elementID})
+	}
+	newDeck, err := deckRepo.Create(&domain.Deck{
+		CharacterID: char.ID,
+		Name:        build.Name,
+		IsActive:    true,
+		Slots:       slots,
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not create deck: %w", err)
+	}
+	return char.ID, &newDeck.ID, nil
+}
@@ -0,0 +1,238 @@
+// Command simulate เล่นการต่อสู้แบบ headless หลายพันรอบเพื่อใช้ปรับ balance
+// (ใช้ CombatService ตัวจริง + memory storage ที่โหลดข้อมูลชุดเดียวกับ seeder ไม่ต้องมี Postgres/Redis)
+//
+// ตัวอย่าง:
+//
+//	go run ./cmd/simulate -build builds/fire.json -enemies 1,2,1+2 -matches 2000 -format csv \
+//	    -config TALENT_DMG_DIVISOR=8 -config ELEMENT_ADVANTAGE_MULTIPLIER=1.4
+//
+// ไฟล์ build (JSON):
+//
+//	{"name": "fire", "primary_element_id": 4, "talents": {"s": 10, "l": 20, "g": 60, "p": 10},
+//	 "masteries": {"1": 3, "2": 1}, "deck": [7, 7, 5]}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"sage-of-elements-backend/internal/adapters/storage/memory"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/combat"
+	"sage-of-elements-backend/pkg/applogger"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// simulationConfigs คือ config ที่ simulator ตั้งก่อน -config ของผู้ใช้
+var simulationConfigs = map[string]string{
+	"MXP_PER_CAST":       "0",
+	"EXP_TRAINING_MATCH": "0",
+}
+
+func main() {
+	var configOverrides configFlag
+	buildPath := flag.String("build", "", "path to a character build JSON file (default: balanced Potency build)")
+	enemiesArg := flag.String("enemies", "", "comma separated scenarios, '+' joins enemies into one match (e.g. 1,2,1+2); default: every seeded enemy alone")
+	matches := flag.Int("matches", 1000, "matches to play per scenario")
+	maxRounds := flag.Int("max-rounds", 50, "rounds before a match counts as a timeout")
+	policyName := flag.String("policy", "greedy", "player policy (greedy, random)")
+	seed := flag.Int64("seed", 1, "seed for policies that make random choices")
+	format := flag.String("format", "json", "output format (json, csv)")
+	outPath := flag.String("out", "", "output file (default: stdout)")
+	verbose := flag.Bool("v", false, "log combat service output")
+	flag.Var(&configOverrides, "config", "override a game config, KEY=VALUE (repeatable)")
+	flag.Parse()
+
+	if *matches <= 0 || *maxRounds <= 0 {
+		log.Fatal("matches and max-rounds must be positive")
+	}
+
+	build, err := loadBuild(*buildPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	policy, err := newPolicy(*policyName, rand.New(rand.NewSource(*seed)))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// --- 1. Storage & Service (memory ทั้งหมด) ---
+	store, err := memory.NewSeededStore()
+	if err != nil {
+		log.Fatalf("could not load seed data: %v", err)
+	}
+	// ตัวละครตัวเดียวเล่นทุก match → ปิด EXP/MXP ไม่ให้เลเวลและศาสตร์ขยับระหว่างรอบ (ผลต้องตรงกับ build ที่ระบุ)
+	// ตั้งกลับผ่าน -config ได้ถ้าอยากดูผลของ progression
+	for key, value := range simulationConfigs {
+		store.SetGameConfig(key, value)
+	}
+	for key, value := range configOverrides {
+		store.SetGameConfig(key, value)
+	}
+
+	appLogger := applogger.NewNopLogger()
+	if *verbose {
+		appLogger = applogger.NewPrettyLogger()
+	}
+
+	characterRepo := memory.NewCharacterRepository(store)
+	deckRepo := memory.NewDeckRepository(store)
+	enemyRepo := memory.NewEnemyRepository(store)
+	gameDataRepo := memory.NewGameDataRepository(store)
+	combatSvc := combat.NewCombatService(
+		appLogger,
+		memory.NewCombatRepository(store),
+		characterRepo,
+		enemyRepo,
+		memory.NewPveRepository(store),
+		gameDataRepo,
+		deckRepo,
+		memory.NewPvpRepository(store),
+		nil, // ไม่มี WebSocket
+	)
+
+	// --- 2. ตัวละครตาม build ---
+	characterID, deckID, err := createCharacter(build, characterRepo, deckRepo, gameDataRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	masteries, err := gameDataRepo.FindAllMasteries()
+	if err != nil {
+		log.Fatalf("could not load masteries: %v", err)
+	}
+	masteryIDs := make([]uint, 0, len(masteries))
+	for _, m := range masteries {
+		masteryIDs = append(masteryIDs, m.ID)
+	}
+	spells := resolveBuildSpells(combatSvc, build, masteryIDs)
+	spellNames := make(map[uint]string, len(spells))
+	spellIDs := make([]uint, 0, len(spells))
+	for _, spell := range spells {
+		spellNames[spell.ID] = spell.Name
+		spellIDs = append(spellIDs, spell.ID)
+	}
+	// simulator ใช้ทดสอบสมดุลของ build → ปลดล็อกเวทที่ build ใช้ทั้งหมดเลย ไม่ต้องเลเวล/ค้นพบสูตรก่อน
+	if err := characterRepo.UnlockSpells(characterID, spellIDs); err != nil {
+		log.Fatalf("could not unlock build spells: %v", err)
+	}
+
+	// --- 3. Scenario (ชุดศัตรู) ---
+	allEnemies, err := enemyRepo.FindAll()
+	if err != nil {
+		log.Fatalf("could not load enemies: %v", err)
+	}
+	enemies := make(map[uint]*domain.Enemy, len(allEnemies))
+	for i := range allEnemies {
+		enemies[allEnemies[i].ID] = &allEnemies[i]
+	}
+	scenarios, err := parseScenarios(*enemiesArg, enemies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// --- 4. Simulate ---
+	r := &runner{
+		combatSvc:   combatSvc,
+		policy:      policy,
+		characterID: characterID,
+		deckID:      deckID,
+		spells:      spells,
+		maxRounds:   *maxRounds,
+	}
+	report := &Report{
+		Build:           build,
+		Policy:          policy.Name(),
+		MatchesPerRun:   *matches,
+		MaxRounds:       *maxRounds,
+		ConfigOverrides: configOverrides,
+	}
+	for _, enemyIDs := range scenarios {
+		stats, err := r.runScenario(enemyIDs, *matches)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scenario := newScenarioReport(stats, enemies, spellNames)
+		if scenario.Stalled > 0 {
+			log.Printf("warning: %d/%d matches against %s stalled: %s",
+				scenario.Stalled, scenario.Matches, strings.Join(scenario.Enemies, "+"), scenario.StalledReason)
+		}
+		report.Scenarios = append(report.Scenarios, scenario)
+	}
+
+	// --- 5. Output ---
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("could not create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeReport(out, *format, report); err != nil {
+		log.Fatalf("could not write report: %v", err)
+	}
+}
+
+// parseScenarios แปลง "1,2,1+2" เป็นชุดศัตรู (ค่าว่าง = ศัตรูทุกตัวใน seeder ตัวละ 1 scenario)
+func parseScenarios(arg string, enemies map[uint]*domain.Enemy) ([][]uint, error) {
+	if strings.TrimSpace(arg) == "" {
+		ids := make([]uint, 0, len(enemies))
+		for id := range enemies {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		scenarios := make([][]uint, 0, len(ids))
+		for _, id := range ids {
+			scenarios = append(scenarios, []uint{id})
+		}
+		return scenarios, nil
+	}
+
+	var scenarios [][]uint
+	for _, group := range strings.Split(arg, ",") {
+		var enemyIDs []uint
+		for _, part := range strings.Split(group, "+") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid enemy id %q in -enemies", part)
+			}
+			if _, ok := enemies[uint(id)]; !ok {
+				return nil, fmt.Errorf("enemy %d does not exist in seed data", id)
+			}
+			enemyIDs = append(enemyIDs, uint(id))
+		}
+		scenarios = append(scenarios, enemyIDs)
+	}
+	return scenarios, nil
+}
+
+// configFlag เก็บค่า -config KEY=VALUE ที่ระบุซ้ำได้หลายครั้ง
+type configFlag map[string]string
+
+func (f *configFlag) String() string {
+	pairs := make([]string, 0, len(*f))
+	for key, value := range *f {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f *configFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	if *f == nil {
+		*f = make(configFlag)
+	}
+	(*f)[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/combat"
+	"sort"
+	"strings"
+)
+
+// ==================== Player Policy ====================
+// Policy คือ "สมอง" ของฝั่งผู้เล่นใน simulator: ดูสถานะเทิร์นแล้วเลือก action 1 ครั้ง
+// - ถ้า action ที่เลือกถูก service ปฏิเสธ runner จะจบเทิร์นแทน (กัน policy วนเลือกท่าเดิมไม่รู้จบ)
+// - เพิ่ม policy ใหม่ได้โดยลงทะเบียนใน policies ด้านล่าง
+
+type Policy interface {
+	Name() string
+	ChooseAction(state *TurnState) combat.PerformActionRequest
+}
+
+// TurnState คือข้อมูลที่ policy ใช้ตัดสินใจในเทิร์นของผู้เล่น
+type TurnState struct {
+	Match    *domain.CombatMatch
+	Self     *domain.Combatant
+	Enemies  []*domain.Combatant // ศัตรูที่ยังมีชีวิต
+	Castable []*domain.Spell     // เวทที่ร่ายได้ตอนนี้ (AP/MP/Charge พอ)
+}
+
+// policies คือรายชื่อ policy ที่เลือกได้ผ่าน -policy
+var policies = map[string]func(rng *rand.Rand) Policy{
+	"greedy": func(rng *rand.Rand) Policy { return &greedyPolicy{} },
+	"random": func(rng *rand.Rand) Policy { return &randomPolicy{rng: rng} },
+}
+
+func newPolicy(name string, rng *rand.Rand) (Policy, error) {
+	factory, ok := policies[name]
+	if !ok {
+		names := make([]string, 0, len(policies))
+		for n := range policies {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown policy %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return factory(rng), nil
+}
+
+// ==================== Greedy ====================
+
+// greedyPolicy ร่ายเวทที่มีดาเมจพื้นฐานต่อ AP สูงสุดใส่ศัตรูที่ HP น้อยที่สุด จบเทิร์นเมื่อไม่มีเวทโจมตีให้ร่าย
+type greedyPolicy struct{}
+
+func (p *greedyPolicy) Name() string { return "greedy" }
+
+func (p *greedyPolicy) ChooseAction(state *TurnState) combat.PerformActionRequest {
+	if len(state.Enemies) == 0 {
+		return endTurnAction()
+	}
+
+	var best *domain.Spell
+	bestScore := 0.0
+	for _, spell := range state.Castable {
+		if spell.TargetType != domain.TargetTypeEnemy || spell.APCost <= 0 {
+			continue
+		}
+		score := baseDamage(spell) / float64(spell.APCost)
+		if score > bestScore {
+			best, bestScore = spell, score
+		}
+	}
+	if best == nil {
+		return endTurnAction()
+	}
+
+	target := state.Enemies[0]
+	for _, enemy := range state.Enemies[1:] {
+		if enemy.CurrentHP < target.CurrentHP {
+			target = enemy
+		}
+	}
+	return castAction(best, target)
+}
+
+// ==================== Random ====================
+
+// randomPolicy สุ่มเวทที่ร่ายได้ 1 ท่า (เป้าหมายศัตรูสุ่ม) จบเทิร์นเมื่อไม่มีเวทให้ร่าย
+type randomPolicy struct {
+	rng *rand.Rand
+}
+
+func (p *randomPolicy) Name() string { return "random" }
+
+func (p *randomPolicy) ChooseAction(state *TurnState) combat.PerformActionRequest {
+	if len(state.Castable) == 0 || len(state.Enemies) == 0 {
+		return endTurnAction()
+	}
+	spell := state.Castable[p.rng.Intn(len(state.Castable))]
+	if spell.TargetType == domain.TargetTypeSelf {
+		return castAction(spell, state.Self)
+	}
+	return castAction(spell, state.Enemies[p.rng.Intn(len(state.Enemies))])
+}
+
+// ==================== Helpers ====================
+
+// baseDamage รวม BaseValue ของ effect ประเภท DAMAGE ในเวท
+func baseDamage(spell *domain.Spell) float64 {
+	total := 0.0
+	for _, spellEffect := range spell.Effects {
+		if spellEffect.Effect != nil && spellEffect.Effect.Type == domain.EffectTypeDamage {
+			total += spellEffect.BaseValue
+		}
+	}
+	return total
+}
+
+// isCastable ตรวจ AP/MP/Element Charge แบบเดียวกับ PrepareAndValidateCast (โหมด INSTANT)
+func isCastable(spell *domain.Spell, caster *domain.Combatant) bool {
+	if spell.TargetType != domain.TargetTypeSelf && spell.TargetType != domain.TargetTypeEnemy {
+		return false
+	}
+	if caster.CurrentAP < spell.APCost || caster.CurrentMP < spell.MPCost {
+		return false
+	}
+	if spell.ElementID <= 4 {
+		return true // T0 ไม่ใช้ Charge
+	}
+	for _, charge := range caster.Deck {
+		if charge.ElementID == spell.ElementID && !charge.IsConsumed {
+			return true
+		}
+	}
+	return false
+}
+
+func endTurnAction() combat.PerformActionRequest {
+	return combat.PerformActionRequest{ActionType: "END_TURN"}
+}
+
+func castAction(spell *domain.Spell, target *domain.Combatant) combat.PerformActionRequest {
+	spellID := spell.ID
+	targetID := target.ID.String()
+	return combat.PerformActionRequest{
+		ActionType: "CAST_SPELL",
+		CastMode:   "INSTANT",
+		SpellID:    &spellID,
+		TargetID:   &targetID,
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sage-of-elements-backend/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ==================== Report ====================
+
+type Report struct {
+	Build           *CharacterBuild   `json:"build"`
+	Policy          string            `json:"policy"`
+	MatchesPerRun   int               `json:"matches_per_scenario"`
+	MaxRounds       int               `json:"max_rounds"`
+	ConfigOverrides map[string]string `json:"config_overrides,omitempty"`
+	Scenarios       []ScenarioReport  `json:"scenarios"`
+}
+
+type ScenarioReport struct {
+	Enemies        []string      `json:"enemies"`
+	Matches        int           `json:"matches"`
+	Wins           int           `json:"wins"`
+	Losses         int           `json:"losses"`
+	Timeouts       int           `json:"timeouts"`
+	Stalled        int           `json:"stalled"`
+	StalledReason  string        `json:"stalled_reason,omitempty"`
+	WinRate        float64       `json:"win_rate"`
+	AvgRoundsToWin float64       `json:"avg_rounds_to_kill"` // เฉลี่ยเฉพาะ match ที่ชนะ
+	AvgHPLeftOnWin float64       `json:"avg_hp_left_on_win"`
+	MultiCastRate  float64       `json:"multi_cast_rate"` // Multi-Cast ต่อการร่าย 1 ครั้ง (ทุกเวทรวมกัน)
+	Spells         []SpellReport `json:"spells"`
+}
+
+type SpellReport struct {
+	SpellID       uint    `json:"spell_id"`
+	Name          string  `json:"name"`
+	Casts         int     `json:"casts"`
+	MultiCasts    int     `json:"multi_casts"`
+	MultiCastRate float64 `json:"multi_cast_rate"`
+	APSpent       int     `json:"ap_spent"`
+	Damage        int     `json:"damage"` // ดาเมจโดยตรง (รวม Multi-Cast, ไม่รวม DoT)
+	DamagePerAP   float64 `json:"damage_per_ap"`
+}
+
+// newScenarioReport สรุป scenarioStats เป็นตัวเลขเฉลี่ย/อัตราส่วน
+func newScenarioReport(stats *scenarioStats, enemies map[uint]*domain.Enemy, spellNames map[uint]string) ScenarioReport {
+	report := ScenarioReport{
+		Wins:          stats.outcomes[outcomeWin],
+		Losses:        stats.outcomes[outcomeLoss],
+		Timeouts:      stats.outcomes[outcomeTimeout],
+		Stalled:       stats.outcomes[outcomeStalled],
+		StalledReason: stats.stalledReason,
+	}
+	report.Matches = report.Wins + report.Losses + report.Timeouts + report.Stalled
+	for _, enemyID := range stats.enemyIDs {
+		name := fmt.Sprintf("#%d", enemyID)
+		if e, ok := enemies[enemyID]; ok {
+			name = e.Name
+		}
+		report.Enemies = append(report.Enemies, name)
+	}
+	report.WinRate = ratio(report.Wins, report.Matches)
+	report.AvgRoundsToWin = ratio(stats.roundsOnWin, report.Wins)
+	report.AvgHPLeftOnWin = ratio(stats.hpLeftOnWin, report.Wins)
+
+	totalCasts, totalMultiCasts := 0, 0
+	for spellID, s := range stats.spells {
+		totalCasts += s.casts
+		totalMultiCasts += s.multiCasts
+		report.Spells = append(report.Spells, SpellReport{
+			SpellID:       spellID,
+			Name:          spellNames[spellID],
+			Casts:         s.casts,
+			MultiCasts:    s.multiCasts,
+			MultiCastRate: ratio(s.multiCasts, s.casts),
+			APSpent:       s.apSpent,
+			Damage:        s.damage,
+			DamagePerAP:   ratio(s.damage, s.apSpent),
+		})
+	}
+	sort.Slice(report.Spells, func(i, j int) bool { return report.Spells[i].SpellID < report.Spells[j].SpellID })
+	report.MultiCastRate = ratio(totalMultiCasts, totalCasts)
+	return report
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// ==================== Writers ====================
+
+func writeReport(w io.Writer, format string, report *Report) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "csv":
+		return writeCSV(w, report)
+	default:
+		return fmt.Errorf("unknown format %q (expected json or csv)", format)
+	}
+}
+
+// writeCSV เขียน 1 แถวต่อ (scenario, เวท) โดยคอลัมน์ของ scenario ซ้ำในทุกแถว (ง่ายต่อการ pivot ใน spreadsheet)
+// scenario ที่ไม่มีการร่ายเวทเลยจะได้ 1 แถวที่คอลัมน์เวทว่าง
+func writeCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	header := []string{
+		"build", "policy", "enemies", "matches", "wins", "losses", "timeouts", "stalled",
+		"win_rate", "avg_rounds_to_kill", "avg_hp_left_on_win", "multi_cast_rate",
+		"spell_id", "spell_name", "casts", "multi_casts", "spell_multi_cast_rate", "ap_spent", "damage", "damage_per_ap",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, scenario := range report.Scenarios {
+		scenarioCols := []string{
+			report.Build.Name,
+			report.Policy,
+			strings.Join(scenario.Enemies, "+"),
+			strconv.Itoa(scenario.Matches),
+			strconv.Itoa(scenario.Wins),
+			strconv.Itoa(scenario.Losses),
+			strconv.Itoa(scenario.Timeouts),
+			strconv.Itoa(scenario.Stalled),
+			formatFloat(scenario.WinRate),
+			formatFloat(scenario.AvgRoundsToWin),
+			formatFloat(scenario.AvgHPLeftOnWin),
+			formatFloat(scenario.MultiCastRate),
+		}
+		if len(scenario.Spells) == 0 {
+			if err := writer.Write(append(scenarioCols, "", "", "", "", "", "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, spell := range scenario.Spells {
+			row := append(append([]string{}, scenarioCols...),
+				strconv.FormatUint(uint64(spell.SpellID), 10),
+				spell.Name,
+				strconv.Itoa(spell.Casts),
+				strconv.Itoa(spell.MultiCasts),
+				formatFloat(spell.MultiCastRate),
+				strconv.Itoa(spell.APSpent),
+				strconv.Itoa(spell.Damage),
+				formatFloat(spell.DamagePerAP),
+			)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/combat"
+	"sort"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== Simulation Runner ====================
+// runner เล่น match ผ่าน CombatService จริง (CreateMatch → PerformAction วนไปจนจบ)
+// แล้วเก็บสถิติจาก match และ CombatEvent ที่ได้จากแต่ละ action
+
+// matchOutcome คือผลของ match 1 ครั้งในมุมของ simulator
+type matchOutcome string
+
+const (
+	outcomeWin     matchOutcome = "WIN"
+	outcomeLoss    matchOutcome = "LOSS"
+	outcomeTimeout matchOutcome = "TIMEOUT" // เกิน -max-rounds
+	outcomeStalled matchOutcome = "STALLED" // ไม่ได้เทิร์นผู้เล่น หรือจบเทิร์นไม่ได้
+)
+
+type runner struct {
+	combatSvc   combat.CombatService
+	policy      Policy
+	characterID uint
+	deckID      *uint
+	spells      []*domain.Spell // เวททั้งหมดที่ build นี้ resolve ได้
+	maxRounds   int
+}
+
+// scenarioStats คือสถิติสะสมของ scenario เดียว (ศัตรูชุดเดียว)
+type scenarioStats struct {
+	enemyIDs      []uint
+	outcomes      map[matchOutcome]int
+	roundsOnWin   int
+	hpLeftOnWin   int
+	spells        map[uint]*spellStats
+	stalledReason string
+}
+
+type spellStats struct {
+	casts      int
+	multiCasts int
+	apSpent    int
+	damage     int
+}
+
+func newScenarioStats(enemyIDs []uint) *scenarioStats {
+	return &scenarioStats{
+		enemyIDs: enemyIDs,
+		outcomes: make(map[matchOutcome]int),
+		spells:   make(map[uint]*spellStats),
+	}
+}
+
+func (st *scenarioStats) spell(spellID uint) *spellStats {
+	stats, ok := st.spells[spellID]
+	if !ok {
+		stats = &spellStats{}
+		st.spells[spellID] = stats
+	}
+	return stats
+}
+
+// runScenario เล่น match ซ้ำ n ครั้งกับศัตรูชุดเดียวกัน
+func (r *runner) runScenario(enemyIDs []uint, n int) (*scenarioStats, error) {
+	stats := newScenarioStats(enemyIDs)
+	for i := 0; i < n; i++ {
+		if err := r.runMatch(stats); err != nil {
+			return nil, fmt.Errorf("match %d against enemies %v: %w", i+1, enemyIDs, err)
+		}
+	}
+	return stats, nil
+}
+
+// runMatch เล่น match 1 ครั้งจนจบ (หรือจนเกิน maxRounds) แล้วบันทึกผลลง stats
+func (r *runner) runMatch(stats *scenarioStats) error {
+	trainingEnemies := make([]combat.TrainingEnemyInput, 0, len(stats.enemyIDs))
+	for _, enemyID := range stats.enemyIDs {
+		trainingEnemies = append(trainingEnemies, combat.TrainingEnemyInput{EnemyID: enemyID})
+	}
+	match, err := r.combatSvc.CreateMatch(simPlayerID, combat.CreateMatchRequest{
+		CharacterID:     r.characterID,
+		MatchType:       string(domain.MatchTypeTraining),
+		TrainingEnemies: trainingEnemies,
+		DeckID:          r.deckID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for match.Status == domain.MatchInProgress {
+		self := findCharacterCombatant(match)
+		if self == nil || match.CurrentTurn != self.ID {
+			stats.stalledReason = "the turn did not come back to the player after the AI turns"
+			return r.finish(stats, match, outcomeStalled)
+		}
+		if match.TurnNumber > r.maxRounds {
+			return r.finish(stats, match, outcomeTimeout)
+		}
+
+		req := r.policy.ChooseAction(r.turnState(match, self))
+		resp, err := r.combatSvc.PerformAction(simPlayerID, match.ID.String(), req)
+		if err != nil && req.ActionType != "END_TURN" {
+			// action ที่ policy เลือกใช้ไม่ได้ → จบเทิร์นแทน
+			req = endTurnAction()
+			resp, err = r.combatSvc.PerformAction(simPlayerID, match.ID.String(), req)
+		}
+		if err != nil {
+			stats.stalledReason = fmt.Sprintf("END_TURN was rejected: %v", err)
+			return r.finish(stats, match, outcomeStalled)
+		}
+
+		r.collectEvents(stats, self.ID, resp.Events)
+		match = resp.UpdatedMatch
+	}
+
+	outcome := outcomeLoss
+	if self := findCharacterCombatant(match); self != nil && self.CurrentHP > 0 {
+		outcome = outcomeWin
+		stats.roundsOnWin += match.TurnNumber
+		stats.hpLeftOnWin += self.CurrentHP
+	}
+	return r.finish(stats, match, outcome)
+}
+
+// finish บันทึกผลลัพธ์ และ abort match ที่ยังค้าง (ตัวละครจะได้สร้าง match ใหม่ได้)
+func (r *runner) finish(stats *scenarioStats, match *domain.CombatMatch, outcome matchOutcome) error {
+	stats.outcomes[outcome]++
+	if match.Status == domain.MatchInProgress {
+		return r.combatSvc.AbortMatch(match.ID.String(), "simulation "+string(outcome))
+	}
+	return nil
+}
+
+// turnState เตรียมข้อมูลให้ policy: ศัตรูที่ยังมีชีวิต และเวทที่ร่ายได้ตอนนี้
+func (r *runner) turnState(match *domain.CombatMatch, self *domain.Combatant) *TurnState {
+	state := &TurnState{Match: match, Self: self}
+	for _, c := range match.Combatants {
+		if c.EnemyID != nil && c.CurrentHP > 0 {
+			state.Enemies = append(state.Enemies, c)
+		}
+	}
+	for _, spell := range r.spells {
+		if isCastable(spell, self) {
+			state.Castable = append(state.Castable, spell)
+		}
+	}
+	return state
+}
+
+// collectEvents นับการร่าย, Multi-Cast, AP ที่ใช้ และดาเมจโดยตรงของผู้เล่น แยกตามเวท
+func (r *runner) collectEvents(stats *scenarioStats, selfID uuid.UUID, events []*domain.CombatEvent) {
+	for _, event := range events {
+		if event.SourceID == nil || *event.SourceID != selfID || event.SpellID == nil {
+			continue
+		}
+		spell := stats.spell(*event.SpellID)
+		switch event.Type {
+		case domain.CombatEventCast:
+			spell.casts++
+			var details struct {
+				APCost int `json:"ap_cost"`
+			}
+			if err := json.Unmarshal(event.Details, &details); err == nil {
+				spell.apSpent += details.APCost
+			}
+		case domain.CombatEventMultiCast:
+			spell.multiCasts++
+		case domain.CombatEventDamage:
+			spell.damage += event.Value
+		}
+	}
+}
+
+// resolveBuildSpells หาเวททั้งหมดที่ build ร่ายได้: ธาตุ T0 ทั้ง 4 + ธาตุในเด็ค × ทุกศาสตร์ (ผ่าน ResolveSpell และ Fallback)
+func resolveBuildSpells(combatSvc combat.CombatService, build *CharacterBuild, masteryIDs []uint) []*domain.Spell {
+	elementIDs := []uint{1, 2, 3, 4}
+	seenElements := map[uint]bool{1: true, 2: true, 3: true, 4: true}
+	for _, elementID := range build.Deck {
+		if !seenElements[elementID] {
+			seenElements[elementID] = true
+			elementIDs = append(elementIDs, elementID)
+		}
+	}
+
+	seenSpells := make(map[uint]bool)
+	var spells []*domain.Spell
+	for _, elementID := range elementIDs {
+		for _, masteryID := range masteryIDs {
+			spell, err := combatSvc.ResolveSpell(elementID, masteryID, build.PrimaryElementID)
+			if err != nil || spell == nil || seenSpells[spell.ID] {
+				continue
+			}
+			seenSpells[spell.ID] = true
+			spells = append(spells, spell)
+		}
+	}
+	sort.Slice(spells, func(i, j int) bool { return spells[i].ID < spells[j].ID })
+	return spells
+}
+
+func findCharacterCombatant(match *domain.CombatMatch) *domain.Combatant {
+	for _, c := range match.Combatants {
+		if c.CharacterID != nil {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// file: internal/adapters/cache/redis/matchmaking_queue.go
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sage-of-elements-backend/internal/modules/pvp"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const matchmakingQueueKey = "pvp:queue:v1"            // Hash: characterID → QueueEntry (JSON)
+const matchmakingMatchedKeyPrefix = "pvp:matched:v1:" // String: matchID (มี TTL)
+
+// claimPairScript เอาตัวละครสองตัวออกจากคิวพร้อมกัน ถ้ายังอยู่ในคิวทั้งคู่ (กันหลาย instance จับคู่ซ้ำ)
+var claimPairScript = redis.NewScript(`
+if redis.call("HEXISTS", KEYS[1], ARGV[1]) == 1 and redis.call("HEXISTS", KEYS[1], ARGV[2]) == 1 then
+	redis.call("HDEL", KEYS[1], ARGV[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// MatchmakingQueue คือคิว Ranked PvP ที่เก็บใน Redis (ใช้ร่วมกันได้หลาย instance)
+type MatchmakingQueue struct {
+	client *redis.Client
+}
+
+func NewMatchmakingQueue(client *redis.Client) *MatchmakingQueue {
+	return &MatchmakingQueue{client: client}
+}
+
+func (q *MatchmakingQueue) Enqueue(entry pvp.QueueEntry) error {
+	ctx := context.Background()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return q.client.HSet(ctx, matchmakingQueueKey, characterField(entry.CharacterID), data).Err()
+}
+
+func (q *MatchmakingQueue) Remove(characterID uint) error {
+	ctx := context.Background()
+	return q.client.HDel(ctx, matchmakingQueueKey, characterField(characterID)).Err()
+}
+
+func (q *MatchmakingQueue) Find(characterID uint) (*pvp.QueueEntry, error) {
+	ctx := context.Background()
+	data, err := q.client.HGet(ctx, matchmakingQueueKey, characterField(characterID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil // ไม่อยู่ในคิว
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry pvp.QueueEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (q *MatchmakingQueue) FindAll() ([]pvp.QueueEntry, error) {
+	ctx := context.Background()
+	values, err := q.client.HGetAll(ctx, matchmakingQueueKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]pvp.QueueEntry, 0, len(values))
+	for field, data := range values {
+		var entry pvp.QueueEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("invalid queue entry %s: %w", field, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (q *MatchmakingQueue) ClaimPair(first, second uint) (bool, error) {
+	ctx := context.Background()
+	claimed, err := claimPairScript.Run(ctx, q.client, []string{matchmakingQueueKey},
+		characterField(first), characterField(second)).Int()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+func (q *MatchmakingQueue) SetMatched(characterID uint, matchID string, ttl time.Duration) error {
+	ctx := context.Background()
+	return q.client.Set(ctx, matchmakingMatchedKeyPrefix+characterField(characterID), matchID, ttl).Err()
+}
+
+func (q *MatchmakingQueue) FindMatched(characterID uint) (string, error) {
+	ctx := context.Background()
+	matchID, err := q.client.Get(ctx, matchmakingMatchedKeyPrefix+characterField(characterID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return matchID, err
+}
+
+func (q *MatchmakingQueue) ClearMatched(characterID uint) error {
+	ctx := context.Background()
+	return q.client.Del(ctx, matchmakingMatchedKeyPrefix+characterField(characterID)).Err()
+}
+
+func characterField(characterID uint) string {
+	return strconv.FormatUint(uint64(characterID), 10)
+}
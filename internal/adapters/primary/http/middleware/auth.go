@@ -7,6 +7,7 @@ import (
 	"sage-of-elements-backend/pkg/apperrors"
 	"sage-of-elements-backend/pkg/appresponse"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,6 +16,12 @@ func AuthMiddleware(authService appauth.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// 1. ดึง Authorization Header
 		authHeader := c.Get("Authorization")
+		if authHeader == "" && websocket.IsWebSocketUpgrade(c) {
+			// WebSocket จาก browser ตั้ง header เองไม่ได้ → รับ token เดียวกันผ่าน query แทน
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			appErr := apperrors.UnauthorizedError("Authorization header is required")
 			return appresponse.Error(c, appErr)
@@ -0,0 +1,376 @@
+// file: internal/adapters/storage/memory/character_repository.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type characterRepository struct {
+	store *Store
+}
+
+// NewCharacterRepository สร้าง CharacterRepository ที่เก็บข้อมูลใน Store
+func NewCharacterRepository(store *Store) character.CharacterRepository {
+	return &characterRepository{store: store}
+}
+
+func (r *characterRepository) CheckCharacterExists(name string) (*domain.Character, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, char := range r.store.characters {
+		if char.CharacterName == name {
+			return r.store.cloneCharacter(char), nil
+		}
+	}
+	return nil, nil // ไม่พบชื่อซ้ำ, ถือว่าปกติ
+}
+
+func (r *characterRepository) Create(char *domain.Character) (*domain.Character, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if char.ID == 0 {
+		r.store.nextCharacterID++
+		char.ID = r.store.nextCharacterID
+	} else if char.ID > r.store.nextCharacterID {
+		r.store.nextCharacterID = char.ID
+	}
+	if _, exists := r.store.characters[char.ID]; exists {
+		return nil, fmt.Errorf("character with id %d already exists", char.ID)
+	}
+	applyCharacterDefaults(char)
+	r.store.putCharacter(char)
+	return char, nil
+}
+
+func (r *characterRepository) Save(char *domain.Character) (*domain.Character, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if char.ID == 0 {
+		r.store.nextCharacterID++
+		char.ID = r.store.nextCharacterID
+		applyCharacterDefaults(char)
+	}
+	r.store.putCharacter(char)
+	return char, nil
+}
+
+// SaveTalents เทียบเท่า UPDATE ... WHERE unallocated_talent_points = previousUnallocated (แก้เฉพาะฟิลด์พรสวรรค์)
+func (r *characterRepository) SaveTalents(char *domain.Character, previousUnallocated int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.characters[char.ID]
+	if !ok || stored.UnallocatedTalentPoints != previousUnallocated {
+		return character.ErrTalentConflict
+	}
+	stored.TalentS, stored.TalentL, stored.TalentG, stored.TalentP = char.TalentS, char.TalentL, char.TalentG, char.TalentP
+	stored.UnallocatedTalentPoints = char.UnallocatedTalentPoints
+	stored.CurrentHP, stored.CurrentMP = char.CurrentHP, char.CurrentMP
+	stored.LastRespecAt = char.LastRespecAt
+	return nil
+}
+
+func (r *characterRepository) FindAllByPlayerID(playerID uint) ([]domain.Character, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var characters []domain.Character
+	for _, char := range r.store.characters {
+		if char.PlayerID == playerID {
+			characters = append(characters, *r.store.cloneCharacter(char))
+		}
+	}
+	sort.Slice(characters, func(i, j int) bool { return characters[i].ID < characters[j].ID })
+	return characters, nil
+}
+
+func (r *characterRepository) FindByID(id uint) (*domain.Character, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	char, ok := r.store.characters[id]
+	if !ok {
+		return nil, nil // ไม่พบข้อมูล
+	}
+	return r.store.cloneCharacter(char), nil
+}
+
+func (r *characterRepository) Delete(characterID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.characters, characterID)
+	delete(r.store.inventories, characterID)
+	delete(r.store.spellUnlocks, characterID)
+	return nil
+}
+
+func (r *characterRepository) FindInventoryByCharacterID(characterID uint) ([]*domain.DimensionalSealInventory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	inventory := make([]*domain.DimensionalSealInventory, 0, len(r.store.inventories[characterID]))
+	for _, item := range r.store.inventories[characterID] {
+		itemCopy := *item
+		inventory = append(inventory, &itemCopy)
+	}
+	return inventory, nil
+}
+
+// UpdateCharacterInTx ไม่มี transaction จริงใน memory store (tx ถูกละไว้)
+func (r *characterRepository) UpdateCharacterInTx(tx *gorm.DB, char *domain.Character) error {
+	_, err := r.Save(char)
+	return err
+}
+
+// ConsumeAndUpdateInventoryInTx ตรวจ/หัก/เพิ่มของในคลังแบบ all-or-nothing (tx ถูกละไว้)
+func (r *characterRepository) ConsumeAndUpdateInventoryInTx(tx *gorm.DB, characterID uint, itemsToConsume map[uint]int, itemsToAdd map[uint]int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.updateInventory(characterID, itemsToConsume, itemsToAdd)
+}
+
+// updateInventory คือ logic ของ ConsumeAndUpdateInventoryInTx (ผู้เรียกต้องถือ s.mu อยู่แล้ว)
+func (s *Store) updateInventory(characterID uint, itemsToConsume map[uint]int, itemsToAdd map[uint]int) error {
+	// 1. ทำงานบนสำเนา เพื่อไม่ให้ข้อมูลเสียหายถ้าของไม่พอ
+	inventoryMap := make(map[uint]*domain.DimensionalSealInventory)
+	var updated []*domain.DimensionalSealInventory
+	for _, item := range s.inventories[characterID] {
+		itemCopy := *item
+		inventoryMap[item.ElementID] = &itemCopy
+		updated = append(updated, &itemCopy)
+	}
+
+	// 2. ตรวจสอบและหัก "ของที่ต้องใช้" (Consume)
+	for elementID, quantityNeeded := range itemsToConsume {
+		item, ok := inventoryMap[elementID]
+		if !ok || item.Quantity < quantityNeeded {
+			return fmt.Errorf("insufficient ingredient: element_id %d", elementID)
+		}
+		item.Quantity -= quantityNeeded
+	}
+
+	// 3. เพิ่ม "ของที่ได้" (Add)
+	for elementID, quantityGained := range itemsToAdd {
+		if item, ok := inventoryMap[elementID]; ok {
+			item.Quantity += quantityGained
+			continue
+		}
+		s.nextInventoryID++
+		newItem := &domain.DimensionalSealInventory{
+			ID:          s.nextInventoryID,
+			CharacterID: characterID,
+			ElementID:   elementID,
+			Quantity:    quantityGained,
+			ItemType:    domain.ItemTypeNormal,
+		}
+		inventoryMap[elementID] = newItem
+		updated = append(updated, newItem)
+	}
+
+	// 4. เก็บเฉพาะของที่เหลือมากกว่า 0
+	remaining := updated[:0]
+	for _, item := range updated {
+		if item.Quantity > 0 {
+			remaining = append(remaining, item)
+		}
+	}
+	s.inventories[characterID] = remaining
+	return nil
+}
+
+// RegenerateStats ฟื้นฟู MP ตามเวลาที่ผ่านไป (สูตรเดียวกับ postgres repository)
+func (r *characterRepository) RegenerateStats(char *domain.Character, gameDataRepo game_data.GameDataRepository) (*domain.Character, error) {
+	minutesPassed := int(time.Since(char.StatsUpdatedAt).Minutes())
+	if minutesPassed <= 0 {
+		return char, nil
+	}
+
+	baseMpStr, _ := gameDataRepo.GetGameConfigValue("STAT_MP_BASE")
+	mpPerTalentLStr, _ := gameDataRepo.GetGameConfigValue("STAT_MP_PER_TALENT_L")
+	baseMp, _ := strconv.Atoi(baseMpStr)
+	mpPerTalentL, _ := strconv.Atoi(mpPerTalentLStr)
+
+	maxMP := baseMp + (char.TalentL * mpPerTalentL)
+	newMP := char.CurrentMP + minutesPassed*5 // สมมติว่า 5 MP/นาที
+	if newMP > maxMP {
+		newMP = maxMP
+	}
+	if newMP == char.CurrentMP {
+		return char, nil
+	}
+
+	char.CurrentMP = newMP
+	char.StatsUpdatedAt = time.Now()
+	return r.Save(char)
+}
+
+// applyCharacterDefaults เติมค่าเริ่มต้นตอน INSERT แบบเดียวกับ tag default/autoCreateTime ของ GORM
+func applyCharacterDefaults(char *domain.Character) {
+	if char.Level == 0 {
+		char.Level = 1
+	}
+	if char.TutorialStep == 0 {
+		char.TutorialStep = 1
+	}
+	if char.StatsUpdatedAt.IsZero() {
+		char.StatsUpdatedAt = time.Now()
+	}
+}
+
+// putCharacter เก็บสำเนาของตัวละคร (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) putCharacter(char *domain.Character) {
+	stored := *char
+	stored.PrimaryElement = nil
+	stored.Masteries = make([]*domain.CharacterMastery, 0, len(char.Masteries))
+	for _, m := range char.Masteries {
+		mastery := *m
+		mastery.CharacterID = char.ID
+		mastery.Mastery = nil
+		stored.Masteries = append(stored.Masteries, &mastery)
+	}
+	stored.DimensionalSeal = nil
+	stored.JournalDiscoveries = nil
+	stored.SpellUnlocks = nil
+	s.characters[char.ID] = &stored
+
+	// Create พร้อมของในคลัง (เหมือน GORM สร้าง association ให้)
+	if len(char.DimensionalSeal) > 0 && len(s.inventories[char.ID]) == 0 {
+		for _, item := range char.DimensionalSeal {
+			s.nextInventoryID++
+			itemCopy := *item
+			itemCopy.ID = s.nextInventoryID
+			itemCopy.CharacterID = char.ID
+			itemCopy.Element = nil
+			s.inventories[char.ID] = append(s.inventories[char.ID], &itemCopy)
+		}
+	}
+}
+
+// FindMatchResults คืนประวัติการต่อสู้ล่าสุดก่อน (เทียบเท่า ORDER BY finished_at DESC + LIMIT/OFFSET)
+func (r *characterRepository) FindMatchResults(characterID uint, filter character.MatchHistoryFilter) ([]domain.MatchResult, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var matched []domain.MatchResult
+	for _, result := range r.store.matchResults {
+		if result.CharacterID != characterID {
+			continue
+		}
+		if filter.MatchType != nil && result.MatchType != *filter.MatchType {
+			continue
+		}
+		if filter.From != nil && result.FinishedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !result.FinishedAt.Before(*filter.To) {
+			continue
+		}
+		matched = append(matched, result)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].FinishedAt.Equal(matched[j].FinishedAt) {
+			return matched[i].FinishedAt.After(matched[j].FinishedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return []domain.MatchResult{}, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+// HasActiveMatch ตรวจว่าตัวละครมี match IN_PROGRESS ที่ผู้เล่นควบคุมอยู่หรือไม่ (เงื่อนไขเดียวกับ FindPlayerActiveMatch)
+func (r *characterRepository) HasActiveMatch(characterID uint) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, match := range r.store.matches {
+		if match.Status != domain.MatchInProgress {
+			continue
+		}
+		for _, c := range match.Combatants {
+			if c.CharacterID != nil && *c.CharacterID == characterID && !c.IsAIControlled {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// putMastery เขียนทับ Level/MXP ของศาสตร์ในตัวละครที่เก็บไว้ (เพิ่มใหม่ถ้ายังไม่มี) ต้องถือ write lock ก่อนเรียก
+func (s *Store) putMastery(m *domain.CharacterMastery) {
+	stored, ok := s.characters[m.CharacterID]
+	if !ok {
+		return
+	}
+	mastery := *m
+	mastery.Mastery = nil
+	mastery.MxpToNextLevel = 0
+	for i, existing := range stored.Masteries {
+		if existing.MasteryID == mastery.MasteryID {
+			stored.Masteries[i] = &mastery
+			return
+		}
+	}
+	stored.Masteries = append(stored.Masteries, &mastery)
+}
+
+// FindSpellUnlocks คืนสำเนาเวทที่ตัวละครปลดล็อกแล้ว (เรียงตาม SpellID)
+func (r *characterRepository) FindSpellUnlocks(characterID uint) ([]*domain.CharacterSpellUnlock, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	unlocks := make([]*domain.CharacterSpellUnlock, 0, len(r.store.spellUnlocks[characterID]))
+	for _, unlock := range r.store.spellUnlocks[characterID] {
+		unlockCopy := *unlock
+		unlocks = append(unlocks, &unlockCopy)
+	}
+	sort.Slice(unlocks, func(i, j int) bool { return unlocks[i].SpellID < unlocks[j].SpellID })
+	return unlocks, nil
+}
+
+// UnlockSpells บันทึกการปลดล็อกเวท (ข้ามเวทที่ปลดล็อกไปแล้ว เหมือน ON CONFLICT DO NOTHING)
+func (r *characterRepository) UnlockSpells(characterID uint, spellIDs []uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	for _, spellID := range spellIDs {
+		exists := false
+		for _, unlock := range r.store.spellUnlocks[characterID] {
+			if unlock.SpellID == spellID {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			r.store.spellUnlocks[characterID] = append(r.store.spellUnlocks[characterID],
+				&domain.CharacterSpellUnlock{CharacterID: characterID, SpellID: spellID, UnlockedAt: now})
+		}
+	}
+	return nil
+}
+
+// FindDiscoveredElementIDs คืนธาตุผลลัพธ์ของสูตรที่ตัวละครค้นพบแล้ว (เทียบเท่า JOIN recipes)
+func (r *characterRepository) FindDiscoveredElementIDs(characterID uint) ([]uint, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var elementIDs []uint
+	seen := make(map[uint]bool)
+	for _, discovery := range r.store.discoveries[characterID] {
+		for _, recipe := range r.store.recipes {
+			if recipe.ID == discovery.RecipeID && !seen[recipe.OutputElementID] {
+				seen[recipe.OutputElementID] = true
+				elementIDs = append(elementIDs, recipe.OutputElementID)
+			}
+		}
+	}
+	return elementIDs, nil
+}
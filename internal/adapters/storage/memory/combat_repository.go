@@ -0,0 +1,267 @@
+// file: internal/adapters/storage/memory/combat_repository.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/combat"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type combatRepository struct {
+	store *Store
+}
+
+// NewCombatRepository สร้าง CombatRepository ที่เก็บ match ไว้ใน Store
+func NewCombatRepository(store *Store) combat.CombatRepository {
+	return &combatRepository{store: store}
+}
+
+// CreateMatch บันทึก Match และ Combatant ทั้งหมด แล้วคืนสำเนาที่ผูก Character/Enemy ไว้แล้ว
+func (r *combatRepository) CreateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.matches[match.ID]; exists {
+		return nil, fmt.Errorf("match %s already exists", match.ID)
+	}
+
+	now := time.Now()
+	match.CreatedAt = now
+	match.UpdatedAt = now
+	for _, c := range match.Combatants {
+		c.MatchID = match.ID
+	}
+
+	r.store.matches[match.ID] = r.store.cloneMatch(match)
+	r.store.matchOrder = append(r.store.matchOrder, match.ID)
+	r.store.appendPending(match)
+
+	return r.store.cloneMatch(r.store.matches[match.ID]), nil
+}
+
+func (r *combatRepository) FindMatchByID(matchID string) (*domain.CombatMatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	match, err := r.store.findMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.cloneMatch(match), nil
+}
+
+// UpdateMatch บันทึกสถานะล่าสุดของ Match พร้อม Action/Event ที่ค้างอยู่
+// match ที่โหลดไปก่อนมีคนบันทึก (Version ไม่ตรง) → ErrMatchConflict โดยไม่บันทึกอะไร (รางวัลไม่ถูกให้ซ้ำ)
+func (r *combatRepository) UpdateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error) {
+	r.store.mu.Lock()
+	stored, exists := r.store.matches[match.ID]
+	if !exists {
+		r.store.mu.Unlock()
+		return nil, fmt.Errorf("match %s not found", match.ID)
+	}
+	if stored.Version != match.Version {
+		r.store.mu.Unlock()
+		return nil, combat.ErrMatchConflict
+	}
+	match.Version++
+	match.UpdatedAt = time.Now()
+	r.store.matches[match.ID] = r.store.cloneMatch(match)
+	r.store.appendPending(match)
+	r.store.mu.Unlock()
+
+	return r.FindMatchByID(match.ID.String())
+}
+
+func (r *combatRepository) FindActionsByMatchID(matchID string) ([]*domain.CombatAction, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	id, err := uuid.FromString(matchID)
+	if err != nil {
+		return nil, err
+	}
+	actions := make([]*domain.CombatAction, 0, len(r.store.actions[id]))
+	for _, action := range r.store.actions[id] {
+		actionCopy := *action
+		actions = append(actions, &actionCopy)
+	}
+	return actions, nil
+}
+
+func (r *combatRepository) FindEventsByMatchID(matchID string) ([]*domain.CombatEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	id, err := uuid.FromString(matchID)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*domain.CombatEvent, 0, len(r.store.events[id]))
+	for _, event := range r.store.events[id] {
+		eventCopy := *event
+		events = append(events, &eventCopy)
+	}
+	return events, nil
+}
+
+// ==================== Cleanup Methods ====================
+
+func (r *combatRepository) FindStaleMatches(inactiveMinutes int) ([]*domain.CombatMatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var matches []*domain.CombatMatch
+	for _, match := range r.store.staleMatches(inactiveMinutes) {
+		matches = append(matches, r.store.cloneMatch(match))
+	}
+	return matches, nil
+}
+
+func (r *combatRepository) AbortStaleMatches(inactiveMinutes int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stale := r.store.staleMatches(inactiveMinutes)
+	for _, match := range stale {
+		abortMatch(match)
+	}
+	return int64(len(stale)), nil
+}
+
+func (r *combatRepository) FindPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, id := range r.store.matchOrder {
+		match := r.store.matches[id]
+		if match.Status != domain.MatchInProgress {
+			continue
+		}
+		for _, c := range match.Combatants {
+			if c.CharacterID != nil && *c.CharacterID == characterID && !c.IsAIControlled {
+				return r.store.cloneMatch(match), nil
+			}
+		}
+	}
+	return nil, nil // ไม่มี active match = OK
+}
+
+func (r *combatRepository) AbortMatchByID(matchID string, reason string) (*domain.CombatMatch, error) {
+	r.store.mu.Lock()
+	match, err := r.store.findMatch(matchID)
+	if err != nil {
+		r.store.mu.Unlock()
+		return nil, err
+	}
+	if match.Status != domain.MatchInProgress {
+		r.store.mu.Unlock()
+		return r.FindMatchByID(matchID) // จบแล้ว ไม่ต้องทำอะไร
+	}
+	abortMatch(match)
+	r.store.mu.Unlock()
+
+	return r.FindMatchByID(matchID)
+}
+
+// ==================== Turn Timer ====================
+
+func (r *combatRepository) FindTimedOutMatches(now time.Time) ([]*domain.CombatMatch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var matches []*domain.CombatMatch
+	for _, id := range r.store.matchOrder {
+		match := r.store.matches[id]
+		if match.Status != domain.MatchInProgress {
+			continue
+		}
+		turnExpired := match.TurnDeadline != nil && match.TurnDeadline.Before(now)
+		matchExpired := match.MatchDeadline != nil && match.MatchDeadline.Before(now)
+		if turnExpired || matchExpired || hasDisconnectExpired(match, now) {
+			matches = append(matches, r.store.cloneMatch(match))
+		}
+	}
+	return matches, nil
+}
+
+// ==================== Helpers (ต้องถือ lock ก่อนเรียก) ====================
+
+func hasDisconnectExpired(match *domain.CombatMatch, now time.Time) bool {
+	for _, c := range match.Combatants {
+		if c.DisconnectDeadline != nil && c.DisconnectDeadline.Before(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) findMatch(matchID string) (*domain.CombatMatch, error) {
+	id, err := uuid.FromString(matchID)
+	if err != nil {
+		return nil, err
+	}
+	match, ok := s.matches[id]
+	if !ok {
+		return nil, fmt.Errorf("match %s not found", matchID)
+	}
+	return match, nil
+}
+
+func (s *Store) staleMatches(inactiveMinutes int) []*domain.CombatMatch {
+	cutoff := time.Now().Add(-time.Duration(inactiveMinutes) * time.Minute)
+	var stale []*domain.CombatMatch
+	for _, id := range s.matchOrder {
+		match := s.matches[id]
+		if match.Status == domain.MatchInProgress && match.UpdatedAt.Before(cutoff) {
+			stale = append(stale, match)
+		}
+	}
+	return stale
+}
+
+// appendPending ย้าย Action/Event ที่ค้างอยู่ใน match ไปเก็บใน Store
+func (s *Store) appendPending(match *domain.CombatMatch) {
+	for _, action := range match.PendingActions {
+		actionCopy := *action
+		s.actions[match.ID] = append(s.actions[match.ID], &actionCopy)
+	}
+	for _, event := range match.PendingEvents {
+		eventCopy := *event
+		s.events[match.ID] = append(s.events[match.ID], &eventCopy)
+	}
+	for _, result := range match.PendingResults {
+		s.nextMatchResultID++
+		result.ID = s.nextMatchResultID
+		s.matchResults = append(s.matchResults, *result)
+	}
+	for _, drop := range match.PendingLoot {
+		// เพิ่มอย่างเดียว (ไม่มีการหัก) จึงไม่มีทางล้มเหลว
+		_ = s.updateInventory(drop.CharacterID, nil, map[uint]int{drop.ElementID: drop.Quantity})
+	}
+	for _, char := range match.PendingCharacters {
+		// เทียบเท่า Omit(clause.Associations): ศาสตร์ที่เก็บไว้ไม่ถูกทับด้วยข้อมูลเก่าของ match
+		stored, ok := s.characters[char.ID]
+		if !ok {
+			continue
+		}
+		masteries := stored.Masteries
+		s.putCharacter(char)
+		s.characters[char.ID].Masteries = masteries
+	}
+	for _, mastery := range match.PendingMasteries {
+		s.putMastery(mastery)
+	}
+	for _, progress := range match.PendingStageProgress {
+		s.putStageProgress(progress)
+	}
+	for _, rating := range match.PendingRatings {
+		s.putPvpRating(rating)
+	}
+	for _, history := range match.PendingRatingHistory {
+		s.appendPvpHistory(history)
+	}
+}
+
+func abortMatch(match *domain.CombatMatch) {
+	now := time.Now()
+	match.Status = domain.MatchAborted
+	match.FinishedAt = &now
+	match.UpdatedAt = now
+	match.Version++
+}
@@ -0,0 +1,138 @@
+// file: internal/adapters/storage/memory/deck_repository.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/deck"
+	"sort"
+)
+
+type deckRepository struct {
+	store *Store
+}
+
+// NewDeckRepository สร้าง DeckRepository ที่เก็บข้อมูลใน Store
+func NewDeckRepository(store *Store) deck.DeckRepository {
+	return &deckRepository{store: store}
+}
+
+func (r *deckRepository) Create(d *domain.Deck) (*domain.Deck, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if d.ID == 0 {
+		r.store.nextDeckID++
+		d.ID = r.store.nextDeckID
+	} else if d.ID > r.store.nextDeckID {
+		r.store.nextDeckID = d.ID
+	}
+	r.store.assignDeckSlotIDs(d.ID, d.Slots)
+	r.store.decks[d.ID] = cloneDeck(d)
+	return d, nil
+}
+
+func (r *deckRepository) FindByCharacterID(characterID uint) ([]domain.Deck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var decks []domain.Deck
+	for _, d := range r.store.decks {
+		if d.CharacterID == characterID {
+			decks = append(decks, *cloneDeck(d))
+		}
+	}
+	sort.Slice(decks, func(i, j int) bool { return decks[i].DisplayOrder < decks[j].DisplayOrder })
+	return decks, nil
+}
+
+func (r *deckRepository) FindByID(deckID uint) (*domain.Deck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	d, ok := r.store.decks[deckID]
+	if !ok {
+		return nil, fmt.Errorf("deck %d not found", deckID)
+	}
+	return cloneDeck(d), nil
+}
+
+func (r *deckRepository) Update(deckID uint, name string, slots []*domain.DeckSlot) (*domain.Deck, error) {
+	r.store.mu.Lock()
+	d, ok := r.store.decks[deckID]
+	if !ok {
+		r.store.mu.Unlock()
+		return nil, fmt.Errorf("deck %d not found", deckID)
+	}
+	d.Name = name
+	r.store.assignDeckSlotIDs(deckID, slots)
+	d.Slots = cloneDeck(&domain.Deck{Slots: slots}).Slots
+	r.store.mu.Unlock()
+
+	return r.FindByID(deckID)
+}
+
+func (r *deckRepository) CountByCharacterID(characterID uint) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var count int64
+	for _, d := range r.store.decks {
+		if d.CharacterID == characterID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *deckRepository) Delete(deckID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.decks, deckID)
+	return nil
+}
+
+func (r *deckRepository) SetDefenseDeck(characterID, deckID uint) (*domain.Deck, error) {
+	r.store.mu.Lock()
+	target, ok := r.store.decks[deckID]
+	if !ok || target.CharacterID != characterID {
+		r.store.mu.Unlock()
+		return nil, fmt.Errorf("deck %d not found", deckID)
+	}
+	for _, d := range r.store.decks {
+		if d.CharacterID == characterID {
+			d.IsDefense = d.ID == deckID
+		}
+	}
+	r.store.mu.Unlock()
+
+	return r.FindByID(deckID)
+}
+
+func (r *deckRepository) FindDefenseDeck(characterID uint) (*domain.Deck, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, d := range r.store.decks {
+		if d.CharacterID == characterID && d.IsDefense {
+			return cloneDeck(d), nil
+		}
+	}
+	return nil, nil
+}
+
+// assignDeckSlotIDs เติม ID/DeckID ให้ slot ใหม่ (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) assignDeckSlotIDs(deckID uint, slots []*domain.DeckSlot) {
+	for _, slot := range slots {
+		slot.DeckID = deckID
+		if slot.ID == 0 {
+			s.nextDeckSlotID++
+			slot.ID = s.nextDeckSlotID
+		}
+	}
+}
+
+func cloneDeck(src *domain.Deck) *domain.Deck {
+	d := *src
+	d.Slots = make([]*domain.DeckSlot, 0, len(src.Slots))
+	for _, slot := range src.Slots {
+		slotCopy := *slot
+		d.Slots = append(d.Slots, &slotCopy)
+	}
+	return &d
+}
@@ -0,0 +1,42 @@
+// file: internal/adapters/storage/memory/enemy_repository.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/enemy"
+	"sort"
+)
+
+type enemyRepository struct {
+	store *Store
+}
+
+// NewEnemyRepository สร้าง EnemyRepository ที่อ่านจาก Store
+func NewEnemyRepository(store *Store) enemy.EnemyRepository {
+	return &enemyRepository{store: store}
+}
+
+// FindByID คืนศัตรูพร้อม Element, Abilities และ AI (ข้อมูล Master Data ห้ามแก้ไข)
+func (r *enemyRepository) FindByID(id uint) (*domain.Enemy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	e, ok := r.store.enemies[id]
+	if !ok {
+		return nil, nil
+	}
+	return e, nil
+}
+
+func (r *enemyRepository) FindAll() ([]domain.Enemy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	enemies := make([]domain.Enemy, 0, len(r.store.enemies))
+	for _, e := range r.store.enemies {
+		enemyCopy := *e
+		enemyCopy.Abilities = nil
+		enemyCopy.AI = nil
+		enemies = append(enemies, enemyCopy)
+	}
+	sort.Slice(enemies, func(i, j int) bool { return enemies[i].ID < enemies[j].ID })
+	return enemies, nil
+}
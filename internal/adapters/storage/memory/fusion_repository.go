@@ -0,0 +1,73 @@
+// file: internal/adapters/storage/memory/fusion_repository.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/fusion"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fusionRepository struct {
+	store *Store
+}
+
+// NewFusionRepository สร้าง FusionRepository ที่อ่านสูตรและบันทึกการค้นพบใน Store
+func NewFusionRepository(store *Store) fusion.FusionRepository {
+	return &fusionRepository{store: store}
+}
+
+// FindRecipeByIngredients หาสูตรที่ส่วนประกอบตรงกับที่ส่งมาเป๊ะๆ (ทั้งชนิดและจำนวน) เหมือน postgres repository
+func (r *fusionRepository) FindRecipeByIngredients(tx *gorm.DB, ingredients map[uint]int) (*domain.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for i := range r.store.recipes {
+		recipe := &r.store.recipes[i]
+		if len(recipe.Ingredients) != len(ingredients) {
+			continue
+		}
+		matched := true
+		for _, ingredient := range recipe.Ingredients {
+			if quantity, ok := ingredients[ingredient.InputElementID]; !ok || quantity != ingredient.Quantity {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			found := r.store.cloneRecipe(recipe)
+			return &found, nil
+		}
+	}
+	return nil, nil // ไม่เจอสูตร, ถือว่าปกติ
+}
+
+func (r *fusionRepository) IsRecipeDiscovered(characterID uint, recipeID uint) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, discovery := range r.store.discoveries[characterID] {
+		if discovery.RecipeID == recipeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LogDiscovery บันทึกการค้นพบใหม่ (ถ้ามีอยู่แล้วจะไม่สร้างซ้ำ เหมือน FirstOrCreate)
+func (r *fusionRepository) LogDiscovery(tx *gorm.DB, characterID uint, recipeID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, discovery := range r.store.discoveries[characterID] {
+		if discovery.RecipeID == recipeID {
+			return nil
+		}
+	}
+	discoveries := append([]*domain.CharacterJournalDiscovery(nil), r.store.discoveries[characterID]...)
+	r.store.discoveries[characterID] = append(discoveries, &domain.CharacterJournalDiscovery{
+		CharacterID:  characterID,
+		RecipeID:     recipeID,
+		DiscoveredAt: time.Now(),
+	})
+	return nil
+}
@@ -0,0 +1,42 @@
+// file: internal/adapters/storage/memory/game_data_cache.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sync"
+	"time"
+)
+
+// gameDataCacheRepository คือ Cache ของ Master Data ในหน่วยความจำ (ใช้แทน Redis)
+type gameDataCacheRepository struct {
+	mu        sync.RWMutex
+	data      *game_data.MasterDataResponse
+	expiresAt time.Time
+}
+
+// NewGameDataCacheRepository สร้าง CacheRepository ที่ไม่ต้องใช้ Redis
+func NewGameDataCacheRepository() game_data.CacheRepository {
+	return &gameDataCacheRepository{}
+}
+
+// GetMasterData คืน nil (Cache Miss) เมื่อยังไม่มีข้อมูลหรือหมดอายุแล้ว
+func (r *gameDataCacheRepository) GetMasterData() (*game_data.MasterDataResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.data == nil || (!r.expiresAt.IsZero() && time.Now().After(r.expiresAt)) {
+		return nil, nil
+	}
+	return r.data, nil
+}
+
+// SetMasterData เก็บข้อมูลพร้อมเวลาหมดอายุ (expiration = 0 คือไม่หมดอายุ เหมือน Redis)
+func (r *gameDataCacheRepository) SetMasterData(data *game_data.MasterDataResponse, expiration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data = data
+	r.expiresAt = time.Time{}
+	if expiration > 0 {
+		r.expiresAt = time.Now().Add(expiration)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// file: internal/adapters/storage/memory/game_data_repository.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sort"
+)
+
+type gameDataRepository struct {
+	store *Store
+}
+
+// NewGameDataRepository สร้าง GameDataRepository ที่อ่านจาก Store (ไม่มี Redis cache)
+func NewGameDataRepository(store *Store) game_data.GameDataRepository {
+	return &gameDataRepository{store: store}
+}
+
+func (r *gameDataRepository) FindAllElements() ([]domain.Element, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return append([]domain.Element(nil), r.store.elements...), nil
+}
+
+func (r *gameDataRepository) FindAllMasteries() ([]domain.Mastery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return append([]domain.Mastery(nil), r.store.masteries...), nil
+}
+
+func (r *gameDataRepository) FindAllRecipes() ([]domain.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	recipes := make([]domain.Recipe, 0, len(r.store.recipes))
+	for _, recipe := range r.store.recipes {
+		recipes = append(recipes, r.store.cloneRecipe(&recipe))
+	}
+	return recipes, nil
+}
+
+func (r *gameDataRepository) FindAllSpells() ([]domain.Spell, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	spells := make([]domain.Spell, 0, len(r.store.spells))
+	for _, spell := range r.store.spells {
+		spells = append(spells, *cloneSpell(spell))
+	}
+	sort.Slice(spells, func(i, j int) bool { return spells[i].ID < spells[j].ID })
+	return spells, nil
+}
+
+// GetGameConfigValue คืนค่าว่าง (ไม่ใช่ error) เมื่อไม่พบ key เหมือน postgres repository
+func (r *gameDataRepository) GetGameConfigValue(key string) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return r.store.configs[key], nil
+}
+
+func (r *gameDataRepository) FindAllGameConfigs() ([]domain.GameConfig, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	configs := make([]domain.GameConfig, 0, len(r.store.configs))
+	for key, value := range r.store.configs {
+		configs = append(configs, domain.GameConfig{Key: key, Value: value})
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Key < configs[j].Key })
+	return configs, nil
+}
+
+func (r *gameDataRepository) FindSpellByID(id uint) (*domain.Spell, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	spell, ok := r.store.spells[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneSpell(spell), nil
+}
+
+func (r *gameDataRepository) FindSpellByElementAndMastery(elementID uint, masteryID uint) (*domain.Spell, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var found *domain.Spell
+	for _, spell := range r.store.spells {
+		if spell.ElementID == elementID && spell.MasteryID == masteryID {
+			if found == nil || spell.ID < found.ID {
+				found = spell
+			}
+		}
+	}
+	if found == nil {
+		return nil, nil // ไม่เจอไม่ใช่ error
+	}
+	return cloneSpell(found), nil
+}
+
+func (r *gameDataRepository) FindEffectByID(id uint) (*domain.Effect, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	effect, ok := r.store.effects[id]
+	if !ok {
+		return nil, nil
+	}
+	effectCopy := *effect
+	return &effectCopy, nil
+}
+
+func (r *gameDataRepository) FindAllElementalMatchups() ([]domain.ElementalMatchup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	matchups := make([]domain.ElementalMatchup, 0, len(r.store.matchups))
+	for key, modifier := range r.store.matchups {
+		matchups = append(matchups, domain.ElementalMatchup{AttackingElementID: key[0], DefendingElementID: key[1], Modifier: modifier})
+	}
+	sort.Slice(matchups, func(i, j int) bool {
+		if matchups[i].AttackingElementID != matchups[j].AttackingElementID {
+			return matchups[i].AttackingElementID < matchups[j].AttackingElementID
+		}
+		return matchups[i].DefendingElementID < matchups[j].DefendingElementID
+	})
+	return matchups, nil
+}
+
+// GetMatchupModifier คืน "1.0" ถ้าไม่เจอกฎ (เหมือน postgres repository)
+func (r *gameDataRepository) GetMatchupModifier(attackerID, defenderID uint) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	modifier, ok := r.store.matchups[[2]uint{attackerID, defenderID}]
+	if !ok {
+		return "1.0", nil
+	}
+	return fmt.Sprintf("%f", modifier), nil
+}
+
+func (r *gameDataRepository) FindRecipeByOutputElementID(elementID uint) (*domain.Recipe, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for i := range r.store.recipes {
+		if r.store.recipes[i].OutputElementID == elementID {
+			recipe := r.store.cloneRecipe(&r.store.recipes[i])
+			return &recipe, nil
+		}
+	}
+	return nil, nil // หาไม่เจอ ไม่ใช่ Error
+}
+
+// cloneRecipe คัดลอกสูตรพร้อมส่วนประกอบ (เทียบเท่า Preload("OutputElement") + Preload("Ingredients.InputElement"))
+func (s *Store) cloneRecipe(src *domain.Recipe) domain.Recipe {
+	recipe := *src
+	recipe.OutputElement = s.findElement(src.OutputElementID)
+	recipe.Ingredients = make([]*domain.RecipeIngredient, 0, len(src.Ingredients))
+	for _, ingredient := range src.Ingredients {
+		ingredientCopy := *ingredient
+		ingredientCopy.InputElement = s.findElement(ingredient.InputElementID)
+		recipe.Ingredients = append(recipe.Ingredients, &ingredientCopy)
+	}
+	return recipe
+}
+
+// cloneSpell คัดลอกเวทพร้อม SpellEffect (Effect ที่ผูกไว้เป็น Master Data ใช้ร่วมกันได้)
+func cloneSpell(src *domain.Spell) *domain.Spell {
+	spell := *src
+	spell.Effects = make([]*domain.SpellEffect, 0, len(src.Effects))
+	for _, spellEffect := range src.Effects {
+		spellEffectCopy := *spellEffect
+		spell.Effects = append(spell.Effects, &spellEffectCopy)
+	}
+	return &spell
+}
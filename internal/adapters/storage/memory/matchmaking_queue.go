@@ -0,0 +1,102 @@
+// file: internal/adapters/storage/memory/matchmaking_queue.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/modules/pvp"
+	"sync"
+	"time"
+)
+
+type matchedResult struct {
+	matchID   string
+	expiresAt time.Time
+}
+
+// matchmakingQueue คือคิว Ranked ในหน่วยความจำ (ใช้แทน Redis, รองรับ process เดียว)
+type matchmakingQueue struct {
+	mu      sync.Mutex
+	entries map[uint]pvp.QueueEntry
+	matched map[uint]matchedResult
+}
+
+// NewMatchmakingQueue สร้าง MatchmakingQueue ที่ไม่ต้องใช้ Redis
+func NewMatchmakingQueue() pvp.MatchmakingQueue {
+	return &matchmakingQueue{
+		entries: make(map[uint]pvp.QueueEntry),
+		matched: make(map[uint]matchedResult),
+	}
+}
+
+func (q *matchmakingQueue) Enqueue(entry pvp.QueueEntry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries[entry.CharacterID] = entry
+	return nil
+}
+
+func (q *matchmakingQueue) Remove(characterID uint) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.entries, characterID)
+	return nil
+}
+
+func (q *matchmakingQueue) Find(characterID uint) (*pvp.QueueEntry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if entry, ok := q.entries[characterID]; ok {
+		return &entry, nil
+	}
+	return nil, nil
+}
+
+func (q *matchmakingQueue) FindAll() ([]pvp.QueueEntry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entries := make([]pvp.QueueEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (q *matchmakingQueue) ClaimPair(first, second uint) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, firstQueued := q.entries[first]
+	_, secondQueued := q.entries[second]
+	if !firstQueued || !secondQueued {
+		return false, nil
+	}
+	delete(q.entries, first)
+	delete(q.entries, second)
+	return true, nil
+}
+
+func (q *matchmakingQueue) SetMatched(characterID uint, matchID string, ttl time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.matched[characterID] = matchedResult{matchID: matchID, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (q *matchmakingQueue) FindMatched(characterID uint) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	result, ok := q.matched[characterID]
+	if !ok {
+		return "", nil
+	}
+	if time.Now().After(result.expiresAt) {
+		delete(q.matched, characterID)
+		return "", nil
+	}
+	return result.matchID, nil
+}
+
+func (q *matchmakingQueue) ClearMatched(characterID uint) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.matched, characterID)
+	return nil
+}
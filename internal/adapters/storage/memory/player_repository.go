@@ -0,0 +1,164 @@
+// file: internal/adapters/storage/memory/player_repository.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/player"
+	"time"
+)
+
+type playerRepository struct {
+	store *Store
+}
+
+// NewPlayerRepository สร้าง PlayerRepository ที่เก็บผู้เล่นและข้อมูลยืนยันตัวตนใน Store
+func NewPlayerRepository(store *Store) player.PlayerRepository {
+	return &playerRepository{store: store}
+}
+
+// Save บันทึกผู้เล่นใหม่ (INSERT) เหมือน postgres repository
+func (r *playerRepository) Save(p *domain.Player) (*domain.Player, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if err := r.store.insertPlayer(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *playerRepository) FindByID(id uint) (*domain.Player, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	p, ok := r.store.players[id]
+	if !ok {
+		return nil, nil // ไม่พบข้อมูล
+	}
+	return clonePlayer(p), nil
+}
+
+func (r *playerRepository) FindByUsername(username string) (*domain.Player, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, p := range r.store.players {
+		if p.Username == username {
+			return clonePlayer(p), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *playerRepository) FindByEmail(email string) (*domain.Player, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, p := range r.store.players {
+		if p.Email == email {
+			return clonePlayer(p), nil
+		}
+	}
+	return nil, nil // ไม่เจอ ถือว่าปกติ
+}
+
+func (r *playerRepository) FindAuthByPlayerIDAndProvider(playerID uint, provider string) (*domain.PlayerAuth, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, auth := range r.store.playerAuths {
+		if auth.PlayerID == playerID && auth.Provider == provider {
+			authCopy := *auth
+			return &authCopy, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateWithAuth สร้าง Player และ PlayerAuth พร้อมกัน (ถ้า auth ซ้ำจะไม่สร้างอะไรเลย)
+func (r *playerRepository) CreateWithAuth(p *domain.Player, auth *domain.PlayerAuth) (*domain.Player, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if auth.ProviderID != "" {
+		for _, existing := range r.store.playerAuths {
+			if existing.ProviderID == auth.ProviderID {
+				return nil, fmt.Errorf("player auth with provider id %q already exists", auth.ProviderID)
+			}
+		}
+	}
+	if err := r.store.insertPlayer(p); err != nil {
+		return nil, err
+	}
+
+	r.store.nextAuthID++
+	auth.ID = r.store.nextAuthID
+	auth.PlayerID = p.ID
+	authCopy := *auth
+	r.store.playerAuths[auth.ID] = &authCopy
+	return p, nil
+}
+
+func (r *playerRepository) Update(p *domain.Player) (*domain.Player, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.players[p.ID]; !ok {
+		if err := r.store.insertPlayer(p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	r.store.players[p.ID] = clonePlayer(p)
+	return p, nil
+}
+
+func (r *playerRepository) UpdateAuth(auth *domain.PlayerAuth) (*domain.PlayerAuth, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if auth.ID == 0 {
+		r.store.nextAuthID++
+		auth.ID = r.store.nextAuthID
+	}
+	authCopy := *auth
+	r.store.playerAuths[auth.ID] = &authCopy
+	return auth, nil
+}
+
+func (r *playerRepository) FindAuthByRefreshToken(token string) (*domain.PlayerAuth, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, auth := range r.store.playerAuths {
+		if auth.RefreshToken != nil && *auth.RefreshToken == token {
+			authCopy := *auth
+			return &authCopy, nil
+		}
+	}
+	return nil, nil // ไม่พบ Token นี้ในระบบ, ถือว่าปกติ
+}
+
+// insertPlayer ตรวจ unique (username/email) แล้วเติม ID/CreatedAt (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) insertPlayer(p *domain.Player) error {
+	for _, existing := range s.players {
+		if existing.Username == p.Username {
+			return fmt.Errorf("player with username %q already exists", p.Username)
+		}
+		if existing.Email == p.Email {
+			return fmt.Errorf("player with email %q already exists", p.Email)
+		}
+	}
+	if p.ID == 0 {
+		s.nextPlayerID++
+		p.ID = s.nextPlayerID
+	} else if p.ID > s.nextPlayerID {
+		s.nextPlayerID = p.ID
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	s.players[p.ID] = clonePlayer(p)
+	return nil
+}
+
+// clonePlayer คัดลอกผู้เล่นโดยไม่รวม association (postgres repository ก็ไม่ได้ Preload)
+func clonePlayer(src *domain.Player) *domain.Player {
+	p := *src
+	p.PlayerAuth = nil
+	p.Characters = nil
+	return &p
+}
@@ -0,0 +1,113 @@
+// file: internal/adapters/storage/memory/pve_repository.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/pve"
+	"sort"
+)
+
+type pveRepository struct {
+	store *Store
+}
+
+// NewPveRepository สร้าง PveRepository ที่อ่านจาก Store
+func NewPveRepository(store *Store) pve.PveRepository {
+	return &pveRepository{store: store}
+}
+
+// FindAllActiveRealms คืน Realm พร้อม Chapters/Stages เรียงตามลำดับ (เทียบเท่า Nested Preload)
+func (r *pveRepository) FindAllActiveRealms() ([]domain.Realm, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var realms []domain.Realm
+	for _, realm := range r.store.realms {
+		if !realm.IsActive {
+			continue
+		}
+		realm.Chapters = nil
+		for _, chapter := range r.store.chapters {
+			if chapter.RealmID != realm.ID {
+				continue
+			}
+			chapter.Stages = nil
+			for _, stage := range r.store.stages {
+				if stage.ChapterID == chapter.ID {
+					chapter.Stages = append(chapter.Stages, stage)
+				}
+			}
+			sort.Slice(chapter.Stages, func(i, j int) bool { return chapter.Stages[i].StageNumber < chapter.Stages[j].StageNumber })
+			realm.Chapters = append(realm.Chapters, chapter)
+		}
+		sort.Slice(realm.Chapters, func(i, j int) bool { return realm.Chapters[i].ChapterNumber < realm.Chapters[j].ChapterNumber })
+		realms = append(realms, realm)
+	}
+	return realms, nil
+}
+
+func (r *pveRepository) FindStageByID(stageID uint) (*domain.Stage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, stage := range r.store.stages {
+		if stage.ID == stageID {
+			stage := stage
+			for i := range r.store.chapters {
+				if r.store.chapters[i].ID == stage.ChapterID {
+					chapter := r.store.chapters[i]
+					stage.Chapter = &chapter
+				}
+			}
+			return &stage, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *pveRepository) FindStageEnemiesByStageID(stageID uint) ([]domain.StageEnemy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var stageEnemies []domain.StageEnemy
+	for _, stageEnemy := range r.store.stageEnemies {
+		if stageEnemy.StageID == stageID {
+			stageEnemies = append(stageEnemies, stageEnemy)
+		}
+	}
+	sort.Slice(stageEnemies, func(i, j int) bool { return stageEnemies[i].Position < stageEnemies[j].Position })
+	return stageEnemies, nil
+}
+
+// ==================== Stage Progress ====================
+
+func (r *pveRepository) FindStageProgressByCharacterID(characterID uint) ([]domain.CharacterStageProgress, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var progress []domain.CharacterStageProgress
+	for _, p := range r.store.stageProgress[characterID] {
+		progress = append(progress, *p)
+	}
+	sort.Slice(progress, func(i, j int) bool { return progress[i].StageID < progress[j].StageID })
+	return progress, nil
+}
+
+func (r *pveRepository) FindStageProgress(characterID, stageID uint) (*domain.CharacterStageProgress, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if p, ok := r.store.stageProgress[characterID][stageID]; ok {
+		progressCopy := *p
+		return &progressCopy, nil
+	}
+	return nil, nil
+}
+
+// putStageProgress เก็บสำเนาความคืบหน้าด่าน (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) putStageProgress(progress *domain.CharacterStageProgress) {
+	if progress.ID == 0 {
+		s.nextStageProgressID++
+		progress.ID = s.nextStageProgressID
+	}
+	if s.stageProgress[progress.CharacterID] == nil {
+		s.stageProgress[progress.CharacterID] = make(map[uint]*domain.CharacterStageProgress)
+	}
+	progressCopy := *progress
+	s.stageProgress[progress.CharacterID][progress.StageID] = &progressCopy
+}
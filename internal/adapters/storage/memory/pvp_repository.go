@@ -0,0 +1,61 @@
+// file: internal/adapters/storage/memory/pvp_repository.go
+package memory
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/pvp"
+	"time"
+)
+
+type pvpRepository struct {
+	store *Store
+}
+
+// NewPvpRepository สร้าง PvpRepository ที่อ่าน/เขียนกับ Store
+func NewPvpRepository(store *Store) pvp.PvpRepository {
+	return &pvpRepository{store: store}
+}
+
+func (r *pvpRepository) FindRatingByCharacterID(characterID uint) (*domain.CharacterPvpRating, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	if rating, ok := r.store.pvpRatings[characterID]; ok {
+		ratingCopy := *rating
+		return &ratingCopy, nil
+	}
+	return nil, nil
+}
+
+// putPvpRating เก็บสำเนาคะแนน (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) putPvpRating(rating *domain.CharacterPvpRating) {
+	if rating.ID == 0 {
+		s.nextPvpRatingID++
+		rating.ID = s.nextPvpRatingID
+	}
+	rating.UpdatedAt = time.Now()
+	ratingCopy := *rating
+	s.pvpRatings[rating.CharacterID] = &ratingCopy
+}
+
+// appendPvpHistory เพิ่มประวัติการเปลี่ยนคะแนน (ต้องถือ write lock ก่อนเรียก)
+func (s *Store) appendPvpHistory(history *domain.PvpRatingHistory) {
+	s.nextPvpHistoryID++
+	history.ID = s.nextPvpHistoryID
+	if history.CreatedAt.IsZero() {
+		history.CreatedAt = time.Now()
+	}
+	s.pvpHistory = append(s.pvpHistory, *history)
+}
+
+// FindRatingHistory คืนประวัติล่าสุดก่อน (เพิ่มเข้าตามลำดับเวลาอยู่แล้ว จึงไล่จากท้าย)
+func (r *pvpRepository) FindRatingHistory(characterID uint, limit int) ([]domain.PvpRatingHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var history []domain.PvpRatingHistory
+	for i := len(r.store.pvpHistory) - 1; i >= 0 && (limit <= 0 || len(history) < limit); i-- {
+		if r.store.pvpHistory[i].CharacterID == characterID {
+			history = append(history, r.store.pvpHistory[i])
+		}
+	}
+	return history, nil
+}
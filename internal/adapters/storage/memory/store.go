@@ -0,0 +1,277 @@
+// file: internal/adapters/storage/memory/store.go
+package memory
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/adapters/storage/seeddata"
+	"sage-of-elements-backend/internal/domain"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== In-Memory Store ====================
+// Store คือ "ฐานข้อมูล" ในหน่วยความจำที่ทุก repository ใน package นี้ใช้ร่วมกัน
+// - ไม่ต้องใช้ Postgres/Redis (เหมาะกับ simulator, local dev และ test)
+// - Repository คืนค่าเป็นสำเนา (copy) เสมอ → แก้ไขค่าที่ได้ไปโดยไม่ Save จะไม่กระทบข้อมูลใน Store
+//   (พฤติกรรมเดียวกับการ query จาก DB ใหม่ทุกครั้ง)
+
+type Store struct {
+	mu sync.RWMutex
+
+	// --- Master Data ---
+	masteries    []domain.Mastery
+	elements     []domain.Element
+	effects      map[uint]*domain.Effect
+	recipes      []domain.Recipe
+	spells       map[uint]*domain.Spell
+	configs      map[string]string
+	matchups     map[[2]uint]float64
+	enemies      map[uint]*domain.Enemy
+	realms       []domain.Realm
+	chapters     []domain.Chapter
+	stages       []domain.Stage
+	stageEnemies []domain.StageEnemy
+
+	// --- Player Data ---
+	players         map[uint]*domain.Player
+	nextPlayerID    uint
+	playerAuths     map[uint]*domain.PlayerAuth
+	nextAuthID      uint
+	characters      map[uint]*domain.Character
+	nextCharacterID uint
+	decks           map[uint]*domain.Deck
+	nextDeckID      uint
+	nextDeckSlotID  uint
+	inventories     map[uint][]*domain.DimensionalSealInventory
+	nextInventoryID uint
+	discoveries     map[uint][]*domain.CharacterJournalDiscovery
+	spellUnlocks    map[uint][]*domain.CharacterSpellUnlock
+
+	// --- PvE Progress (characterID → stageID → progress) ---
+	stageProgress       map[uint]map[uint]*domain.CharacterStageProgress
+	nextStageProgressID uint
+
+	// --- PvP Data (characterID → rating) ---
+	pvpRatings       map[uint]*domain.CharacterPvpRating
+	nextPvpRatingID  uint
+	pvpHistory       []domain.PvpRatingHistory
+	nextPvpHistoryID uint
+
+	// --- Combat Data ---
+	matches    map[uuid.UUID]*domain.CombatMatch
+	matchOrder []uuid.UUID
+	actions    map[uuid.UUID][]*domain.CombatAction
+	events     map[uuid.UUID][]*domain.CombatEvent
+
+	// --- Match History ---
+	matchResults      []domain.MatchResult
+	nextMatchResultID uint
+
+	// --- Transaction ---
+	txMu sync.Mutex // ให้ Transaction ทำงานทีละอัน (ดู transactor.go)
+}
+
+// NewStore สร้าง Store เปล่า (ยังไม่มี Master Data)
+func NewStore() *Store {
+	return &Store{
+		effects:       make(map[uint]*domain.Effect),
+		spells:        make(map[uint]*domain.Spell),
+		configs:       make(map[string]string),
+		matchups:      make(map[[2]uint]float64),
+		enemies:       make(map[uint]*domain.Enemy),
+		players:       make(map[uint]*domain.Player),
+		playerAuths:   make(map[uint]*domain.PlayerAuth),
+		characters:    make(map[uint]*domain.Character),
+		decks:         make(map[uint]*domain.Deck),
+		inventories:   make(map[uint][]*domain.DimensionalSealInventory),
+		discoveries:   make(map[uint][]*domain.CharacterJournalDiscovery),
+		spellUnlocks:  make(map[uint][]*domain.CharacterSpellUnlock),
+		stageProgress: make(map[uint]map[uint]*domain.CharacterStageProgress),
+		pvpRatings:    make(map[uint]*domain.CharacterPvpRating),
+		matches:       make(map[uuid.UUID]*domain.CombatMatch),
+		actions:       make(map[uuid.UUID][]*domain.CombatAction),
+		events:        make(map[uuid.UUID][]*domain.CombatEvent),
+	}
+}
+
+// NewSeededStore สร้าง Store ที่โหลด Master Data ชุดเดียวกับที่ postgres.Seed เขียนลง DB
+func NewSeededStore() (*Store, error) {
+	store := NewStore()
+	if err := store.LoadSeedData(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// LoadSeedData โหลด (หรือเขียนทับ) Master Data ทั้งหมดจาก package seeddata
+// เงื่อนไขของเวทหรือกฎ AI ที่ผิด → คืน error โดยไม่โหลดอะไรเลย (เหมือน postgres.Seed ที่ปฏิเสธทั้งชุด)
+func (s *Store) LoadSeedData() error {
+	spells := seeddata.Spells()
+	if err := seeddata.ValidateSpells(spells, seeddata.Effects()); err != nil {
+		return fmt.Errorf("invalid spell effect conditions in seed data: %w", err)
+	}
+	enemies := seeddata.Enemies()
+	if err := seeddata.ValidateEnemies(enemies, seeddata.Effects()); err != nil {
+		return fmt.Errorf("invalid enemy AI rules in seed data: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.masteries = seeddata.Masteries()
+	s.elements = seeddata.Elements()
+
+	for _, effect := range seeddata.Effects() {
+		effect := effect
+		s.effects[effect.ID] = &effect
+	}
+
+	// สูตรหลอมรวม: เติม ID ให้เหมือนถูก insert ลง DB
+	s.recipes = seeddata.Recipes()
+	var nextIngredientID uint
+	for i := range s.recipes {
+		s.recipes[i].ID = uint(i + 1)
+		for _, ingredient := range s.recipes[i].Ingredients {
+			nextIngredientID++
+			ingredient.ID = nextIngredientID
+			ingredient.RecipeID = s.recipes[i].ID
+		}
+	}
+
+	// เวท: เติม SpellEffect.ID/SpellID และผูก Effect (เทียบเท่า Preload("Effects.Effect"))
+	var nextSpellEffectID uint
+	for _, spell := range spells {
+		spell := spell
+		for _, spellEffect := range spell.Effects {
+			nextSpellEffectID++
+			spellEffect.ID = nextSpellEffectID
+			spellEffect.SpellID = spell.ID
+			spellEffect.Effect = s.effects[spellEffect.EffectID]
+		}
+		s.spells[spell.ID] = &spell
+	}
+
+	for _, config := range seeddata.GameConfigs() {
+		s.configs[config.Key] = config.Value
+	}
+
+	for _, matchup := range seeddata.ElementalMatchups() {
+		s.matchups[[2]uint{matchup.AttackingElementID, matchup.DefendingElementID}] = matchup.Modifier
+	}
+
+	// ศัตรู: ผูก Element และ AbilityToUse (เทียบเท่า Preload("AI.AbilityToUse")) และให้ ID ตาราง loot
+	var nextAIRuleID, nextLootID uint
+	for _, enemy := range enemies {
+		enemy := enemy
+		enemy.Element = s.findElement(enemy.ElementID)
+		abilities := make(map[uint]*domain.EnemyAbility, len(enemy.Abilities))
+		for _, ability := range enemy.Abilities {
+			abilities[ability.ID] = ability
+		}
+		for _, rule := range enemy.AI {
+			nextAIRuleID++
+			rule.ID = nextAIRuleID
+			if rule.AbilityToUseID != nil {
+				rule.AbilityToUse = abilities[*rule.AbilityToUseID]
+			}
+		}
+		for _, loot := range enemy.Loots {
+			nextLootID++
+			loot.ID = nextLootID
+		}
+		s.enemies[enemy.ID] = &enemy
+	}
+
+	s.realms = seeddata.Realms()
+	s.chapters = seeddata.Chapters()
+	s.stages = seeddata.Stages()
+	s.stageEnemies = seeddata.StageEnemies()
+	return nil
+}
+
+// SetGameConfig เขียนทับค่า game config (ใช้ปรับ balance ใน simulator โดยไม่ต้องแก้ seeder)
+func (s *Store) SetGameConfig(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[key] = value
+}
+
+// ==================== Internal Helpers (ต้องถือ lock ก่อนเรียก) ====================
+
+func (s *Store) findElement(id uint) *domain.Element {
+	for i := range s.elements {
+		if s.elements[i].ID == id {
+			return &s.elements[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) findMastery(id uint) *domain.Mastery {
+	for i := range s.masteries {
+		if s.masteries[i].ID == id {
+			return &s.masteries[i]
+		}
+	}
+	return nil
+}
+
+// cloneCharacter คัดลอกตัวละครพร้อม Masteries (เทียบเท่า Preload("PrimaryElement") + Preload("Masteries.Mastery"))
+func (s *Store) cloneCharacter(src *domain.Character) *domain.Character {
+	char := *src
+	char.PrimaryElement = s.findElement(src.PrimaryElementID)
+	char.Masteries = make([]*domain.CharacterMastery, 0, len(src.Masteries))
+	for _, m := range src.Masteries {
+		mastery := *m
+		mastery.Mastery = s.findMastery(m.MasteryID)
+		char.Masteries = append(char.Masteries, &mastery)
+	}
+	char.DimensionalSeal = nil
+	char.JournalDiscoveries = nil
+	return &char
+}
+
+// cloneMatch คัดลอก match และ combatants ทั้งหมด พร้อมผูก Character/Enemy ตาม ID
+func (s *Store) cloneMatch(src *domain.CombatMatch) *domain.CombatMatch {
+	match := *src
+	match.PendingActions = nil
+	match.PendingEvents = nil
+	match.PendingResults = nil
+	match.PendingLoot = nil
+	match.PendingCharacters = nil
+	match.PendingMasteries = nil
+	match.PendingStageProgress = nil
+	match.PendingRatings = nil
+	match.PendingRatingHistory = nil
+	match.Combatants = make([]*domain.Combatant, 0, len(src.Combatants))
+	for _, c := range src.Combatants {
+		combatant := *c
+		combatant.Hand = cloneBytes(c.Hand)
+		combatant.ActiveEffects = cloneBytes(c.ActiveEffects)
+		combatant.Character = nil
+		combatant.Enemy = nil
+		if c.CharacterID != nil {
+			if char, ok := s.characters[*c.CharacterID]; ok {
+				combatant.Character = s.cloneCharacter(char)
+			}
+		}
+		if c.EnemyID != nil {
+			combatant.Enemy = s.enemies[*c.EnemyID]
+		}
+		combatant.Deck = make([]*domain.CombatantDeck, 0, len(c.Deck))
+		for _, charge := range c.Deck {
+			chargeCopy := *charge
+			combatant.Deck = append(combatant.Deck, &chargeCopy)
+		}
+		match.Combatants = append(match.Combatants, &combatant)
+	}
+	return &match
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
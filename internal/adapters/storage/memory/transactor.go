@@ -0,0 +1,85 @@
+// file: internal/adapters/storage/memory/transactor.go
+package memory
+
+import (
+	"database/sql"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/fusion"
+
+	"gorm.io/gorm"
+)
+
+// ==================== Transaction ====================
+// transactor จำลอง Transaction ของ *gorm.DB สำหรับ Store
+// - tx ที่ส่งให้ callback เป็น nil เสมอ (repository ใน package นี้ไม่ใช้ tx)
+// - Transaction ทำงานทีละอัน และถ้า callback คืน error (หรือ panic) จะ rollback "Player Data" กลับเป็นค่าก่อนเริ่ม
+// - Master Data และ Combat Data ไม่ถูก rollback (ไม่มี service ไหนแก้ข้อมูลพวกนี้ใน Transaction)
+
+type transactor struct {
+	store *Store
+}
+
+// NewTransactor สร้าง Transactor สำหรับ service ที่ต้องใช้ Transaction (เช่น fusion)
+func NewTransactor(store *Store) fusion.Transactor {
+	return &transactor{store: store}
+}
+
+func (t *transactor) Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error) {
+	t.store.txMu.Lock()
+	defer t.store.txMu.Unlock()
+
+	snapshot := t.store.snapshotPlayerData()
+	defer func() {
+		if r := recover(); r != nil {
+			t.store.restorePlayerData(snapshot)
+			panic(r)
+		}
+	}()
+
+	if err = fc(nil); err != nil {
+		t.store.restorePlayerData(snapshot)
+	}
+	return err
+}
+
+// playerDataSnapshot เก็บสำเนา map ของ Player Data
+// (copy แค่ map ก็พอ เพราะ repository ใน package นี้ "แทนที่" object ใน map เสมอ ไม่แก้ object เดิม)
+type playerDataSnapshot struct {
+	characters      map[uint]*domain.Character
+	nextCharacterID uint
+	inventories     map[uint][]*domain.DimensionalSealInventory
+	nextInventoryID uint
+	discoveries     map[uint][]*domain.CharacterJournalDiscovery
+}
+
+func (s *Store) snapshotPlayerData() *playerDataSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	snapshot := &playerDataSnapshot{
+		characters:      make(map[uint]*domain.Character, len(s.characters)),
+		nextCharacterID: s.nextCharacterID,
+		inventories:     make(map[uint][]*domain.DimensionalSealInventory, len(s.inventories)),
+		nextInventoryID: s.nextInventoryID,
+		discoveries:     make(map[uint][]*domain.CharacterJournalDiscovery, len(s.discoveries)),
+	}
+	for id, char := range s.characters {
+		snapshot.characters[id] = char
+	}
+	for id, items := range s.inventories {
+		snapshot.inventories[id] = items
+	}
+	for id, discoveries := range s.discoveries {
+		snapshot.discoveries[id] = discoveries
+	}
+	return snapshot
+}
+
+func (s *Store) restorePlayerData(snapshot *playerDataSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.characters = snapshot.characters
+	s.nextCharacterID = snapshot.nextCharacterID
+	s.inventories = snapshot.inventories
+	s.nextInventoryID = snapshot.nextInventoryID
+	s.discoveries = snapshot.discoveries
+}
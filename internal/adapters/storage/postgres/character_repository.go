@@ -60,6 +60,30 @@ func (r *characterRepository) Save(character *domain.Character) (*domain.Charact
 	return character, nil
 }
 
+// SaveTalents บันทึกการเปลี่ยนพรสวรรค์ด้วย conditional update (WHERE unallocated_talent_points = ค่าที่อ่านมา)
+// request ที่แจก/รีเซ็ตพร้อมกันจะผ่านได้แค่ตัวแรก ตัวที่เหลือได้ ErrTalentConflict แทนการใช้แต้มซ้ำ
+func (r *characterRepository) SaveTalents(char *domain.Character, previousUnallocated int) error {
+	result := r.db.Model(&domain.Character{}).
+		Where("id = ? AND unallocated_talent_points = ?", char.ID, previousUnallocated).
+		Updates(map[string]interface{}{
+			"talent_s":                  char.TalentS,
+			"talent_l":                  char.TalentL,
+			"talent_g":                  char.TalentG,
+			"talent_p":                  char.TalentP,
+			"unallocated_talent_points": char.UnallocatedTalentPoints,
+			"current_hp":                char.CurrentHP,
+			"current_mp":                char.CurrentMP,
+			"last_respec_at":            char.LastRespecAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return character.ErrTalentConflict
+	}
+	return nil
+}
+
 // FindAllByPlayerID ค้นหาตัวละครทั้งหมดที่เป็นของ Player ID ที่กำหนด
 func (r *characterRepository) FindAllByPlayerID(playerID uint) ([]domain.Character, error) {
 	var characters []domain.Character
@@ -135,6 +159,11 @@ func (r *characterRepository) UpdateCharacterInTx(tx *gorm.DB, character *domain
 // ConsumeAndUpdateInventoryInTx คือ Logic ที่ซับซ้อนที่สุดของเรา
 // ทำหน้าที่ "เช็คของ", "หักของ", และ "เพิ่มของ" ทั้งหมดใน Transaction เดียว
 func (r *characterRepository) ConsumeAndUpdateInventoryInTx(tx *gorm.DB, characterID uint, itemsToConsume map[uint]int, itemsToAdd map[uint]int) error {
+	return updateInventoryInTx(tx, characterID, itemsToConsume, itemsToAdd)
+}
+
+// updateInventoryInTx ใช้ร่วมกับ repository อื่นที่ต้องแก้คลังใน transaction ของตัวเอง (เช่น ของดรอปตอนจบ match)
+func updateInventoryInTx(tx *gorm.DB, characterID uint, itemsToConsume map[uint]int, itemsToAdd map[uint]int) error {
 	// 1. ดึงข้อมูลคลังปัจจุบันทั้งหมดขึ้นมา และ Lock แถวข้อมูลไว้
 	var currentInventory []*domain.DimensionalSealInventory
 	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("character_id = ?", characterID).Find(&currentInventory).Error
@@ -223,3 +252,73 @@ func (r *characterRepository) RegenerateStats(character *domain.Character, gameD
 	// เรียกใช้ Save (ตัวใหม่) เพื่อบันทึกข้อมูล
 	return r.Save(character)
 }
+
+// FindMatchResults ดึงประวัติการต่อสู้ของตัวละคร (ล่าสุดก่อน) พร้อมจำนวนทั้งหมดที่ตรงเงื่อนไข
+func (r *characterRepository) FindMatchResults(characterID uint, filter character.MatchHistoryFilter) ([]domain.MatchResult, int64, error) {
+	query := r.db.Model(&domain.MatchResult{}).Where("character_id = ?", characterID)
+	if filter.MatchType != nil {
+		query = query.Where("match_type = ?", *filter.MatchType)
+	}
+	if filter.From != nil {
+		query = query.Where("finished_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("finished_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []domain.MatchResult
+	err := query.
+		Order("finished_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&results).Error
+	return results, total, err
+}
+
+// HasActiveMatch ตรวจว่าตัวละครมี match IN_PROGRESS ที่ผู้เล่นควบคุมอยู่หรือไม่ (เงื่อนไขเดียวกับ FindPlayerActiveMatch)
+func (r *characterRepository) HasActiveMatch(characterID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Combatant{}).
+		Joins("JOIN combat_matches ON combat_matches.id = combatants.match_id").
+		Where("combat_matches.status = ?", domain.MatchInProgress).
+		Where("combatants.character_id = ?", characterID).
+		Where("combatants.is_ai_controlled = ?", false).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindSpellUnlocks ดึงเวทที่ตัวละครปลดล็อกแล้ว (เรียงตาม SpellID)
+func (r *characterRepository) FindSpellUnlocks(characterID uint) ([]*domain.CharacterSpellUnlock, error) {
+	var unlocks []*domain.CharacterSpellUnlock
+	err := r.db.Where("character_id = ?", characterID).Order("spell_id").Find(&unlocks).Error
+	return unlocks, err
+}
+
+// UnlockSpells บันทึกการปลดล็อกเวท (ON CONFLICT DO NOTHING เพื่อไม่ทับเวลาที่ปลดล็อกครั้งแรก)
+func (r *characterRepository) UnlockSpells(characterID uint, spellIDs []uint) error {
+	if len(spellIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	unlocks := make([]*domain.CharacterSpellUnlock, 0, len(spellIDs))
+	for _, spellID := range spellIDs {
+		unlocks = append(unlocks, &domain.CharacterSpellUnlock{CharacterID: characterID, SpellID: spellID, UnlockedAt: now})
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&unlocks).Error
+}
+
+// FindDiscoveredElementIDs ดึงธาตุผลลัพธ์ของสูตรที่ตัวละครค้นพบแล้ว
+func (r *characterRepository) FindDiscoveredElementIDs(characterID uint) ([]uint, error) {
+	var elementIDs []uint
+	err := r.db.Model(&domain.CharacterJournalDiscovery{}).
+		Joins("JOIN recipes ON recipes.id = character_journal_discoveries.recipe_id").
+		Where("character_journal_discoveries.character_id = ?", characterID).
+		Distinct().
+		Pluck("recipes.output_element_id", &elementIDs).Error
+	return elementIDs, err
+}
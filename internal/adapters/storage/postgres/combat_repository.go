@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/internal/modules/combat"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type combatRepository struct {
@@ -42,10 +44,15 @@ func (r *combatRepository) CreateMatch(match *domain.CombatMatch) (*domain.Comba
 func (r *combatRepository) FindMatchByID(matchID string) (*domain.CombatMatch, error) {
 	var match domain.CombatMatch
 	err := r.db.
+		Preload("Combatants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("combatants.id ASC") // UUIDv7 เรียงตามลำดับที่สร้าง → ลำดับเทิร์นคงที่ทุกครั้งที่โหลด
+		}).
 		Preload("Combatants.Character.PrimaryElement"). // Preload ให้ลึกขึ้นเผื่อใช้
+		Preload("Combatants.Character.Masteries").      // ใช้คำนวณ Mastery Bonus ตอนร่ายเวท
 		Preload("Combatants.Enemy.Element").
 		Preload("Combatants.Enemy.Abilities").       // <-- ⭐️ สั่งให้โหลดท่าโจมตีของศัตรูมาด้วย!
 		Preload("Combatants.Enemy.AI.AbilityToUse"). // <-- ⭐️ สั่งให้โหลดกฎ AI และท่าที่ผูกกับกฎนั้นมาด้วย!
+		Preload("Combatants.Enemy.Loots").           // ตาราง loot ใช้สุ่มของดรอปตอนผู้เล่นชนะ
 		Preload("Combatants.Deck").
 		Where("id = ?", matchID).
 		First(&match).Error
@@ -55,6 +62,18 @@ func (r *combatRepository) FindMatchByID(matchID string) (*domain.CombatMatch, e
 // UpdateMatch บันทึกสถานะล่าสุดของ Match และ Combatant ทุกตัว
 func (r *combatRepository) UpdateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error) {
 	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 0. จอง Version ถัดไป (optimistic lock) → request อื่นบันทึกไปก่อนแล้ว = ไม่บันทึกอะไรเลย
+		result := tx.Model(&domain.CombatMatch{}).
+			Where("id = ? AND version = ?", match.ID, match.Version).
+			Update("version", match.Version+1)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return combat.ErrMatchConflict
+		}
+		match.Version++
+
 		// 1. บันทึก Combatant ทุกตัวก่อน
 		for i := range match.Combatants {
 			if err := tx.Save(match.Combatants[i]).Error; err != nil {
@@ -66,10 +85,71 @@ func (r *combatRepository) UpdateMatch(match *domain.CombatMatch) (*domain.Comba
 		if err := tx.Save(match).Error; err != nil {
 			return err
 		}
+
+		// 3. บันทึก Action ของผู้เล่น (สำหรับ replay) และ Combat Event ที่เกิดขึ้นใน action นี้
+		if len(match.PendingActions) > 0 {
+			if err := tx.Create(&match.PendingActions).Error; err != nil {
+				return err
+			}
+		}
+		if len(match.PendingEvents) > 0 {
+			if err := tx.Create(&match.PendingEvents).Error; err != nil {
+				return err
+			}
+		}
+
+		// 4. บันทึกสรุปผลของ match ที่เพิ่งจบ
+		if len(match.PendingResults) > 0 {
+			if err := tx.Create(&match.PendingResults).Error; err != nil {
+				return err
+			}
+		}
+
+		// 5. เพิ่มของดรอปจากศัตรูและรางวัลเคลียร์ครั้งแรกเข้าคลัง (commit พร้อมการจบ match เท่านั้น)
+		for _, drop := range match.PendingLoot {
+			if err := updateInventoryInTx(tx, drop.CharacterID, nil, map[uint]int{drop.ElementID: drop.Quantity}); err != nil {
+				return err
+			}
+		}
+
+		// 6. บันทึก EXP/เลเวลของตัวละคร (เฉพาะคอลัมน์ของตัวละคร) และ Level/MXP ของศาสตร์แยกทีละแถว
+		//    (Save ของ Character ไม่อัปเดต association ที่มีอยู่แล้ว)
+		for _, char := range match.PendingCharacters {
+			if err := tx.Omit(clause.Associations).Save(char).Error; err != nil {
+				return err
+			}
+		}
+		for _, mastery := range match.PendingMasteries {
+			if err := tx.Omit("Mastery").Save(mastery).Error; err != nil {
+				return err
+			}
+		}
+
+		// 7. บันทึกความคืบหน้าด่าน (รวม flag รับรางวัลเคลียร์ครั้งแรก)
+		for _, progress := range match.PendingStageProgress {
+			if err := tx.Save(progress).Error; err != nil {
+				return err
+			}
+		}
+
+		// 8. บันทึกคะแนน Ranked และประวัติ (ID = 0 → สร้างใหม่)
+		for _, rating := range match.PendingRatings {
+			if err := tx.Save(rating).Error; err != nil {
+				return err
+			}
+		}
+		if len(match.PendingRatingHistory) > 0 {
+			if err := tx.Create(&match.PendingRatingHistory).Error; err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
 	if err != nil {
+		if err != combat.ErrMatchConflict {
+			match.Version-- // rollback แล้ว Version ในฐานข้อมูลยังเป็นค่าเดิม
+		}
 		return nil, err
 	}
 
@@ -77,6 +157,26 @@ func (r *combatRepository) UpdateMatch(match *domain.CombatMatch) (*domain.Comba
 	return r.FindMatchByID(match.ID.String())
 }
 
+// FindActionsByMatchID ดึง action ของผู้เล่นทั้งหมดใน match เรียงตามลำดับ (สำหรับ replay)
+func (r *combatRepository) FindActionsByMatchID(matchID string) ([]*domain.CombatAction, error) {
+	var actions []*domain.CombatAction
+	err := r.db.
+		Where("match_id = ?", matchID).
+		Order("sequence ASC").
+		Find(&actions).Error
+	return actions, err
+}
+
+// FindEventsByMatchID ดึง Combat Event ทั้งหมดใน match เรียงตามลำดับ
+func (r *combatRepository) FindEventsByMatchID(matchID string) ([]*domain.CombatEvent, error) {
+	var events []*domain.CombatEvent
+	err := r.db.
+		Where("match_id = ?", matchID).
+		Order("sequence ASC").
+		Find(&events).Error
+	return events, err
+}
+
 // ==================== Cleanup Methods ====================
 
 // FindStaleMatches หา match ที่ไม่มีความเคลื่อนไหวเกินเวลากำหนด (นาที)
@@ -102,6 +202,7 @@ func (r *combatRepository) AbortStaleMatches(inactiveMinutes int) (int64, error)
 			"status":      domain.MatchAborted,
 			"finished_at": now,
 			"updated_at":  now,
+			"version":     gorm.Expr("version + 1"),
 		})
 	return result.RowsAffected, result.Error
 }
@@ -114,6 +215,7 @@ func (r *combatRepository) FindPlayerActiveMatch(characterID uint) (*domain.Comb
 		Joins("JOIN combatants ON combatants.match_id = combat_matches.id").
 		Where("combat_matches.status = ?", domain.MatchInProgress).
 		Where("combatants.character_id = ?", characterID).
+		Where("combatants.is_ai_controlled = ?", false). // ตัวละครที่ถูกท้าดวล (PvP async) ไม่นับว่ากำลังเล่น
 		Preload("Combatants.Character.PrimaryElement").
 		Preload("Combatants.Enemy.Element").
 		First(&match).Error
@@ -144,9 +246,26 @@ func (r *combatRepository) AbortMatchByID(matchID string, reason string) (*domai
 		"status":      domain.MatchAborted,
 		"finished_at": now,
 		"updated_at":  now,
+		"version":     gorm.Expr("version + 1"),
 	}).Error; err != nil {
 		return nil, err
 	}
 
 	return r.FindMatchByID(matchID)
 }
+
+// ==================== Turn Timer ====================
+
+// FindTimedOutMatches หา match ที่ยังเล่นอยู่แต่เทิร์นปัจจุบันหรือทั้ง match หมดเวลาแล้ว
+// หรือมีผู้เล่นหลุดการเชื่อมต่อเกินเส้นตาย (โหลดแค่ข้อมูล match, service จะโหลด match เต็มอีกครั้งก่อนจัดการ)
+func (r *combatRepository) FindTimedOutMatches(now time.Time) ([]*domain.CombatMatch, error) {
+	disconnected := r.db.Model(&domain.Combatant{}).Select("match_id").Where("disconnect_deadline < ?", now)
+
+	var matches []*domain.CombatMatch
+	err := r.db.
+		Where("status = ?", domain.MatchInProgress).
+		Where("turn_deadline < ? OR match_deadline < ? OR id IN (?)", now, now, disconnected).
+		Order("updated_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
@@ -86,3 +86,36 @@ func (r *deckRepository) Delete(deckID uint) error {
 	// GORM จะจัดการลบ Deck และ Slots ที่ผูกกัน (constraint:OnDelete:CASCADE) ให้โดยอัตโนมัติ
 	return r.db.Delete(&domain.Deck{}, deckID).Error
 }
+
+func (r *deckRepository) SetDefenseDeck(characterID, deckID uint) (*domain.Deck, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 1. ยกเลิก Defense Deck เดิมของตัวละคร
+		if err := tx.Model(&domain.Deck{}).
+			Where("character_id = ? AND is_defense = ?", characterID, true).
+			Update("is_defense", false).Error; err != nil {
+			return err
+		}
+		// 2. ตั้ง Deck ใหม่
+		return tx.Model(&domain.Deck{}).
+			Where("id = ? AND character_id = ?", deckID, characterID).
+			Update("is_defense", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(deckID)
+}
+
+func (r *deckRepository) FindDefenseDeck(characterID uint) (*domain.Deck, error) {
+	var d domain.Deck
+	err := r.db.Preload("Slots").
+		Where("character_id = ? AND is_defense = ?", characterID, true).
+		First(&d).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
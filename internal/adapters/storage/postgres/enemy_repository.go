@@ -18,11 +18,12 @@ func NewEnemyRepository(db *gorm.DB) enemy.EnemyRepository {
 
 func (r *enemyRepository) FindByID(id uint) (*domain.Enemy, error) {
 	var e domain.Enemy
-	// Preload ทุกอย่างที่เกี่ยวกับศัตรูมาให้หมด! (Abilities, AI)
+	// Preload ทุกอย่างที่เกี่ยวกับศัตรูมาให้หมด! (Abilities, AI, Loots)
 	err := r.db.
 		Preload("Element").
 		Preload("Abilities").
-		Preload("AI.AbilityToUse").First(&e, id).Error
+		Preload("AI.AbilityToUse").
+		Preload("Loots").First(&e, id).Error
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
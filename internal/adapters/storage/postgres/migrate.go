@@ -29,6 +29,7 @@ func AutoMigrate(db *gorm.DB, appLogger applogger.Logger) error {
 		&domain.CharacterMastery{},
 		&domain.DimensionalSealInventory{},
 		&domain.CharacterJournalDiscovery{},
+		&domain.CharacterSpellUnlock{},
 		&domain.Deck{},
 		&domain.DeckSlot{},
 
@@ -43,12 +44,20 @@ func AutoMigrate(db *gorm.DB, appLogger applogger.Logger) error {
 		&domain.Chapter{},
 		&domain.Stage{},
 		&domain.StageEnemy{},
+		&domain.CharacterStageProgress{},
+
+		// --- PvP Data ---
+		&domain.CharacterPvpRating{},
+		&domain.PvpRatingHistory{},
 
 		// --- ✨⭐️ สิ่งที่หายไป อยู่ตรงนี้! ⭐️✨ ---
 		// --- Combat Data ---
 		&domain.CombatMatch{},
 		&domain.Combatant{},
 		&domain.CombatantDeck{},
+		&domain.CombatEvent{},
+		&domain.CombatAction{},
+		&domain.MatchResult{},
 	)
 
 	if err != nil {
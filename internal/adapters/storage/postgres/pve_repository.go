@@ -2,6 +2,7 @@
 package postgres
 
 import (
+	"errors"
 	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/internal/modules/pve"
 
@@ -34,3 +35,47 @@ func (r *pveRepository) FindAllActiveRealms() ([]domain.Realm, error) {
 		Find(&realms).Error
 	return realms, err
 }
+
+// FindStageByID ดึงข้อมูลด่านพร้อม Chapter (คืน nil ถ้าไม่พบ)
+func (r *pveRepository) FindStageByID(stageID uint) (*domain.Stage, error) {
+	var stage domain.Stage
+	err := r.db.Preload("Chapter").First(&stage, stageID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stage, nil
+}
+
+// FindStageEnemiesByStageID ดึงศัตรูทั้งหมดในด่าน เรียงตามตำแหน่งในสนามรบ
+func (r *pveRepository) FindStageEnemiesByStageID(stageID uint) ([]domain.StageEnemy, error) {
+	var stageEnemies []domain.StageEnemy
+	err := r.db.
+		Where("stage_id = ?", stageID).
+		Order("position asc").
+		Find(&stageEnemies).Error
+	return stageEnemies, err
+}
+
+// ==================== Stage Progress ====================
+
+func (r *pveRepository) FindStageProgressByCharacterID(characterID uint) ([]domain.CharacterStageProgress, error) {
+	var progress []domain.CharacterStageProgress
+	err := r.db.Where("character_id = ?", characterID).Order("stage_id asc").Find(&progress).Error
+	return progress, err
+}
+
+// FindStageProgress ดึงความคืบหน้าของด่านเดียว (คืน nil ถ้ายังไม่เคยเคลียร์)
+func (r *pveRepository) FindStageProgress(characterID, stageID uint) (*domain.CharacterStageProgress, error) {
+	var progress domain.CharacterStageProgress
+	err := r.db.Where("character_id = ? AND stage_id = ?", characterID, stageID).First(&progress).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
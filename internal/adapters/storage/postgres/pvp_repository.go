@@ -0,0 +1,41 @@
+// file: internal/adapters/storage/postgres/pvp_repository.go
+package postgres
+
+import (
+	"errors"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/pvp"
+
+	"gorm.io/gorm"
+)
+
+type pvpRepository struct {
+	db *gorm.DB
+}
+
+func NewPvpRepository(db *gorm.DB) pvp.PvpRepository {
+	return &pvpRepository{db: db}
+}
+
+// FindRatingByCharacterID ดึงคะแนน Ranked ของตัวละคร (คืน nil ถ้ายังไม่เคยเล่น)
+func (r *pvpRepository) FindRatingByCharacterID(characterID uint) (*domain.CharacterPvpRating, error) {
+	var rating domain.CharacterPvpRating
+	err := r.db.Where("character_id = ?", characterID).First(&rating).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *pvpRepository) FindRatingHistory(characterID uint, limit int) ([]domain.PvpRatingHistory, error) {
+	var history []domain.PvpRatingHistory
+	err := r.db.
+		Where("character_id = ?", characterID).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&history).Error
+	return history, err
+}
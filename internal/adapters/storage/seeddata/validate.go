@@ -0,0 +1,186 @@
+// file: internal/adapters/storage/seeddata/validate.go
+package seeddata
+
+import (
+	"errors"
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+)
+
+// ValidateEnemies ตรวจกฎ AI ของศัตรูก่อนเขียนลง storage (แทนที่จะไปเจอตอน AI ตัดสินใจกลาง match)
+// - AIMode/AIDifficulty ต้องเป็นค่าที่รู้จัก และศัตรูแบบ UTILITY ต้องมี ability ให้เลือก
+// - Condition, Target และ Action ต้องเป็นค่าที่ Combat Service รองรับ (USE_ABILITY เท่านั้นที่ต้องมี AbilityToUseID)
+// - เงื่อนไข HP ต้องมี ConditionValue อยู่ในช่วง (0, 1], TURN_IS ต้องเป็นเลขเทิร์นจำนวนเต็มตั้งแต่ 1
+// - ConditionEffectID ใช้ได้เฉพาะเงื่อนไข *_HAS_BUFF/*_HAS_DEBUFF และต้องชี้ไปที่เอฟเฟกต์ประเภทที่ตรงกัน
+func ValidateEnemies(enemies []domain.Enemy, effects []domain.Effect) error {
+	effectTypes := make(map[uint]domain.EffectType, len(effects))
+	for _, effect := range effects {
+		effectTypes[effect.ID] = effect.Type
+	}
+
+	var errs []error
+	for _, enemy := range enemies {
+		if err := validateAIMode(enemy); err != nil {
+			errs = append(errs, fmt.Errorf("enemy %s: %w", enemy.Name, err))
+		}
+		for _, rule := range enemy.AI {
+			if err := validateAIRule(rule, effectTypes); err != nil {
+				errs = append(errs, fmt.Errorf("enemy %s rule priority %d: %w", enemy.Name, rule.Priority, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateAIMode ตรวจโหมด AI และระดับความยาก (ค่าว่าง = ค่า default ของคอลัมน์: RULES / NORMAL)
+func validateAIMode(enemy domain.Enemy) error {
+	if enemy.AIMode != "" && !enemy.AIMode.IsKnown() {
+		return fmt.Errorf("unknown AI mode %q", enemy.AIMode)
+	}
+	if enemy.AIDifficulty != "" && !enemy.AIDifficulty.IsKnown() {
+		return fmt.Errorf("unknown AI difficulty %q", enemy.AIDifficulty)
+	}
+	if enemy.UsesUtilityAI() && len(enemy.Abilities) == 0 {
+		return fmt.Errorf("%s AI needs at least one ability", enemy.AIMode)
+	}
+	return nil
+}
+
+func validateAIRule(rule *domain.EnemyAI, effectTypes map[uint]domain.EffectType) error {
+	if !rule.Condition.IsKnown() {
+		return fmt.Errorf("unknown AI condition %q", rule.Condition)
+	}
+	if !rule.Target.IsKnown() {
+		return fmt.Errorf("unknown AI target %q", rule.Target)
+	}
+	if !rule.Action.IsKnown() {
+		return fmt.Errorf("unknown AI action %q", rule.Action)
+	}
+	if usesAbility := rule.Action == domain.AIActionUseAbility; usesAbility != (rule.AbilityToUseID != nil) {
+		if usesAbility {
+			return fmt.Errorf("%s needs an ability", rule.Action)
+		}
+		return fmt.Errorf("%s does not take an ability", rule.Action)
+	}
+
+	switch {
+	case rule.Condition.ChecksHP():
+		if rule.ConditionValue <= 0 || rule.ConditionValue > 1 {
+			return fmt.Errorf("%s needs condition value in (0, 1], got %v", rule.Condition, rule.ConditionValue)
+		}
+	case rule.Condition == domain.AIConditionTurnIs:
+		if rule.ConditionValue < 1 || rule.ConditionValue != float64(int(rule.ConditionValue)) {
+			return fmt.Errorf("%s needs a whole turn number, got %v", rule.Condition, rule.ConditionValue)
+		}
+	}
+
+	if rule.ConditionEffectID == nil {
+		return nil
+	}
+	if !rule.Condition.ChecksEffect() {
+		return fmt.Errorf("%s does not take a condition effect", rule.Condition)
+	}
+	effectType, ok := effectTypes[*rule.ConditionEffectID]
+	if !ok {
+		return fmt.Errorf("condition effect %d does not exist", *rule.ConditionEffectID)
+	}
+	wantBuff := rule.Condition == domain.AIConditionSelfHasBuff || rule.Condition == domain.AIConditionTargetHasBuff
+	if wantBuff && !effectType.IsBuff() || !wantBuff && !effectType.IsDebuff() {
+		return fmt.Errorf("condition effect %d (%s) does not match %s", *rule.ConditionEffectID, effectType, rule.Condition)
+	}
+	return nil
+}
+
+// ValidateSpells ตรวจเงื่อนไขของ SpellEffect ก่อนเขียนลง storage
+// - ConditionType ต้องเป็นค่าที่ Combat Service รองรับ (ค่าว่าง = NONE)
+// - ConditionDetails ต้องเป็น JSON ของ domain.SpellEffectCondition และมีฟิลด์ที่เงื่อนไขนั้นต้องใช้ครบ
+// - effect_id/spell_id ที่อ้างถึงต้องมีอยู่จริง (effect_id ต้องเป็นบัฟ/ดีบัฟให้ตรงกับเงื่อนไข)
+func ValidateSpells(spells []domain.Spell, effects []domain.Effect) error {
+	effectTypes := make(map[uint]domain.EffectType, len(effects))
+	for _, effect := range effects {
+		effectTypes[effect.ID] = effect.Type
+	}
+	spellIDs := make(map[uint]bool, len(spells))
+	for _, spell := range spells {
+		spellIDs[spell.ID] = true
+	}
+
+	var errs []error
+	for _, spell := range spells {
+		for _, spellEffect := range spell.Effects {
+			if err := validateSpellEffectCondition(spellEffect, effectTypes, spellIDs); err != nil {
+				errs = append(errs, fmt.Errorf("spell %s effect %d: %w", spell.Name, spellEffect.EffectID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateSpellEffectCondition(spellEffect *domain.SpellEffect, effectTypes map[uint]domain.EffectType, spellIDs map[uint]bool) error {
+	conditionType := spellEffect.ConditionType
+	if conditionType != "" && !conditionType.IsKnown() {
+		return fmt.Errorf("unknown condition %q", conditionType)
+	}
+	condition, err := spellEffect.ParseCondition()
+	if err != nil {
+		return fmt.Errorf("invalid condition details %q: %w", spellEffect.ConditionDetails, err)
+	}
+	if !spellEffect.HasCondition() {
+		if condition != (domain.SpellEffectCondition{}) {
+			return fmt.Errorf("condition details without a condition")
+		}
+		return nil
+	}
+
+	switch {
+	case conditionType.ChecksHP():
+		if condition.Value <= 0 || condition.Value > 1 {
+			return fmt.Errorf("%s needs value in (0, 1], got %v", conditionType, condition.Value)
+		}
+	case conditionType == domain.ConditionTypeSelfAPIs || conditionType == domain.ConditionTypeComboCountIs:
+		if condition.Value < 1 || condition.Value != float64(int(condition.Value)) {
+			return fmt.Errorf("%s needs a whole value from 1, got %v", conditionType, condition.Value)
+		}
+	case conditionType == domain.ConditionTypeSelfHasElement:
+		if condition.ElementID == 0 {
+			return fmt.Errorf("%s needs element_id", conditionType)
+		}
+	case conditionType == domain.ConditionTypeTurnCountIs:
+		switch condition.Parity {
+		case "EVEN", "ODD":
+			if condition.Value != 0 {
+				return fmt.Errorf("%s takes either value or parity, not both", conditionType)
+			}
+		case "":
+			if condition.Value < 1 || condition.Value != float64(int(condition.Value)) {
+				return fmt.Errorf("%s needs a whole turn number or parity, got %v", conditionType, condition.Value)
+			}
+		default:
+			return fmt.Errorf("%s parity must be EVEN or ODD, got %q", conditionType, condition.Parity)
+		}
+	case conditionType == domain.ConditionTypeLastSpellWas:
+		if !spellIDs[condition.SpellID] {
+			return fmt.Errorf("%s spell %d does not exist", conditionType, condition.SpellID)
+		}
+	case conditionType == domain.ConditionTypeRandomChance:
+		if condition.Chance <= 0 || condition.Chance > 1 {
+			return fmt.Errorf("%s needs chance in (0, 1], got %v", conditionType, condition.Chance)
+		}
+	}
+
+	if condition.EffectID == nil {
+		return nil
+	}
+	wantBuff := conditionType == domain.ConditionTypeSelfHasBuff
+	if !wantBuff && conditionType != domain.ConditionTypeTargetHasDebuff {
+		return fmt.Errorf("%s does not take effect_id", conditionType)
+	}
+	effectType, ok := effectTypes[*condition.EffectID]
+	if !ok {
+		return fmt.Errorf("condition effect %d does not exist", *condition.EffectID)
+	}
+	if wantBuff && !effectType.IsBuff() || !wantBuff && !effectType.IsDebuff() {
+		return fmt.Errorf("condition effect %d (%s) does not match %s", *condition.EffectID, effectType, conditionType)
+	}
+	return nil
+}
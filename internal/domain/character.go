@@ -10,7 +10,8 @@ type Character struct {
 	Gender                  string                       `gorm:"size:50;not null;comment:เพศของตัวละคร (MALE, FEMALE)" json:"gender"`
 	PrimaryElementID        uint                         `gorm:"not null;comment:ID ของธาตุปฐมภูมิที่เลือกตอนสร้างตัว" json:"primary_element_id"`
 	Level                   int                          `gorm:"default:1;comment:เลเวลปัจจุบัน" json:"level"`
-	Exp                     int                          `gorm:"default:0;comment:ค่าประสบการณ์ปัจจุบัน" json:"exp"`
+	Exp                     int                          `gorm:"default:0;comment:ค่าประสบการณ์ปัจจุบัน (ภายในเลเวลนี้)" json:"exp"`
+	ExpToNextLevel          int                          `gorm:"-" json:"exp_to_next_level"` // EXP ที่ต้องใช้ขึ้นเลเวลถัดไป (คำนวณตอนอ่าน, 0 = เลเวลสูงสุด)
 	CurrentHP               int                          `gorm:"comment:พลังชีวิตปัจจุบัน" json:"current_hp"`
 	CurrentMP               int                          `gorm:"comment:พลังเวทปัจจุบัน" json:"current_mp"`
 	TalentS                 int                          `gorm:"comment:แต้มพรสวรรค์ S" json:"talent_s"`
@@ -18,10 +19,12 @@ type Character struct {
 	TalentG                 int                          `gorm:"comment:แต้มพรสวรรค์ G" json:"talent_g"`
 	TalentP                 int                          `gorm:"comment:แต้มพรสวรรค์ P" json:"talent_p"`
 	UnallocatedTalentPoints int                          `gorm:"default:0;comment:แต้มพรสวรรค์ที่ยังไม่ได้แจกจ่าย" json:"unallocated_talent_points"`
+	LastRespecAt            *time.Time                   `gorm:"comment:เวลาที่รีเซ็ตพรสวรรค์ล่าสุด (ใช้คำนวณ cooldown)" json:"last_respec_at,omitempty"`
 	StatsUpdatedAt          time.Time                    `gorm:"autoCreateTime;comment:เวลาที่อัปเดตค่าพลังล่าสุด" json:"statsUpdatedAt"`
 	TutorialStep            int                          `gorm:"default:1;comment:ขั้นตอนของบทช่วยสอนที่ทำถึง" json:"tutorial_step"`
 	PrimaryElement          *Element                     `gorm:"foreignKey:PrimaryElementID;references:ID"`
 	Masteries               []*CharacterMastery          `gorm:"foreignKey:CharacterID;constraint:OnDelete:CASCADE;" json:"masteries"`
 	DimensionalSeal         []*DimensionalSealInventory  `gorm:"foreignKey:CharacterID;constraint:OnDelete:CASCADE;" json:"dimensionalSeal"`
 	JournalDiscoveries      []*CharacterJournalDiscovery `gorm:"foreignKey:CharacterID;constraint:OnDelete:CASCADE;" json:"journalDiscoveries"`
+	SpellUnlocks            []*CharacterSpellUnlock      `gorm:"foreignKey:CharacterID;constraint:OnDelete:CASCADE;" json:"-"` // ดูผ่าน GET /characters/:id/spells
 }
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// CharacterSpellUnlock บันทึกเวทที่ตัวละครปลดล็อกแล้ว (ปลดล็อกครั้งเดียวแล้วใช้ได้ตลอด แม้เงื่อนไขใน Spell จะเปลี่ยนภายหลัง)
+type CharacterSpellUnlock struct {
+	CharacterID uint      `gorm:"primaryKey;autoIncrement:false;comment:ID ของตัวละคร (Composite PK)" json:"-"`
+	SpellID     uint      `gorm:"primaryKey;autoIncrement:false;comment:ID ของเวทที่ปลดล็อก (Composite PK, ไม่ผูก FK เพราะ seeder ลบ/สร้าง spells ใหม่)" json:"spell_id"`
+	UnlockedAt  time.Time `gorm:"comment:วันเวลาที่ปลดล็อก" json:"unlocked_at"`
+}
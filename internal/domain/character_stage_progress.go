@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// CharacterStageProgress คือตารางที่เก็บว่าตัวละครเคลียร์ด่านไหนไปแล้วบ้าง (1 แถวต่อ 1 ด่าน)
+type CharacterStageProgress struct {
+	ID             uint      `gorm:"primaryKey;comment:ID เฉพาะของแถวข้อมูล (PK)" json:"-"`
+	CharacterID    uint      `gorm:"not null;uniqueIndex:idx_character_stage;comment:ID ของตัวละคร (FK to characters)" json:"-"`
+	StageID        uint      `gorm:"not null;uniqueIndex:idx_character_stage;comment:ID ของด่าน (FK to stages)" json:"stage_id"`
+	ClearedAt      time.Time `gorm:"not null;comment:เวลาที่เคลียร์ด่านนี้ครั้งแรก" json:"cleared_at"`
+	LastClearedAt  time.Time `gorm:"not null;comment:เวลาที่เคลียร์ด่านนี้ล่าสุด" json:"last_cleared_at"`
+	BestTurnCount  int       `gorm:"not null;comment:จำนวนเทิร์นที่น้อยที่สุดที่ใช้เคลียร์" json:"best_turn_count"`
+	ClearCount     int       `gorm:"not null;default:0;comment:จำนวนครั้งที่เคลียร์" json:"clear_count"`
+	RewardsClaimed bool      `gorm:"not null;default:false;comment:รับรางวัลเคลียร์ครั้งแรกไปแล้วหรือยัง" json:"rewards_claimed"`
+}
@@ -0,0 +1,23 @@
+// file: internal/domain/combat_action.go
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CombatAction คือ action ของผู้เล่นที่ถูกยอมรับแล้ว 1 ครั้ง (ใช้สำหรับ replay / ตรวจสอบย้อนหลัง)
+// เก็บเฉพาะข้อมูลที่ส่งเข้ามา ผลลัพธ์จะคำนวณซ้ำได้จาก snapshot + seed ของ match
+type CombatAction struct {
+	ID          uint       `gorm:"primaryKey" json:"-"`
+	MatchID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_combat_action_match_seq" json:"-"`
+	Sequence    int        `gorm:"not null;uniqueIndex:idx_combat_action_match_seq" json:"sequence"`
+	TurnNumber  int        `gorm:"not null" json:"turnNumber"`
+	CombatantID uuid.UUID  `gorm:"type:uuid;not null" json:"combatantId"` // ผู้ที่ส่ง action
+	ActionType  string     `gorm:"type:varchar(20);not null" json:"actionType"`
+	CastMode    string     `gorm:"type:varchar(20)" json:"castMode,omitempty"`
+	SpellID     *uint      `json:"spellId,omitempty"`
+	TargetID    *uuid.UUID `gorm:"type:uuid" json:"targetId,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
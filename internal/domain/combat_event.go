@@ -0,0 +1,56 @@
+// file: internal/domain/combat_event.go
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/datatypes"
+)
+
+// CombatEventType คือประเภทของเหตุการณ์ที่เกิดขึ้นในสนามรบ (ใช้สำหรับ animation และ battle log ฝั่ง client)
+type CombatEventType string
+
+const (
+	CombatEventCast           CombatEventType = "CAST"             // ร่ายเวท / AI ใช้ท่า
+	CombatEventMultiCast      CombatEventType = "MULTI_CAST"       // ร่ายซ้ำจาก Talent G
+	CombatEventDamage         CombatEventType = "DAMAGE"           // HP ลดจากการโจมตี
+	CombatEventHeal           CombatEventType = "HEAL"             // HP เพิ่มจากการฟื้นฟู
+	CombatEventMPDamage       CombatEventType = "MP_DAMAGE"        // MP ลดจากการโจมตี
+	CombatEventShieldAbsorbed CombatEventType = "SHIELD_ABSORBED"  // โล่ดูดซับความเสียหาย
+	CombatEventEvaded         CombatEventType = "EVADED"           // หลบการโจมตีได้ ("MISS")
+	CombatEventRetaliation    CombatEventType = "RETALIATION"      // สะท้อนความเสียหายกลับหาผู้โจมตี
+	CombatEventEffectApplied  CombatEventType = "EFFECT_APPLIED"   // ติด buff/debuff/stance/shield
+	CombatEventEffectExpired  CombatEventType = "EFFECT_EXPIRED"   // effect หมดอายุ
+	CombatEventTick           CombatEventType = "TICK"             // effect ต่อเนื่องทำงานต้นเทิร์น (Regen, DoT)
+	CombatEventTurnStarted    CombatEventType = "TURN_STARTED"     // เริ่มเทิร์นของ combatant
+	CombatEventTurnEnded      CombatEventType = "TURN_ENDED"       // จบเทิร์นของ combatant
+	CombatEventMatchEnded     CombatEventType = "MATCH_ENDED"      // การต่อสู้จบลง
+	CombatEventStageCleared   CombatEventType = "STAGE_CLEARED"    // เคลียร์ด่าน STORY (details บอกรางวัลครั้งแรก)
+	CombatEventForfeited      CombatEventType = "FORFEITED"        // ผู้เล่นยอมแพ้/หลุดการเชื่อมต่อ (source = ผู้ยอมแพ้)
+	CombatEventDisconnected   CombatEventType = "DISCONNECTED"     // ผู้เล่นหลุดการเชื่อมต่อ PvP สด (source = ตัวละคร, details = เส้นตายที่ต้องกลับมา)
+	CombatEventReconnected    CombatEventType = "RECONNECTED"      // ผู้เล่นกลับมาก่อนหมดเวลา (source = ตัวละคร)
+	CombatEventRatingChanged  CombatEventType = "RATING_CHANGED"   // คะแนน Ranked เปลี่ยนหลังจบ match (source = ตัวละคร, value = ส่วนต่าง)
+	CombatEventLootDropped    CombatEventType = "LOOT_DROPPED"     // ศัตรูที่ถูกกำจัดดรอปของ (source = ศัตรู, target = ผู้ชนะ, value = จำนวน)
+	CombatEventLevelUp        CombatEventType = "LEVEL_UP"         // ตัวละครขึ้นเลเวลจาก EXP ที่ได้ตอนจบ match (source = ตัวละคร, value = เลเวลใหม่)
+	CombatEventMasteryLevelUp CombatEventType = "MASTERY_LEVEL_UP" // ศาสตร์ขึ้นเลเวลจาก MXP ตอนจบ match (source = ตัวละคร, value = เลเวลใหม่)
+	CombatEventPassed         CombatEventType = "PASSED"           // AI ข้ามเทิร์นโดยตั้งใจ (value = AP ที่เก็บไว้)
+	CombatEventFled           CombatEventType = "FLED"             // ศัตรูหนีออกจาก match สำเร็จ
+	CombatEventFleeFailed     CombatEventType = "FLEE_FAILED"      // ศัตรูพยายามหนีแต่ไม่สำเร็จ (เสียเทิร์น)
+)
+
+// CombatEvent แทนเหตุการณ์ 1 อย่างที่เกิดขึ้นใน Match (เรียงตาม Sequence)
+type CombatEvent struct {
+	ID         uint            `gorm:"primaryKey" json:"-"`
+	MatchID    uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_combat_event_match_seq" json:"-"`
+	Sequence   int             `gorm:"not null;uniqueIndex:idx_combat_event_match_seq" json:"sequence"`
+	TurnNumber int             `gorm:"not null" json:"turnNumber"`
+	Type       CombatEventType `gorm:"type:varchar(30);not null" json:"type"`
+	SourceID   *uuid.UUID      `gorm:"type:uuid" json:"sourceId,omitempty"` // ผู้กระทำ (caster / เจ้าของ effect)
+	TargetID   *uuid.UUID      `gorm:"type:uuid" json:"targetId,omitempty"` // ผู้ถูกกระทำ
+	SpellID    *uint           `json:"spellId,omitempty"`
+	EffectID   *uint           `json:"effectId,omitempty"`
+	Value      int             `gorm:"not null;default:0" json:"value"`
+	Details    datatypes.JSON  `gorm:"type:jsonb" json:"details,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
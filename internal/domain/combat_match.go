@@ -46,4 +46,36 @@ type CombatMatch struct {
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `gorm:"index" json:"updatedAt"` // ✅ ใช้สำหรับตรวจจับ match ค้าง (GORM auto-update)
 	FinishedAt  *time.Time     `json:"finishedAt"`
+	Ranked      bool           `gorm:"not null;default:false" json:"ranked"` // PvP จาก matchmaking (จบแล้วปรับ rating)
+
+	// Version เพิ่มขึ้นทุกครั้งที่บันทึก match (optimistic lock: UpdateMatch ที่โหลด match ไปก่อนคนอื่นบันทึกจะถูกปฏิเสธ)
+	Version int `gorm:"not null;default:0" json:"-"`
+
+	// --- Turn Timer (nil = ไม่จับเวลา เช่น DisableTimer หรือเป็นเทิร์นของ AI) ---
+	TurnDeadline  *time.Time `gorm:"index" json:"turnDeadline,omitempty"`  // เทิร์นของผู้เล่นปัจจุบันจะหมดเวลาเมื่อไหร่ (client ใช้แสดง countdown)
+	MatchDeadline *time.Time `gorm:"index" json:"matchDeadline,omitempty"` // ทั้ง match จะหมดเวลาเมื่อไหร่ (หมดแล้วนับเป็นแพ้)
+
+	// --- Deterministic Random Source (ห้ามส่งให้ client เพื่อกันการทำนายผลสุ่ม) ---
+	RandomSeed   int64 `gorm:"not null;default:0" json:"-"` // seed ที่สุ่มตอน CreateMatch
+	RandomCursor int64 `gorm:"not null;default:0" json:"-"` // จำนวนครั้งที่สุ่มไปแล้ว (ตำแหน่งใน random stream)
+
+	// --- Replay ---
+	Snapshot       datatypes.JSON  `gorm:"type:jsonb" json:"-"`         // สถานะเริ่มต้นของ match (combatants, decks, configs, seed)
+	ActionSequence int             `gorm:"not null;default:0" json:"-"` // ลำดับล่าสุดของ CombatAction ใน match นี้
+	PendingActions []*CombatAction `gorm:"-" json:"-"`                  // action ที่ยอมรับใน request นี้ (ยังไม่ถูกบันทึก)
+
+	// --- Combat Event Log ---
+	EventSequence int            `gorm:"not null;default:0" json:"-"` // ลำดับล่าสุดของ CombatEvent ใน match นี้
+	PendingEvents []*CombatEvent `gorm:"-" json:"-"`                  // event ที่เกิดใน action นี้ (ยังไม่ถูกบันทึก)
+
+	// --- Match Result ---
+	PendingResults []*MatchResult `gorm:"-" json:"-"` // สรุปผลที่สร้างตอนจบ match (ยังไม่ถูกบันทึก)
+	PendingLoot    []*LootDrop    `gorm:"-" json:"-"` // ของดรอปจากศัตรู/รางวัลเคลียร์ครั้งแรกที่รอเข้าคลังพร้อมการจบ match
+
+	// --- Character Progression (บันทึกใน transaction เดียวกับ UpdateMatch → จบ match ซ้ำไม่ได้รางวัลซ้ำ) ---
+	PendingCharacters    []*Character              `gorm:"-" json:"-"` // ตัวละครที่ EXP/เลเวล/แต้มพรสวรรค์เปลี่ยนใน action นี้ (ไม่รวมศาสตร์)
+	PendingMasteries     []*CharacterMastery       `gorm:"-" json:"-"` // Level/MXP ของศาสตร์ที่ได้จากการร่ายทั้ง match
+	PendingStageProgress []*CharacterStageProgress `gorm:"-" json:"-"` // ความคืบหน้าด่าน STORY ที่เพิ่งเคลียร์
+	PendingRatings       []*CharacterPvpRating     `gorm:"-" json:"-"` // คะแนน Ranked ใหม่ของทั้งสองฝั่ง
+	PendingRatingHistory []*PvpRatingHistory       `gorm:"-" json:"-"` // ประวัติการเปลี่ยนคะแนนของ match นี้
 }
@@ -2,6 +2,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 	"gorm.io/datatypes"
 )
@@ -17,15 +19,49 @@ type Combatant struct {
 	EnemyID     *uint      `gorm:"comment:ID ของศัตรู (ถ้าเป็น AI)" json:"-"`
 	Enemy       *Enemy     `gorm:"foreignKey:EnemyID" json:"enemy,omitempty"`
 
+	// Team = ฝั่งของ combatant (PLAYER = ผู้ท้าชิงและพันธมิตร, ENEMY = ศัตรูหรือฝ่ายถูกท้า)
+	// ค่าว่าง = match ที่สร้างก่อนมีทีม (อนุมานจาก EnemyID/IsOpponent แทน)
+	Team MatchSide `gorm:"type:varchar(10)" json:"team"`
+
+	// IsOpponent = ตัวละครฝ่ายถูกท้าใน PvP (นับเป็นฝั่งศัตรูของผู้ท้าชิง)
+	IsOpponent bool `gorm:"not null;default:false" json:"isOpponent"`
+	// IsAIControlled = ตัวละครของผู้เล่นอีกคนที่ AI เล่นแทน (ฝ่ายป้องกันใน PvP แบบ async และพันธมิตร co-op)
+	// เจ้าของตัวละครส่ง action ใน match นี้ไม่ได้ (PvP สดจะเป็น false ทั้งสองฝั่ง)
+	IsAIControlled bool `gorm:"not null;default:false" json:"isAiControlled"`
+
 	// --- Stats ชั่วคราวใน Match นี้ ---
+	Level      int `gorm:"not null;default:0" json:"level,omitempty"` // เลเวลของศัตรูใน match นี้ (อาจถูก override โดยด่าน)
+	MaxHP      int `gorm:"not null;default:0" json:"maxHp,omitempty"` // HP สูงสุดของศัตรูหลังปรับตามเลเวล (0 = ใช้ Enemy.MaxHP)
 	Initiative int `gorm:"not null" json:"initiative"`
 	CurrentHP  int `gorm:"not null" json:"currentHp"`
 	CurrentMP  int `gorm:"not null" json:"currentMp"`
 	CurrentAP  int `gorm:"not null" json:"currentAp"`
+	Threat     int `gorm:"not null;default:0" json:"threat"` // ดาเมจ + ฮีลที่ทำไปใน match นี้ (AI ใช้เลือกเป้าหมาย PLAYER_HIGHEST_THREAT)
+
+	// LastActedRound = รอบล่าสุด (CombatMatch.TurnNumber) ที่จบเทิร์นไปแล้ว ใช้จัดคิวเทิร์นตาม Initiative
+	LastActedRound int `gorm:"not null;default:0" json:"lastActedRound"`
+
+	// --- ประวัติการร่ายของ combatant (ใช้กับเงื่อนไข LAST_SPELL_WAS / COMBO_COUNT_IS ของ SpellEffect) ---
+	LastSpellID *uint `json:"lastSpellId,omitempty"`                // เวทที่ร่ายสำเร็จล่าสุดใน match นี้
+	ComboCount  int   `gorm:"not null;default:0" json:"comboCount"` // จำนวนเวทที่ร่ายในเทิร์นนี้ (รีเซ็ตตอนจบเทิร์น)
+
+	// HasFled = ศัตรูหนีออกจาก match แล้ว (AI action FLEE) ไม่ได้เทิร์นและเป็นเป้าหมายไม่ได้อีก
+	HasFled bool `gorm:"not null;default:false" json:"hasFled"`
+
+	// DisconnectDeadline = เส้นตายให้ผู้เล่นที่หลุด WebSocket ทุกการเชื่อมต่อใน PvP สดกลับมา (nil = ยังเชื่อมต่ออยู่)
+	// เลยเวลานี้แล้ว scheduler จะให้ยอมแพ้แทน (ดู ProcessTimeouts)
+	DisconnectDeadline *time.Time `gorm:"index" json:"disconnectDeadline,omitempty"`
 
 	Hand          datatypes.JSON `gorm:"type:jsonb" json:"hand"`
 	ActiveEffects datatypes.JSON `gorm:"type:jsonb" json:"activeEffects"`
 
+	// GhostSpells = ID เวทที่ AI ร่ายแทนตัวละครนี้ได้ (JSON array, resolve และตรวจการปลดล็อกครั้งเดียวตอนสร้าง match)
+	// ว่าง = ไม่ใช่ตัวละครที่ AI เล่นแทน หรือ match ที่สร้างก่อนมีรายการนี้
+	GhostSpells datatypes.JSON `gorm:"type:jsonb" json:"-"`
+
+	// SealElements = ID ธาตุที่ตัวละครมีใน Dimensional Seal ตอนเริ่ม match (JSON array, ใช้กับเงื่อนไข SELF_HAS_ELEMENT)
+	SealElements datatypes.JSON `gorm:"type:jsonb" json:"-"`
+
 	Deck []*CombatantDeck `gorm:"foreignKey:CombatantID;constraint:OnDelete:CASCADE;" json:"-"`
 }
 
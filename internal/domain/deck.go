@@ -5,6 +5,7 @@ type Deck struct {
 	CharacterID  uint        `gorm:"not null;index" json:"-"`
 	Name         string      `gorm:"size:100;not null" json:"name"`
 	IsActive     bool        `gorm:"not null;default:false" json:"isActive"`
+	IsDefense    bool        `gorm:"not null;default:false" json:"isDefense"` // Deck ที่ใช้ตอนถูกท้าชิงใน PvP แบบ async (มีได้ 1 Deck ต่อตัวละคร)
 	DisplayOrder int         `gorm:"not null;default:0" json:"displayOrder"`
 	Slots        []*DeckSlot `gorm:"foreignKey:DeckID;constraint:OnDelete:CASCADE;" json:"slots"`
 }
@@ -28,3 +28,18 @@ const (
 	EffectTypeSynergyBuff  EffectType = "BUFF_SYNERGY"   // เอฟเฟกต์พิเศษที่ได้จาก Synergy
 	EffectTypeDebuffDOT    EffectType = "DEBUFF_DOT"     // เอฟเฟกต์สร้างความเสียหายต่อเนื่อง (เช่น เผาไหม้, พิษ)
 )
+
+// IsBuff บอกว่าเป็นเอฟเฟกต์เสริมพลังที่ติดตัว (BUFF, BUFF_SYNERGY)
+func (t EffectType) IsBuff() bool {
+	return t == EffectTypeBuff || t == EffectTypeSynergyBuff
+}
+
+// IsDebuff บอกว่าเป็นเอฟเฟกต์ลดทอนที่ติดตัว (DEBUFF ทุกประเภท)
+func (t EffectType) IsDebuff() bool {
+	return t == EffectTypeDebuff || t == EffectTypeDebuffCC || t == EffectTypeDebuffHardCC || t == EffectTypeDebuffDOT
+}
+
+// IsCrowdControl บอกว่าเป็นเอฟเฟกต์ขัดขวาง (Slow/Stun) ซึ่งนับเป็นสถานะ "เสียหลัก" (Staggered)
+func (t EffectType) IsCrowdControl() bool {
+	return t == EffectTypeDebuffCC || t == EffectTypeDebuffHardCC
+}
@@ -14,6 +14,8 @@ type Enemy struct {
 	Initiative   int            `gorm:"not null;comment:ค่าความเร็วพื้นฐาน"`
 	ElementID    uint           `gorm:"comment:ID ของธาตุประจำตัวศัตรู (FK to elements)"`
 	Element      *Element       `gorm:"foreignKey:ElementID;references:ID"`
+	AIMode       EnemyAIMode    `gorm:"size:20;not null;default:'RULES';comment:วิธีตัดสินใจของ AI (RULES, UTILITY)"`
+	AIDifficulty AIDifficulty   `gorm:"size:20;not null;default:'NORMAL';comment:ระดับความยากของ AI แบบ UTILITY"`
 
 	// --- ⭐️ เพิ่ม 3 บรรทัดนี้เข้ามาเพื่อสร้าง "ความสัมพันธ์"! ⭐️ ---
 	// นี่คือ "สะพาน" ที่บอก GORM ว่า Enemy มี Abilities, AI, และ Loots ได้หลายอัน
@@ -46,19 +48,57 @@ type EnemyLoot struct {
 
 // EnemyAI คือ "กฎ" การตัดสินใจ 1 ข้อของศัตรู
 type EnemyAI struct {
-	ID             uint          `gorm:"primaryKey;comment:ID เฉพาะของกฎ (PK)"`
-	EnemyID        uint          `gorm:"not null;comment:ID ของศัตรูที่เป็นเจ้าของกฎนี้ (FK to enemies)"`
-	Priority       int           `gorm:"not null;comment:ลำดับความสำคัญ (ยิ่งน้อยยิ่งทำก่อน)"`
-	Condition      AICondition   `gorm:"size:100;not null;comment:เงื่อนไขในการทำงาน (เช่น IF_SELF_HP_BELOW)"`
-	ConditionValue float64       `gorm:"comment:ค่าของเงื่อนไข (เช่น 0.5 สำหรับ 50%)"`
-	Action         AIAction      `gorm:"size:100;not null;comment:การกระทำที่จะทำ (เช่น USE_ABILITY)"`
-	Target         AITarget      `gorm:"size:50;not null;comment:เป้าหมายของการกระทำ (SELF, PLAYER)"`
-	AbilityToUseID *uint         `gorm:"comment:ID ของท่าที่จะใช้ (ถ้า Action คือ USE_ABILITY)"`
-	AbilityToUse   *EnemyAbility `gorm:"foreignKey:AbilityToUseID;references:ID"`
+	ID                uint          `gorm:"primaryKey;comment:ID เฉพาะของกฎ (PK)"`
+	EnemyID           uint          `gorm:"not null;comment:ID ของศัตรูที่เป็นเจ้าของกฎนี้ (FK to enemies)"`
+	Priority          int           `gorm:"not null;comment:ลำดับความสำคัญ (ยิ่งน้อยยิ่งทำก่อน)"`
+	Condition         AICondition   `gorm:"size:100;not null;comment:เงื่อนไขในการทำงาน (เช่น IF_SELF_HP_BELOW)"`
+	ConditionValue    float64       `gorm:"comment:ค่าของเงื่อนไข (เช่น 0.5 สำหรับ 50%)"`
+	ConditionEffectID *uint         `gorm:"comment:ID ของเอฟเฟกต์ที่เงื่อนไข *_HAS_BUFF/*_HAS_DEBUFF ต้องการ (nil = บัฟ/ดีบัฟใดก็ได้)"`
+	Action            AIAction      `gorm:"size:100;not null;comment:การกระทำที่จะทำ (เช่น USE_ABILITY)"`
+	Target            AITarget      `gorm:"size:50;not null;comment:เป้าหมายของการกระทำ (SELF, PLAYER)"`
+	AbilityToUseID    *uint         `gorm:"comment:ID ของท่าที่จะใช้ (ถ้า Action คือ USE_ABILITY)"`
+	AbilityToUse      *EnemyAbility `gorm:"foreignKey:AbilityToUseID;references:ID"`
+}
+
+// UsesUtilityAI บอกว่าศัตรูตัวนี้ใช้ AI แบบให้คะแนน (ค่าว่างจากข้อมูลเก่า = RULES)
+func (e *Enemy) UsesUtilityAI() bool {
+	return e.AIMode == EnemyAIModeUtility
 }
 
 // --- Constants / Enums (พร้อมคำอธิบายภาษาไทย) ---
 
+// EnemyAIMode คือวิธีที่ AI ของศัตรูเลือก action
+type EnemyAIMode string
+
+const (
+	EnemyAIModeRules   EnemyAIMode = "RULES"   // ไล่กฎ EnemyAI ตาม Priority แล้วทำข้อแรกที่เข้าเงื่อนไข (แบบเดิม)
+	EnemyAIModeUtility EnemyAIMode = "UTILITY" // ให้คะแนนทุก ability × เป้าหมาย แล้วเลือกค่าที่ดีที่สุด (ไม่ใช้ EnemyAI)
+)
+
+// IsKnown บอกว่า Combat Service รองรับโหมดนี้หรือไม่ (ใช้ตรวจข้อมูลศัตรูตอน seed)
+func (m EnemyAIMode) IsKnown() bool {
+	return m == EnemyAIModeRules || m == EnemyAIModeUtility
+}
+
+// AIDifficulty คือระดับความยากของ AI แบบ UTILITY (คุมความสุ่มของคะแนนและการมองล่วงหน้า)
+type AIDifficulty string
+
+const (
+	AIDifficultyEasy      AIDifficulty = "EASY"      // คะแนนสุ่มแกว่งมาก ไม่มองล่วงหน้า
+	AIDifficultyNormal    AIDifficulty = "NORMAL"    // คะแนนสุ่มแกว่งเล็กน้อย ไม่มองล่วงหน้า
+	AIDifficultyHard      AIDifficulty = "HARD"      // แทบไม่สุ่ม มองล่วงหน้า 1 ท่า
+	AIDifficultyNightmare AIDifficulty = "NIGHTMARE" // ไม่สุ่ม มองล่วงหน้า 2 ท่า
+)
+
+// IsKnown บอกว่า Combat Service มีโปรไฟล์ของระดับความยากนี้หรือไม่
+func (d AIDifficulty) IsKnown() bool {
+	switch d {
+	case AIDifficultyEasy, AIDifficultyNormal, AIDifficultyHard, AIDifficultyNightmare:
+		return true
+	}
+	return false
+}
+
 // AICondition คือเงื่อนไขในการทำงานของ AI
 type AICondition string
 
@@ -79,23 +119,83 @@ const (
 	AIConditionTurnIsOdd         AICondition = "TURN_IS_ODD"         // เป็นเทิร์นเลขคี่
 )
 
+// IsKnown บอกว่า Combat Service รองรับเงื่อนไขนี้หรือไม่ (ใช้ตรวจกฎ AI ตอน seed)
+func (c AICondition) IsKnown() bool {
+	switch c {
+	case AIConditionAlways,
+		AIConditionSelfHPBelow, AIConditionSelfHPAbove,
+		AIConditionTargetHPBelow, AIConditionTargetHPAbove,
+		AIConditionSelfHasBuff, AIConditionSelfHasDebuff,
+		AIConditionTargetHasBuff, AIConditionTargetHasDebuff,
+		AIConditionTargetHasShield, AIConditionTargetIsStaggered,
+		AIConditionTurnIs, AIConditionTurnIsEven, AIConditionTurnIsOdd:
+		return true
+	}
+	return false
+}
+
+// ChecksEffect บอกว่าเงื่อนไขนี้ตรวจบัฟ/ดีบัฟ (ระบุเอฟเฟกต์เฉพาะด้วย ConditionEffectID ได้)
+func (c AICondition) ChecksEffect() bool {
+	switch c {
+	case AIConditionSelfHasBuff, AIConditionSelfHasDebuff, AIConditionTargetHasBuff, AIConditionTargetHasDebuff:
+		return true
+	}
+	return false
+}
+
+// ChecksHP บอกว่าเงื่อนไขนี้ใช้ ConditionValue เป็นสัดส่วน HP (0.0 ถึง 1.0)
+func (c AICondition) ChecksHP() bool {
+	switch c {
+	case AIConditionSelfHPBelow, AIConditionSelfHPAbove, AIConditionTargetHPBelow, AIConditionTargetHPAbove:
+		return true
+	}
+	return false
+}
+
 // AIAction คือการกระทำที่ AI สามารถทำได้
 type AIAction string
 
 const (
 	AIActionUseAbility AIAction = "USE_ABILITY" // ใช้ความสามารถพิเศษ (ตาม AbilityToUseID)
-	AIActionDefend     AIAction = "DEFEND"      // ตั้งการ์ดป้องกัน (Logic พิเศษใน Combat Service)
-	AIActionPass       AIAction = "PASS"        // อยู่เฉยๆ / ข้ามเทิร์น
-	AIActionFlee       AIAction = "FLEE"        // พยายามหลบหนี
+	AIActionDefend     AIAction = "DEFEND"      // ตั้งการ์ดป้องกัน: ได้ BUFF_DEFENSE_UP แล้วจบเทิร์น (ใช้ AP ที่เหลือทั้งหมด)
+	AIActionPass       AIAction = "PASS"        // อยู่เฉยๆ / ข้ามเทิร์น (เก็บ AP ไว้เทิร์นถัดไป)
+	AIActionFlee       AIAction = "FLEE"        // พยายามหลบหนี: สำเร็จ = ออกจาก match (ไม่ดรอปของ, ผู้เล่นได้ EXP บางส่วน)
 )
 
+// IsKnown บอกว่า Combat Service ทำการกระทำนี้ได้หรือไม่ (ใช้ตรวจกฎ AI ตอน seed)
+func (a AIAction) IsKnown() bool {
+	switch a {
+	case AIActionUseAbility, AIActionDefend, AIActionPass, AIActionFlee:
+		return true
+	}
+	return false
+}
+
+// EndsTurn บอกว่าการกระทำนี้จบเทิร์นของ AI ทันที (ไม่เลือก action ต่อแม้ยังมี AP)
+func (a AIAction) EndsTurn() bool {
+	return a == AIActionDefend || a == AIActionPass || a == AIActionFlee
+}
+
 // AITarget คือเป้าหมายที่เป็นไปได้ของ AI
 type AITarget string
 
 const (
-	AITargetSelf           AITarget = "SELF"             // ตัวเอง
-	AITargetPlayer         AITarget = "PLAYER"           // ผู้เล่น (เป้าหมายหลัก)
-	AITargetPlayerLowestHP AITarget = "PLAYER_LOWEST_HP" // ผู้เล่นที่ HP ต่ำที่สุด
-	AITargetAlly           AITarget = "ALLY"             // เพื่อนร่วมทีมของศัตรู
-	AITargetAllyLowestHP   AITarget = "ALLY_LOWEST_HP"   // เพื่อนร่วมทีมที่ HP ต่ำที่สุด
+	AITargetSelf                AITarget = "SELF"                  // ตัวเอง
+	AITargetPlayer              AITarget = "PLAYER"                // ผู้เล่น (เป้าหมายหลัก)
+	AITargetPlayerLowestHP      AITarget = "PLAYER_LOWEST_HP"      // ผู้เล่นที่ HP ต่ำที่สุด (ตามสัดส่วน)
+	AITargetPlayerHighestThreat AITarget = "PLAYER_HIGHEST_THREAT" // ผู้เล่นที่สร้าง Threat (ดาเมจ + ฮีล) มากที่สุด
+	AITargetRandomPlayer        AITarget = "RANDOM_PLAYER"         // สุ่มผู้เล่น 1 คน (ผ่าน random stream ของ match)
+	AITargetAlly                AITarget = "ALLY"                  // เพื่อนร่วมทีมของศัตรู (ไม่รวมตัวเอง)
+	AITargetAllyLowestHP        AITarget = "ALLY_LOWEST_HP"        // เพื่อนร่วมทีมที่ HP ต่ำที่สุด (ตามสัดส่วน, ไม่รวมตัวเอง)
+	AITargetAllyWithoutBuff     AITarget = "ALLY_WITHOUT_BUFF"     // เพื่อนร่วมทีมที่ยังไม่มีบัฟจากท่านี้ (ไม่รวมตัวเอง)
 )
+
+// IsKnown บอกว่า Combat Service เลือกเป้าหมายแบบนี้ได้หรือไม่ (ใช้ตรวจกฎ AI ตอน seed)
+func (t AITarget) IsKnown() bool {
+	switch t {
+	case AITargetSelf, AITargetPlayer, AITargetPlayerLowestHP, AITargetPlayerHighestThreat, AITargetRandomPlayer,
+		AITargetAlly, AITargetAllyLowestHP, AITargetAllyWithoutBuff:
+		return true
+	}
+	return false
+}
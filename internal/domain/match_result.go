@@ -0,0 +1,78 @@
+// file: internal/domain/match_result.go
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/datatypes"
+)
+
+// MatchOutcome คือผลแพ้ชนะจากมุมของตัวละครเจ้าของสรุปผล
+type MatchOutcome string
+
+const (
+	MatchOutcomeWin  MatchOutcome = "WIN"
+	MatchOutcomeLose MatchOutcome = "LOSE"
+)
+
+// MatchSide คือฝั่งในสนาม (ฝั่งผู้ท้าชิง หรือฝั่งศัตรู/ผู้ถูกท้า)
+type MatchSide string
+
+const (
+	MatchSidePlayer MatchSide = "PLAYER"
+	MatchSideEnemy  MatchSide = "ENEMY"
+)
+
+// MatchEndReason คือสาเหตุที่ match จบ
+type MatchEndReason string
+
+const (
+	MatchEndDefeat  MatchEndReason = "DEFEAT"  // ฝั่งใดฝั่งหนึ่ง HP หมด
+	MatchEndForfeit MatchEndReason = "FORFEIT" // ผู้เล่นยอมแพ้/หลุดการเชื่อมต่อ
+	MatchEndTimeout MatchEndReason = "TIMEOUT" // ทั้ง match หมดเวลา (ฝั่งผู้เล่นแพ้)
+	MatchEndFled    MatchEndReason = "FLED"    // ศัตรูที่เหลือหนีไปหมด (ผู้เล่นชนะ แต่ได้ EXP บางส่วนและไม่ได้ของจากตัวที่หนี)
+)
+
+// MatchResult คือสรุปผล match ที่จบแล้วของตัวละคร 1 ตัว (PvP มี 2 แถวต่อ match)
+// ถูกเขียนตอน _EndMatch พร้อมกับการบันทึก match ครั้งสุดท้าย
+type MatchResult struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	MatchID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_match_result_character" json:"match_id"`
+	CharacterID uint           `gorm:"not null;uniqueIndex:idx_match_result_character;index:idx_match_result_history,priority:1" json:"character_id"`
+	MatchType   MatchType      `gorm:"type:varchar(20);not null;index" json:"match_type"`
+	StageID     *uint          `json:"stage_id,omitempty"`
+	Ranked      bool           `gorm:"not null;default:false" json:"ranked"`
+	Outcome     MatchOutcome   `gorm:"type:varchar(10);not null" json:"outcome"`
+	WinnerSide  MatchSide      `gorm:"type:varchar(10);not null" json:"winner_side"`
+	EndReason   MatchEndReason `gorm:"type:varchar(20);not null" json:"end_reason"`
+
+	// --- สถิติในสนาม (นับจาก Combat Event) ---
+	TurnsTaken     int `gorm:"not null;default:0" json:"turns_taken"`
+	DamageDealt    int `gorm:"not null;default:0" json:"damage_dealt"`
+	DamageReceived int `gorm:"not null;default:0" json:"damage_received"`
+	HealingDone    int `gorm:"not null;default:0" json:"healing_done"`
+	SpellsCast     int `gorm:"not null;default:0" json:"spells_cast"`
+
+	// --- รางวัล/บทลงโทษ ---
+	ExpGained  int            `gorm:"not null;default:0" json:"exp_gained"`  // EXP ชนะ + EXP เคลียร์ครั้งแรก
+	ExpPenalty int            `gorm:"not null;default:0" json:"exp_penalty"` // EXP ที่ถูกหักจากการยอมแพ้
+	Loot       datatypes.JSON `gorm:"type:jsonb" json:"loot,omitempty"`      // []StageRewardElement ที่เข้าคลัง
+
+	// --- เลเวล (0 = ไม่ได้รับ EXP ใน match นี้) ---
+	LevelBefore        int `gorm:"not null;default:0" json:"level_before"`
+	LevelAfter         int `gorm:"not null;default:0" json:"level_after"`
+	TalentPointsGained int `gorm:"not null;default:0" json:"talent_points_gained"`
+	MxpGained          int `gorm:"not null;default:0" json:"mxp_gained"` // MXP ศาสตร์รวมทุกศาสตร์จากการร่ายใน match นี้
+
+	DurationSeconds int       `gorm:"not null;default:0" json:"duration_seconds"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `gorm:"not null;index:idx_match_result_history,priority:2" json:"finished_at"`
+}
+
+// LootDrop คือของที่ดรอปจากศัตรู 1 รายการ (รวมจำนวนต่อธาตุแล้ว) ที่จะเข้าคลังของตัวละครผู้ชนะ
+type LootDrop struct {
+	CharacterID uint `json:"-"`
+	ElementID   uint `json:"element_id"`
+	Quantity    int  `json:"quantity"`
+}
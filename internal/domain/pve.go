@@ -10,6 +10,13 @@ type Realm struct {
 	Descriptions    datatypes.JSON `gorm:"type:jsonb;comment:คำอธิบายโหมด"`
 	IsActive        bool           `gorm:"not null;default:true;comment:โหมดนี้เปิดให้เล่นอยู่หรือไม่"`
 	UnlockCondition datatypes.JSON `gorm:"type:jsonb;comment:เงื่อนไขในการปลดล็อก (JSON)"`
+	Chapters        []Chapter      `gorm:"foreignKey:RealmID"` // GORM Preload (เรียงตาม ChapterNumber)
+}
+
+// RealmUnlockCondition คือรูปแบบของ Realm.UnlockCondition (ทุกเงื่อนไขที่ระบุต้องผ่าน)
+type RealmUnlockCondition struct {
+	RequiredStageID *uint `json:"required_stage_id,omitempty"` // ต้องเคลียร์ด่านนี้ก่อน
+	MinLevel        int   `json:"min_level,omitempty"`         // เลเวลตัวละครขั้นต่ำ
 }
 
 // Chapter คือบทของเนื้อเรื่อง หรือกลุ่มของด่านที่อยู่ใน Realm เดียวกัน
@@ -21,6 +28,7 @@ type Chapter struct {
 	DisplayNames  datatypes.JSON `gorm:"type:jsonb;comment:ชื่อบทที่แสดงในเกม"`
 	Descriptions  datatypes.JSON `gorm:"type:jsonb;comment:คำอธิบายเรื่องย่อของบท"`
 	Realm         *Realm         `gorm:"foreignKey:RealmID;references:ID"` // GORM Preload
+	Stages        []Stage        `gorm:"foreignKey:ChapterID"`             // GORM Preload (เรียงตาม StageNumber)
 }
 
 // Stage คือ "ด่าน" 1 ด่านที่ผู้เล่นจะต้องเข้าไปต่อสู้
@@ -36,6 +44,18 @@ type Stage struct {
 	Chapter           *Chapter       `gorm:"foreignKey:ChapterID;references:ID"` // GORM Preload
 }
 
+// StageRewards คือรูปแบบของ Stage.FirstClearRewards
+type StageRewards struct {
+	Exp      int                  `json:"exp,omitempty"`
+	Elements []StageRewardElement `json:"elements,omitempty"` // ธาตุที่จะเข้าคลัง Dimensional Seal
+}
+
+// StageRewardElement คือธาตุ 1 ชนิดในรางวัลของด่าน
+type StageRewardElement struct {
+	ElementID uint `json:"element_id"`
+	Quantity  int  `json:"quantity"`
+}
+
 // --- Constants / Enums ---
 // StageType คือประเภทของด่าน
 type StageType string
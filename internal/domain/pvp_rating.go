@@ -0,0 +1,32 @@
+// file: internal/domain/pvp_rating.go
+package domain
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// CharacterPvpRating คือคะแนน Ranked PvP ของตัวละคร (Glicko-2, 1 แถวต่อ 1 ตัวละคร)
+type CharacterPvpRating struct {
+	ID              uint      `gorm:"primaryKey;comment:ID เฉพาะของแถวข้อมูล (PK)" json:"-"`
+	CharacterID     uint      `gorm:"not null;uniqueIndex;comment:ID ของตัวละคร (FK to characters)" json:"character_id"`
+	Rating          float64   `gorm:"not null;index;comment:คะแนน (เริ่มที่ PVP_RATING_DEFAULT)" json:"rating"`
+	RatingDeviation float64   `gorm:"not null;comment:ความไม่แน่นอนของคะแนน (RD)" json:"rating_deviation"`
+	Volatility      float64   `gorm:"not null;comment:ความผันผวนของผลงาน (sigma)" json:"volatility"`
+	Wins            int       `gorm:"not null;default:0" json:"wins"`
+	Losses          int       `gorm:"not null;default:0" json:"losses"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PvpRatingHistory คือบันทึกการเปลี่ยนคะแนนหลังจบ Ranked match แต่ละครั้ง
+type PvpRatingHistory struct {
+	ID                  uint      `gorm:"primaryKey" json:"-"`
+	CharacterID         uint      `gorm:"not null;index;comment:ตัวละครเจ้าของประวัติ" json:"-"`
+	MatchID             uuid.UUID `gorm:"type:uuid;not null;index" json:"match_id"`
+	OpponentCharacterID uint      `gorm:"not null" json:"opponent_character_id"`
+	Won                 bool      `gorm:"not null" json:"won"`
+	RatingBefore        float64   `gorm:"not null" json:"rating_before"`
+	RatingAfter         float64   `gorm:"not null" json:"rating_after"`
+	CreatedAt           time.Time `gorm:"index" json:"created_at"`
+}
@@ -13,9 +13,15 @@ type Spell struct {
 	APCost       int               `gorm:"not null"`
 	MPCost       int               `gorm:"not null"`
 	TargetType   TargetType        `gorm:"size:50;not null;default:'ENEMY';comment:ประเภทเป้าหมาย (SELF, ENEMY, ALLY, etc.)"`
-	Element      *Element          `gorm:"foreignKey:ElementID;references:ID"`
-	Mastery      *Mastery          `gorm:"foreignKey:MasteryID;references:ID"`
-	Effects      []*SpellEffect    `gorm:"foreignKey:SpellID"`
+
+	// --- เงื่อนไขปลดล็อก (ค่า 0/false = ไม่มีเงื่อนไขข้อนั้น) ---
+	RequiredLevel        int  `gorm:"not null;default:0;comment:เลเวลตัวละครขั้นต่ำ"`
+	RequiredMasteryLevel int  `gorm:"not null;default:0;comment:เลเวลของศาสตร์ (MasteryID) ขั้นต่ำ"`
+	RequiresDiscovery    bool `gorm:"not null;default:false;comment:ต้องค้นพบสูตรหลอมธาตุ (ElementID) ใน Journal ก่อน"`
+
+	Element *Element       `gorm:"foreignKey:ElementID;references:ID"`
+	Mastery *Mastery       `gorm:"foreignKey:MasteryID;references:ID"`
+	Effects []*SpellEffect `gorm:"foreignKey:SpellID"`
 }
 
 // TargetType defines the possible targeting modes for spells and abilities.
@@ -30,3 +36,26 @@ const (
 	TargetTypeAllEnemies TargetType = "ALL_ENEMIES" // Targets all enemies on the field : "ศัตรูทั้งหมด"
 	TargetTypeAllAllies  TargetType = "ALL_ALLIES"  // Targets all allies on the field : "พันธมิตรทั้งหมด"
 )
+
+// IsMultiTarget บอกว่าเวทนี้ลงเป้าหมายหลายตัวในการร่ายครั้งเดียว
+func (t TargetType) IsMultiTarget() bool {
+	switch t {
+	case TargetTypeEnemyAOE, TargetTypeAllyAOE, TargetTypeAllEnemies, TargetTypeAllAllies:
+		return true
+	}
+	return false
+}
+
+// TargetsAllies บอกว่าเป้าหมายต้องอยู่ฝ่ายเดียวกับผู้ร่าย (SELF นับด้วย)
+func (t TargetType) TargetsAllies() bool {
+	switch t {
+	case TargetTypeSelf, TargetTypeAlly, TargetTypeAllyAOE, TargetTypeAllAllies:
+		return true
+	}
+	return false
+}
+
+// HasFalloff บอกว่าเป้ารองของเวทนี้โดนดาเมจลดลง (AOE มีเป้าหลัก ส่วน ALL_* โดนเท่ากันทุกตัว)
+func (t TargetType) HasFalloff() bool {
+	return t == TargetTypeEnemyAOE || t == TargetTypeAllyAOE
+}
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
 // SpellEffect คือตารางเชื่อมโยงที่บอกว่า "เวท" ใบนี้มี "เอฟเฟกต์" อะไรบ้าง
 type SpellEffect struct {
 	ID               uint    `gorm:"primaryKey"`
@@ -9,7 +15,44 @@ type SpellEffect struct {
 	BaseValue        float64 `gorm:"not null"`
 	DurationInTurns  int
 	ConditionType    ConditionType `gorm:"size:50;default:'NONE'"`
-	ConditionDetails string
+	ConditionDetails string        // JSON ของ SpellEffectCondition (ว่าง = ไม่มีรายละเอียด)
+}
+
+// SpellEffectCondition คือ ConditionDetails ที่ parse แล้ว ฟิลด์ที่ใช้ขึ้นกับ ConditionType:
+//   - SELF_HP_BELOW, SELF_HP_ABOVE, TARGET_HP_BELOW, TARGET_HP_ABOVE: {"value": 0.5} (สัดส่วน HP 0-1 ไม่ใช่ %)
+//   - SELF_AP_IS: {"value": 3} (AP ปัจจุบัน >= value)
+//   - SELF_HAS_BUFF, TARGET_HAS_DEBUFF: {"effect_id": 2202} (ไม่ระบุ = บัฟ/ดีบัฟใดก็ได้)
+//   - SELF_HAS_ELEMENT: {"element_id": 1} (ธาตุที่ผู้ร่ายมีใน Dimensional Seal ตอนเริ่ม match)
+//   - TURN_COUNT_IS: {"value": 3} หรือ {"parity": "EVEN"} / {"parity": "ODD"}
+//   - LAST_SPELL_WAS: {"spell_id": 9} (เวทที่ร่ายสำเร็จครั้งก่อนหน้า)
+//   - COMBO_COUNT_IS: {"value": 2} (จำนวนเวทที่ร่ายไปแล้วในเทิร์นนี้ == value)
+//   - RANDOM_CHANCE: {"chance": 0.3} (0-1)
+//   - NONE, TARGET_IS_GUARDED, TARGET_HAS_SHIELD, TARGET_IS_STAGGERED: ไม่มีรายละเอียด
+//   - ELEMENTAL_RESONANCE: ไม่มีรายละเอียด (ธาตุของเวทหรือธาตุในสูตรของมัน ตรงกับพรสวรรค์ที่สูงที่สุดของผู้ร่าย)
+type SpellEffectCondition struct {
+	Value     float64 `json:"value,omitempty"`
+	EffectID  *uint   `json:"effect_id,omitempty"`
+	ElementID uint    `json:"element_id,omitempty"`
+	SpellID   uint    `json:"spell_id,omitempty"`
+	Parity    string  `json:"parity,omitempty"`
+	Chance    float64 `json:"chance,omitempty"`
+}
+
+// ParseCondition อ่าน ConditionDetails (ฟิลด์ที่ไม่รู้จักถือว่าผิดรูปแบบ)
+func (se *SpellEffect) ParseCondition() (SpellEffectCondition, error) {
+	var condition SpellEffectCondition
+	if strings.TrimSpace(se.ConditionDetails) == "" {
+		return condition, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(se.ConditionDetails)))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&condition)
+	return condition, err
+}
+
+// HasCondition บอกว่า effect นี้มีเงื่อนไขก่อนทำงานหรือไม่ (ค่าว่างจากข้อมูลเก่า = NONE)
+func (se *SpellEffect) HasCondition() bool {
+	return se.ConditionType != "" && se.ConditionType != ConditionTypeNone
 }
 
 type ConditionType string
@@ -20,16 +63,16 @@ const (
 
 	// --- เงื่อนไขเกี่ยวกับ "ฝ่ายเรา" (Self) ---
 	ConditionTypeSelfHasBuff    ConditionType = "SELF_HAS_BUFF"    // ฝ่ายเรามีบัฟที่กำหนด
-	ConditionTypeSelfHPBelow    ConditionType = "SELF_HP_BELOW"    // HP ของเราต่ำกว่า % ที่กำหนด
-	ConditionTypeSelfHPAbove    ConditionType = "SELF_HP_ABOVE"    // HP ของเราสูงกว่า % ที่กำหนด
+	ConditionTypeSelfHPBelow    ConditionType = "SELF_HP_BELOW"    // HP ของเราต่ำกว่าสัดส่วนที่กำหนด (0-1)
+	ConditionTypeSelfHPAbove    ConditionType = "SELF_HP_ABOVE"    // HP ของเราสูงกว่าสัดส่วนที่กำหนด (0-1)
 	ConditionTypeSelfHasElement ConditionType = "SELF_HAS_ELEMENT" // เรามีธาตุที่กำหนดใน "มิติผนึก"
 	ConditionTypeSelfAPIs       ConditionType = "SELF_AP_IS"       // AP ของเรามากกว่าหรือเท่ากับค่าที่กำหนด
 
 	// --- เงื่อนไขเกี่ยวกับ "เป้าหมาย" (Target) ---
 	ConditionTypeTargetHasDebuff   ConditionType = "TARGET_HAS_DEBUFF"   // เป้าหมายติดดีบัฟที่กำหนด
-	ConditionTypeTargetHPBelow     ConditionType = "TARGET_HP_BELOW"     // HP ของเป้าหมายต่ำกว่า % ที่กำหนด
-	ConditionTypeTargetHPAbove     ConditionType = "TARGET_HP_ABOVE"     // HP ของเป้าหมายสูงกว่า % ที่กำหนด
-	ConditionTypeTargetIsGuarded   ConditionType = "TARGET_IS_GUARDED"   // เป้าหมายมี Shield อยู่
+	ConditionTypeTargetHPBelow     ConditionType = "TARGET_HP_BELOW"     // HP ของเป้าหมายต่ำกว่าสัดส่วนที่กำหนด (0-1)
+	ConditionTypeTargetHPAbove     ConditionType = "TARGET_HP_ABOVE"     // HP ของเป้าหมายสูงกว่าสัดส่วนที่กำหนด (0-1)
+	ConditionTypeTargetIsGuarded   ConditionType = "TARGET_IS_GUARDED"   // เป้าหมายมี Shield หรือกำลังตั้งรับ (DEFENSE_UP)
 	ConditionTypeTargetIsStaggered ConditionType = "TARGET_IS_STAGGERED" // เป้าหมายติดสถานะ "เสียหลัก"
 	ConditionTypeTargetHasShield   ConditionType = "TARGET_HAS_SHIELD"   // เป้าหมายมีเกราะป้องกัน (Shield) อยู่
 
@@ -42,3 +85,26 @@ const (
 	// --- เงื่อนไขแบบสุ่ม ---
 	ConditionTypeRandomChance ConditionType = "RANDOM_CHANCE" // มีโอกาส % ที่จะทำงาน
 )
+
+// IsKnown บอกว่า Combat Service ตรวจเงื่อนไขนี้ได้หรือไม่ (ใช้ตรวจเวทตอน seed)
+func (c ConditionType) IsKnown() bool {
+	switch c {
+	case ConditionTypeNone,
+		ConditionTypeSelfHasBuff, ConditionTypeSelfHPBelow, ConditionTypeSelfHPAbove, ConditionTypeSelfHasElement, ConditionTypeSelfAPIs,
+		ConditionTypeTargetHasDebuff, ConditionTypeTargetHPBelow, ConditionTypeTargetHPAbove,
+		ConditionTypeTargetIsGuarded, ConditionTypeTargetIsStaggered, ConditionTypeTargetHasShield,
+		ConditionTypeElementalResonance, ConditionTypeTurnCountIs, ConditionTypeLastSpellWas, ConditionTypeComboCountIs,
+		ConditionTypeRandomChance:
+		return true
+	}
+	return false
+}
+
+// ChecksHP บอกว่าเงื่อนไขนี้ใช้ value เป็นสัดส่วน HP (0.0 ถึง 1.0)
+func (c ConditionType) ChecksHP() bool {
+	switch c {
+	case ConditionTypeSelfHPBelow, ConditionTypeSelfHPAbove, ConditionTypeTargetHPBelow, ConditionTypeTargetHPAbove:
+		return true
+	}
+	return false
+}
@@ -1,11 +1,14 @@
 package character
 
 import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/pkg/appauth"
 	"sage-of-elements-backend/pkg/apperrors"
 	"sage-of-elements-backend/pkg/appresponse"
 	"sage-of-elements-backend/pkg/appvalidator"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -34,12 +37,24 @@ type CreateCharacterRequest struct {
 	InitialMasteryID uint   `json:"initial_mastery_id" validate:"required,min=1,max=4"`
 }
 
+// AllocateTalentsRequest คือจำนวนแต้มที่จะแจกให้พรสวรรค์แต่ละธาตุ (รวมกันต้องไม่เกินแต้มที่เหลือ)
+type AllocateTalentsRequest struct {
+	TalentS int `json:"talent_s" validate:"min=0"`
+	TalentL int `json:"talent_l" validate:"min=0"`
+	TalentG int `json:"talent_g" validate:"min=0"`
+	TalentP int `json:"talent_p" validate:"min=0"`
+}
+
 func (h *characterHandler) RegisterProtectedRoutes(router fiber.Router) {
 	router.Post("/", h.CreateCharacter)
 	router.Get("/", h.ListCharacters)
 	router.Get("/:id", h.GetCharacterByID)
 	router.Delete("/:id", h.DeleteCharacter)
 	router.Get("/:id/inventory", h.GetInventory)
+	router.Get("/:id/matches", h.GetMatchHistory)
+	router.Post("/:id/talents", h.AllocateTalents)
+	router.Post("/:id/talents/respec", h.RespecTalents)
+	router.Get("/:id/spells", h.GetSpells)
 
 }
 
@@ -206,3 +221,133 @@ func (h *characterHandler) SkipTutorial(c *fiber.Ctx) error {
 	// 3. ส่งข้อมูลตัวละครที่อัปเดตแล้วกลับไป
 	return appresponse.Success(c, fiber.StatusOK, "Tutorial skipped", updatedChar, nil)
 }
+
+// GetMatchHistory คือ Handler สำหรับดึงประวัติการต่อสู้ของตัวละครแบบแบ่งหน้า
+// Query: match_type (TRAINING/STORY/PVP), from, to (RFC3339 หรือ YYYY-MM-DD; to แบบวันที่นับรวมทั้งวัน), limit (ค่าเริ่มต้น 20, สูงสุด 100), offset
+func (h *characterHandler) GetMatchHistory(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	charID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid character ID format", nil)
+	}
+
+	filter := MatchHistoryFilter{
+		Limit:  c.QueryInt("limit", defaultMatchHistoryLimit),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if filter.Limit <= 0 || filter.Limit > maxMatchHistoryLimit {
+		return apperrors.InvalidFormatError(fmt.Sprintf("limit must be between 1 and %d", maxMatchHistoryLimit), nil)
+	}
+	if filter.Offset < 0 {
+		return apperrors.InvalidFormatError("offset must not be negative", nil)
+	}
+	if matchType := c.Query("match_type"); matchType != "" {
+		switch domain.MatchType(matchType) {
+		case domain.MatchTypeTraining, domain.MatchTypeStory, domain.MatchTypePVP:
+			mt := domain.MatchType(matchType)
+			filter.MatchType = &mt
+		default:
+			return apperrors.InvalidFormatError("match_type must be one of TRAINING, STORY, PVP", nil)
+		}
+	}
+	if filter.From, err = parseHistoryDate(c.Query("from"), false); err != nil {
+		return apperrors.InvalidFormatError("Invalid from date (use RFC3339 or YYYY-MM-DD)", nil)
+	}
+	if filter.To, err = parseHistoryDate(c.Query("to"), true); err != nil {
+		return apperrors.InvalidFormatError("Invalid to date (use RFC3339 or YYYY-MM-DD)", nil)
+	}
+
+	results, total, err := h.service.GetMatchHistory(claims.UserID, uint(charID), filter)
+	if err != nil {
+		return err
+	}
+
+	pagination := appresponse.NewPagePagination(int(total), filter.Limit, filter.Offset)
+	return appresponse.Success(c, fiber.StatusOK, "Match history retrieved successfully", results, pagination)
+}
+
+const (
+	defaultMatchHistoryLimit = 20
+	maxMatchHistoryLimit     = 100
+)
+
+// parseHistoryDate รับได้ทั้ง RFC3339 และ YYYY-MM-DD (ค่าว่าง = ไม่กรอง)
+// wholeDay = true: YYYY-MM-DD คืนเที่ยงคืนของวันถัดไป เพื่อให้ขอบเขตแบบไม่รวม (FinishedAt < To) ยังนับวันนั้นทั้งวัน
+func parseHistoryDate(value string, wholeDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if wholeDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// AllocateTalents คือ Handler สำหรับใช้แต้มพรสวรรค์ที่ยังไม่ได้แจกจ่าย
+func (h *characterHandler) AllocateTalents(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	charID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid character ID format", nil)
+	}
+
+	req := new(AllocateTalentsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return apperrors.InvalidFormatError("Cannot parse JSON", nil)
+	}
+	if validationResult := appvalidator.Validate(h.validator, req); !validationResult.IsValid {
+		return c.Status(fiber.StatusBadRequest).JSON(validationResult)
+	}
+
+	result, err := h.service.AllocateTalents(claims.UserID, uint(charID), TalentAllocation{
+		S: req.TalentS,
+		L: req.TalentL,
+		G: req.TalentG,
+		P: req.TalentP,
+	})
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Talent points allocated", result, nil)
+}
+
+// RespecTalents คือ Handler สำหรับรีเซ็ตพรสวรรค์กลับเป็นค่าเริ่มต้น (คืนแต้มเข้ากองกลาง)
+func (h *characterHandler) RespecTalents(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	charID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid character ID format", nil)
+	}
+
+	result, err := h.service.RespecTalents(claims.UserID, uint(charID))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Talents reset", result, nil)
+}
+
+// GetSpells คือ Handler สำหรับดูเวททั้งหมดของตัวละคร พร้อมสถานะปลดล็อกและเงื่อนไขที่ยังขาด
+func (h *characterHandler) GetSpells(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	charID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid character ID format", nil)
+	}
+
+	spells, err := h.service.GetSpells(claims.UserID, uint(charID))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Spells retrieved successfully", spells, nil)
+}
@@ -0,0 +1,195 @@
+// file: internal/modules/character/progression.go
+package character
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"strconv"
+)
+
+// ==================== Level Progression ====================
+// ไฟล์นี้รวมสูตร EXP/เลเวลของตัวละคร (ใช้ร่วมกันระหว่าง character service และ combat._EndMatch)
+// - Character.Exp คือ EXP "ภายในเลเวลปัจจุบัน" (หลอดเลเวลบน UI) ไม่ใช่ EXP สะสมทั้งหมด
+// - EXP ที่ต้องใช้จากเลเวล L → L+1 = PLAYER_BASE_EXP × PLAYER_EXP_GROWTH_RATE^(L-1)
+// - ขึ้นเลเวลละ TALENT_POINTS_PER_LEVEL แต้ม (เข้า UnallocatedTalentPoints)
+// - PLAYER_EXP_CARRY_OVER = false → EXP ที่เกินตอนขึ้นเลเวลจะถูกทิ้ง (ขึ้นได้ทีละ 1 เลเวลต่อครั้ง)
+// - ถึง PLAYER_MAX_LEVEL แล้ว EXP จะไม่สะสมอีก
+// - เลเวลศาสตร์ (CharacterMastery.Level/Mxp) ใช้สูตรเดียวกันแต่อ่านจาก MASTERY_* (ดู MasteryProgressionConfig)
+
+// ProgressionConfig คือค่าจาก game_configs ที่ใช้คำนวณเลเวล
+type ProgressionConfig struct {
+	BaseExp         int
+	GrowthRate      float64
+	MaxLevel        int
+	TalentsPerLevel int
+	CarryOverExcess bool
+}
+
+// LevelUpResult คือผลของการเพิ่ม EXP 1 ครั้ง
+type LevelUpResult struct {
+	LevelBefore        int `json:"level_before"`
+	LevelAfter         int `json:"level_after"`
+	LevelsGained       int `json:"levels_gained"`
+	TalentPointsGained int `json:"talent_points_gained"`
+	ExpToNextLevel     int `json:"exp_to_next_level"` // 0 = ถึงเลเวลสูงสุดแล้ว
+}
+
+// LoadProgressionConfig อ่านค่าเลเวลจาก game_configs (ใช้ค่า seed เป็น fallback)
+func LoadProgressionConfig(gameDataRepo game_data.GameDataRepository) ProgressionConfig {
+	return ProgressionConfig{
+		BaseExp:         configInt(gameDataRepo, "PLAYER_BASE_EXP", 100),
+		GrowthRate:      configFloat(gameDataRepo, "PLAYER_EXP_GROWTH_RATE", 1.15),
+		MaxLevel:        configInt(gameDataRepo, "PLAYER_MAX_LEVEL", 50),
+		TalentsPerLevel: configInt(gameDataRepo, "TALENT_POINTS_PER_LEVEL", 3),
+		CarryOverExcess: configBool(gameDataRepo, "PLAYER_EXP_CARRY_OVER", true),
+	}
+}
+
+// ExpToNextLevel คือ EXP ที่ต้องใช้จาก level ไป level+1 (0 ถ้าถึงเลเวลสูงสุดแล้ว)
+func (c ProgressionConfig) ExpToNextLevel(level int) int {
+	if level >= c.MaxLevel {
+		return 0
+	}
+	return levelThreshold(c.BaseExp, c.GrowthRate, level)
+}
+
+// ApplyExp เพิ่ม EXP ให้ตัวละคร แล้วขึ้นเลเวล (ได้หลายเลเวลในครั้งเดียว) ตามเงื่อนไขใน config
+// แก้ไข char โดยตรง ผู้เรียกต้องบันทึกเอง
+func ApplyExp(char *domain.Character, amount int, cfg ProgressionConfig) LevelUpResult {
+	if char.Level < 1 {
+		char.Level = 1
+	}
+	result := LevelUpResult{LevelBefore: char.Level}
+
+	if amount > 0 && char.Level < cfg.MaxLevel {
+		char.Exp += amount
+		for char.Level < cfg.MaxLevel {
+			required := cfg.ExpToNextLevel(char.Level)
+			if char.Exp < required {
+				break
+			}
+			char.Level++
+			char.UnallocatedTalentPoints += cfg.TalentsPerLevel
+			result.TalentPointsGained += cfg.TalentsPerLevel
+			if !cfg.CarryOverExcess {
+				char.Exp = 0
+				break
+			}
+			char.Exp -= required
+		}
+	}
+	if char.Level >= cfg.MaxLevel {
+		char.Exp = 0
+	}
+
+	result.LevelAfter = char.Level
+	result.LevelsGained = result.LevelAfter - result.LevelBefore
+	result.ExpToNextLevel = cfg.ExpToNextLevel(char.Level)
+	return result
+}
+
+// MasteryProgressionConfig คือค่าจาก game_configs ที่ใช้คำนวณเลเวลของศาสตร์ (Mastery)
+// MXP ที่ต้องใช้จากเลเวล L → L+1 = MASTERY_BASE_MXP × MASTERY_MXP_GROWTH_RATE^(L-1), MXP ส่วนเกินยกไปเลเวลถัดไปเสมอ
+type MasteryProgressionConfig struct {
+	BaseMxp    int
+	GrowthRate float64
+	MaxLevel   int
+}
+
+// LoadMasteryProgressionConfig อ่านค่าเลเวลศาสตร์จาก game_configs (ใช้ค่า seed เป็น fallback)
+func LoadMasteryProgressionConfig(gameDataRepo game_data.GameDataRepository) MasteryProgressionConfig {
+	return MasteryProgressionConfig{
+		BaseMxp:    configInt(gameDataRepo, "MASTERY_BASE_MXP", 100),
+		GrowthRate: configFloat(gameDataRepo, "MASTERY_MXP_GROWTH_RATE", 1.5),
+		MaxLevel:   configInt(gameDataRepo, "MASTERY_MAX_LEVEL", 10),
+	}
+}
+
+// MxpToNextLevel คือ MXP ที่ต้องใช้จาก level ไป level+1 (0 ถ้าถึงเลเวลสูงสุดแล้ว)
+func (c MasteryProgressionConfig) MxpToNextLevel(level int) int {
+	if level >= c.MaxLevel {
+		return 0
+	}
+	return levelThreshold(c.BaseMxp, c.GrowthRate, level)
+}
+
+// ApplyMasteryExp เพิ่ม MXP ให้ศาสตร์ 1 อย่างแล้วขึ้นเลเวล (แก้ไข mastery โดยตรง) คืนจำนวนเลเวลที่ขึ้น
+func ApplyMasteryExp(mastery *domain.CharacterMastery, amount int, cfg MasteryProgressionConfig) int {
+	if mastery.Level < 1 {
+		mastery.Level = 1
+	}
+	levelBefore := mastery.Level
+	if amount > 0 && mastery.Level < cfg.MaxLevel {
+		mastery.Mxp += amount
+		for mastery.Level < cfg.MaxLevel {
+			required := cfg.MxpToNextLevel(mastery.Level)
+			if mastery.Mxp < required {
+				break
+			}
+			mastery.Level++
+			mastery.Mxp -= required
+		}
+	}
+	if mastery.Level >= cfg.MaxLevel {
+		mastery.Mxp = 0
+	}
+	return mastery.Level - levelBefore
+}
+
+// FillProgress เติมค่าที่คำนวณตอนอ่าน (EXP/MXP ที่ต้องใช้ขึ้นเลเวลถัดไป) ให้หลอดความคืบหน้าบน UI
+func FillProgress(char *domain.Character, gameDataRepo game_data.GameDataRepository) {
+	char.ExpToNextLevel = LoadProgressionConfig(gameDataRepo).ExpToNextLevel(char.Level)
+	masteryCfg := LoadMasteryProgressionConfig(gameDataRepo)
+	for _, mastery := range char.Masteries {
+		mastery.MxpToNextLevel = masteryCfg.MxpToNextLevel(mastery.Level)
+	}
+}
+
+// levelThreshold คือค่าที่ต้องใช้ขึ้นจาก level ไป level+1 ตามสูตร base × growth^(level-1) (อย่างน้อย 1)
+func levelThreshold(base int, growth float64, level int) int {
+	if level < 1 {
+		level = 1
+	}
+	required := int(math.Round(float64(base) * math.Pow(growth, float64(level-1))))
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+func configInt(gameDataRepo game_data.GameDataRepository, key string, fallback int) int {
+	valueStr, err := gameDataRepo.GetGameConfigValue(key)
+	if err != nil {
+		return fallback
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func configFloat(gameDataRepo game_data.GameDataRepository, key string, fallback float64) float64 {
+	valueStr, err := gameDataRepo.GetGameConfigValue(key)
+	if err != nil {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func configBool(gameDataRepo game_data.GameDataRepository, key string, fallback bool) bool {
+	valueStr, err := gameDataRepo.GetGameConfigValue(key)
+	if err != nil {
+		return fallback
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
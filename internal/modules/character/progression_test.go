@@ -0,0 +1,88 @@
+package character
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+)
+
+func TestApplyExp(t *testing.T) {
+	// EXP ต่อเลเวล: 1→2 = 100, 2→3 = 150, 3→4 = 225, 4→5 = 338
+	carry := ProgressionConfig{BaseExp: 100, GrowthRate: 1.5, MaxLevel: 5, TalentsPerLevel: 3, CarryOverExcess: true}
+	noCarry := carry
+	noCarry.CarryOverExcess = false
+
+	tests := []struct {
+		name       string
+		cfg        ProgressionConfig
+		level, exp int
+		amount     int
+		wantLevel  int
+		wantExp    int
+		wantGained int
+		wantNext   int
+	}{
+		{name: "below threshold", cfg: carry, level: 1, exp: 0, amount: 50, wantLevel: 1, wantExp: 50, wantGained: 0, wantNext: 100},
+		{name: "exact threshold", cfg: carry, level: 1, exp: 0, amount: 100, wantLevel: 2, wantExp: 0, wantGained: 1, wantNext: 150},
+		{name: "multi level up carries excess", cfg: carry, level: 1, exp: 20, amount: 300, wantLevel: 3, wantExp: 70, wantGained: 2, wantNext: 225},
+		{name: "no carry over drops excess", cfg: noCarry, level: 1, exp: 20, amount: 300, wantLevel: 2, wantExp: 0, wantGained: 1, wantNext: 150},
+		{name: "stops at max level", cfg: carry, level: 4, exp: 300, amount: 1000, wantLevel: 5, wantExp: 0, wantGained: 1, wantNext: 0},
+		{name: "max level ignores exp", cfg: carry, level: 5, exp: 0, amount: 100, wantLevel: 5, wantExp: 0, wantGained: 0, wantNext: 0},
+		{name: "non-positive amount changes nothing", cfg: carry, level: 2, exp: 10, amount: -50, wantLevel: 2, wantExp: 10, wantGained: 0, wantNext: 150},
+		{name: "level zero counts as level one", cfg: carry, level: 0, exp: 0, amount: 0, wantLevel: 1, wantExp: 0, wantGained: 0, wantNext: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			char := &domain.Character{Level: tt.level, Exp: tt.exp, UnallocatedTalentPoints: 1}
+			result := ApplyExp(char, tt.amount, tt.cfg)
+
+			if char.Level != tt.wantLevel || char.Exp != tt.wantExp {
+				t.Errorf("level/exp = %d/%d, want %d/%d", char.Level, char.Exp, tt.wantLevel, tt.wantExp)
+			}
+			if result.LevelsGained != tt.wantGained || result.LevelAfter != tt.wantLevel {
+				t.Errorf("result levels gained/after = %d/%d, want %d/%d", result.LevelsGained, result.LevelAfter, tt.wantGained, tt.wantLevel)
+			}
+			if want := tt.wantGained * tt.cfg.TalentsPerLevel; result.TalentPointsGained != want || char.UnallocatedTalentPoints != 1+want {
+				t.Errorf("talent points gained = %d (unallocated %d), want %d (unallocated %d)",
+					result.TalentPointsGained, char.UnallocatedTalentPoints, want, 1+want)
+			}
+			if result.ExpToNextLevel != tt.wantNext {
+				t.Errorf("exp to next level = %d, want %d", result.ExpToNextLevel, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestApplyMasteryExp(t *testing.T) {
+	// MXP ต่อเลเวล: 1→2 = 100, 2→3 = 150, 3→4 = 225 (MXP ส่วนเกินยกไปเสมอ)
+	cfg := MasteryProgressionConfig{BaseMxp: 100, GrowthRate: 1.5, MaxLevel: 4}
+
+	tests := []struct {
+		name       string
+		level, mxp int
+		amount     int
+		wantLevel  int
+		wantMxp    int
+		wantGained int
+	}{
+		{name: "below threshold", level: 1, mxp: 0, amount: 99, wantLevel: 1, wantMxp: 99, wantGained: 0},
+		{name: "multi level up carries excess", level: 1, mxp: 0, amount: 260, wantLevel: 3, wantMxp: 10, wantGained: 2},
+		{name: "stops at max level", level: 3, mxp: 200, amount: 500, wantLevel: 4, wantMxp: 0, wantGained: 1},
+		{name: "max level ignores mxp", level: 4, mxp: 0, amount: 50, wantLevel: 4, wantMxp: 0, wantGained: 0},
+		{name: "level zero counts as level one", level: 0, mxp: 0, amount: 100, wantLevel: 2, wantMxp: 0, wantGained: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mastery := &domain.CharacterMastery{Level: tt.level, Mxp: tt.mxp}
+			gained := ApplyMasteryExp(mastery, tt.amount, cfg)
+
+			if mastery.Level != tt.wantLevel || mastery.Mxp != tt.wantMxp {
+				t.Errorf("level/mxp = %d/%d, want %d/%d", mastery.Level, mastery.Mxp, tt.wantLevel, tt.wantMxp)
+			}
+			if gained != tt.wantGained {
+				t.Errorf("levels gained = %d, want %d", gained, tt.wantGained)
+			}
+		})
+	}
+}
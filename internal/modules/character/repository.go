@@ -3,21 +3,41 @@ package character
 import (
 	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/pkg/apperrors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrTalentConflict คือ error จาก SaveTalents เมื่อแต้มพรสวรรค์ถูกเปลี่ยนโดย request อื่นหลังจากที่โหลดตัวละครมา
+var ErrTalentConflict = apperrors.New(409, "TALENT_CONFLICT", "talents were changed by another request, please retry")
+
+// MatchHistoryFilter คือเงื่อนไขค้นประวัติการต่อสู้ (ค่า nil = ไม่กรอง)
+type MatchHistoryFilter struct {
+	MatchType *domain.MatchType
+	From      *time.Time // FinishedAt >= From
+	To        *time.Time // FinishedAt < To
+	Limit     int
+	Offset    int
+}
+
 type CharacterRepository interface {
 	// ฟังก์ชันที่มีอยู่แล้ว
 	CheckCharacterExists(name string) (*domain.Character, error)
 	Create(character *domain.Character) (*domain.Character, error)
 	Save(character *domain.Character) (*domain.Character, error)
+	SaveTalents(character *domain.Character, previousUnallocated int) error // บันทึกเฉพาะพรสวรรค์/HP/MP/เวลารีเซ็ต เมื่อแต้มที่ยังไม่แจกใน DB ยังเท่ากับ previousUnallocated (ไม่ตรง = ErrTalentConflict)
 	FindAllByPlayerID(playerID uint) ([]domain.Character, error)
 	FindByID(id uint) (*domain.Character, error)
 	Delete(characterID uint) error
 	FindInventoryByCharacterID(characterID uint) ([]*domain.DimensionalSealInventory, error)
 	UpdateCharacterInTx(tx *gorm.DB, character *domain.Character) error
 	ConsumeAndUpdateInventoryInTx(tx *gorm.DB, characterID uint, itemsToConsume map[uint]int, itemsToAdd map[uint]int) error
+	FindMatchResults(characterID uint, filter MatchHistoryFilter) ([]domain.MatchResult, int64, error) // ประวัติการต่อสู้ล่าสุดก่อน + จำนวนทั้งหมดที่ตรงเงื่อนไข
+	HasActiveMatch(characterID uint) (bool, error)                                                     // ตัวละครกำลังเล่น match ที่ยังไม่จบอยู่หรือไม่ (ไม่นับตอนถูกท้าดวลโดย AI)
+	FindSpellUnlocks(characterID uint) ([]*domain.CharacterSpellUnlock, error)                         // เวทที่ตัวละครปลดล็อกแล้ว
+	UnlockSpells(characterID uint, spellIDs []uint) error                                              // บันทึกการปลดล็อกเวท (ข้ามเวทที่ปลดล็อกไปแล้ว)
+	FindDiscoveredElementIDs(characterID uint) ([]uint, error)                                         // ธาตุผลลัพธ์ของสูตรที่ค้นพบแล้วใน Journal
 
 	// --- ⭐️ เพิ่มแค่ฟังก์ชันนี้เข้ามา! ⭐️ ---
 	// ฟังก์ชันใหม่สำหรับคำนวณและบันทึกค่าพลังที่ฟื้นฟู
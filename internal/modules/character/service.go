@@ -2,6 +2,8 @@ package character
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"sage-of-elements-backend/internal/domain"
@@ -20,6 +22,10 @@ type CharacterService interface {
 	GetInventory(playerID, characterID uint) (*InventoryResponse, error)
 	AdvanceTutorialStep(playerID, characterID uint) (*domain.Character, error)
 	SkipTutorial(playerID, characterID uint) (*domain.Character, error)
+	GetMatchHistory(playerID, characterID uint, filter MatchHistoryFilter) ([]domain.MatchResult, int64, error)
+	AllocateTalents(playerID, characterID uint, allocation TalentAllocation) (*TalentsResponse, error)
+	RespecTalents(playerID, characterID uint) (*TalentsResponse, error)
+	GetSpells(playerID, characterID uint) ([]SpellUnlockStatus, error)
 }
 
 // characterService คือ struct ที่จะเก็บ Logic การทำงานจริง
@@ -36,6 +42,21 @@ type InventoryResponse struct {
 	Inventory   []*domain.DimensionalSealInventory `json:"inventory"`
 }
 
+// TalentAllocation คือจำนวนแต้มที่จะเพิ่มให้พรสวรรค์แต่ละธาตุ
+type TalentAllocation struct {
+	S int
+	L int
+	G int
+	P int
+}
+
+// TalentsResponse คือผลหลังแจกแต้ม/รีเซ็ตพรสวรรค์ พร้อมค่าพลังที่คำนวณใหม่
+type TalentsResponse struct {
+	Character    *domain.Character `json:"character"`
+	Stats        DerivedStats      `json:"stats"`
+	NextRespecAt *time.Time        `json:"next_respec_at,omitempty"` // nil = รีเซ็ตได้ทันที
+}
+
 // NewCharacterService คือฟังก์ชันสำหรับสร้าง Service ขึ้นมาใช้งาน
 func NewCharacterService(appLogger applogger.Logger, repoCharacter CharacterRepository, repoGameData game_data.GameDataRepository) CharacterService {
 	return &characterService{
@@ -74,22 +95,10 @@ func (s *characterService) CreateCharacter(playerID uint, name string, gender st
 	}
 
 	// --- ⭐️ ส่วนที่เพิ่มเข้ามา ⭐️ ---
-	// 5. คำนวณ MaxHP/MP และตั้งค่า HP/MP เริ่มต้น
-	baseHpStr, _ := s.repoGameData.GetGameConfigValue("STAT_HP_BASE")
-	hpPerTalentSStr, _ := s.repoGameData.GetGameConfigValue("STAT_HP_PER_TALENT_S")
-	baseMpStr, _ := s.repoGameData.GetGameConfigValue("STAT_MP_BASE")
-	mpPerTalentLStr, _ := s.repoGameData.GetGameConfigValue("STAT_MP_PER_TALENT_L")
-
-	baseHp, _ := strconv.Atoi(baseHpStr)
-	hpPerTalentS, _ := strconv.Atoi(hpPerTalentSStr)
-	baseMp, _ := strconv.Atoi(baseMpStr)
-	mpPerTalentL, _ := strconv.Atoi(mpPerTalentLStr)
-
-	maxHP := baseHp + (newCharacter.TalentS * hpPerTalentS)
-	maxMP := baseMp + (newCharacter.TalentL * mpPerTalentL)
-
-	newCharacter.CurrentHP = maxHP
-	newCharacter.CurrentMP = maxMP
+	// 5. คำนวณ MaxHP/MP และตั้งค่า HP/MP เริ่มต้น (ดู stats.go)
+	stats := CalculateDerivedStats(newCharacter, s.repoGameData)
+	newCharacter.CurrentHP = stats.MaxHP
+	newCharacter.CurrentMP = stats.MaxMP
 	// ---------------------------------
 
 	// 6. สร้างข้อมูล Mastery เริ่มต้น
@@ -108,6 +117,7 @@ func (s *characterService) CreateCharacter(playerID uint, name string, gender st
 		return nil, errors.New("failed to save character to database")
 	}
 
+	FillProgress(savedCharacter, s.repoGameData)
 	return savedCharacter, nil
 }
 
@@ -143,6 +153,9 @@ func (s *characterService) ListCharacters(playerID uint) ([]domain.Character, er
 	}
 
 	// 3. คืนค่า Slice ของ Characters ที่หาเจอ (อาจจะเป็น Slice ว่างๆ ถ้ายังไม่มีตัวละคร)
+	for i := range characters {
+		FillProgress(&characters[i], s.repoGameData)
+	}
 	return characters, nil
 }
 
@@ -169,7 +182,8 @@ func (s *characterService) GetCharacterByID(playerID, characterID uint) (*domain
 		return nil, apperrors.PermissionDeniedError("you are not the owner of this character")
 	}
 
-	// 4. คืนค่า Character ที่หาเจอ
+	// 4. คืนค่า Character ที่หาเจอ (พร้อม EXP/MXP ที่ต้องใช้ขึ้นเลเวลถัดไปสำหรับหลอดความคืบหน้า)
+	FillProgress(character, s.repoGameData)
 	return character, nil
 }
 
@@ -330,9 +344,14 @@ func (s *characterService) GrantExp(characterID uint, expAmount int) error {
 		return apperrors.NotFoundError("character not found")
 	}
 
-	// 2. เพิ่ม EXP
-	character.Exp += expAmount
-	s.appLogger.Info("granted exp to character", "character_id", characterID, "exp_amount", expAmount, "new_total", character.Exp)
+	// 2. เพิ่ม EXP และขึ้นเลเวลตามสูตร (ดู progression.go)
+	levelUp := ApplyExp(character, expAmount, LoadProgressionConfig(s.repoGameData))
+	s.appLogger.Info("granted exp to character",
+		"character_id", characterID,
+		"exp_amount", expAmount,
+		"level", character.Level,
+		"levels_gained", levelUp.LevelsGained,
+	)
 
 	// 3. บันทึกกลับเข้าฐานข้อมูล
 	_, err = s.repoCharacter.Save(character)
@@ -343,3 +362,220 @@ func (s *characterService) GrantExp(characterID uint, expAmount int) error {
 
 	return nil
 }
+
+// GetMatchHistory ดึงสรุปผลการต่อสู้ของตัวละคร (ล่าสุดก่อน) พร้อมจำนวนทั้งหมดสำหรับแบ่งหน้า
+func (s *characterService) GetMatchHistory(playerID, characterID uint, filter MatchHistoryFilter) ([]domain.MatchResult, int64, error) {
+	character, err := s.repoCharacter.FindByID(characterID)
+	if err != nil {
+		s.appLogger.Error("failed to find character by id for match history", err)
+		return nil, 0, apperrors.SystemError("failed to retrieve character")
+	}
+	if character == nil {
+		return nil, 0, apperrors.NotFoundError("character not found")
+	}
+	if character.PlayerID != playerID {
+		return nil, 0, apperrors.PermissionDeniedError("you are not the owner of this character")
+	}
+
+	results, total, err := s.repoCharacter.FindMatchResults(characterID, filter)
+	if err != nil {
+		s.appLogger.Error("failed to find match history", err, "character_id", characterID)
+		return nil, 0, apperrors.SystemError("failed to retrieve match history")
+	}
+	if results == nil {
+		results = []domain.MatchResult{}
+	}
+	return results, total, nil
+}
+
+// GetSpells คืนเวททั้งหมดพร้อมสถานะปลดล็อกและเงื่อนไข (บันทึกการปลดล็อกของเวทที่เพิ่งครบเงื่อนไขไปด้วย)
+func (s *characterService) GetSpells(playerID, characterID uint) ([]SpellUnlockStatus, error) {
+	char, err := s.GetCharacterByID(playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. โหลดเวททั้งหมด, เวทที่ปลดล็อกแล้ว และธาตุที่ค้นพบสูตรแล้ว
+	spells, err := s.repoGameData.FindAllSpells()
+	if err != nil {
+		s.appLogger.Error("failed to find spells", err)
+		return nil, apperrors.SystemError("failed to retrieve spells")
+	}
+	unlocks, err := s.repoCharacter.FindSpellUnlocks(char.ID)
+	if err != nil {
+		s.appLogger.Error("failed to find spell unlocks", err, "char_id", char.ID)
+		return nil, apperrors.SystemError("failed to retrieve spell unlocks")
+	}
+	discovered, err := discoveredElementSet(char.ID, nil, s.repoCharacter)
+	if err != nil {
+		s.appLogger.Error("failed to find journal discoveries", err, "char_id", char.ID)
+		return nil, apperrors.SystemError("failed to retrieve journal discoveries")
+	}
+	unlockedAt := make(map[uint]time.Time, len(unlocks))
+	for _, unlock := range unlocks {
+		unlockedAt[unlock.SpellID] = unlock.UnlockedAt
+	}
+
+	// 2. ประเมินทีละเวท
+	sort.Slice(spells, func(i, j int) bool { return spells[i].ID < spells[j].ID })
+	statuses := make([]SpellUnlockStatus, 0, len(spells))
+	var newlyUnlocked []uint
+	now := time.Now()
+	for i := range spells {
+		spell := &spells[i]
+		status := SpellUnlockStatus{Spell: spell, Requirements: NewSpellRequirements(spell)}
+		switch at, ok := unlockedAt[spell.ID]; {
+		case !HasUnlockRequirements(spell):
+			status.Unlocked = true
+		case ok:
+			status.Unlocked = true
+			status.UnlockedAt = &at
+		default:
+			status.MissingRequirements = MissingSpellRequirements(char, spell, discovered)
+			if len(status.MissingRequirements) == 0 {
+				status.Unlocked = true
+				status.UnlockedAt = &now
+				newlyUnlocked = append(newlyUnlocked, spell.ID)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	// 3. บันทึกเวทที่เพิ่งครบเงื่อนไข
+	if len(newlyUnlocked) > 0 {
+		if err := s.repoCharacter.UnlockSpells(char.ID, newlyUnlocked); err != nil {
+			s.appLogger.Error("failed to save spell unlocks", err, "char_id", char.ID)
+			return nil, apperrors.SystemError("failed to save spell unlocks")
+		}
+		s.appLogger.Info("Unlocked spells", "char_id", char.ID, "spell_ids", newlyUnlocked)
+	}
+	return statuses, nil
+}
+
+// AllocateTalents ใช้แต้มพรสวรรค์ที่ยังไม่ได้แจกจ่ายเพิ่มให้ TalentS/L/G/P แล้วคำนวณค่าพลังใหม่
+func (s *characterService) AllocateTalents(playerID, characterID uint, allocation TalentAllocation) (*TalentsResponse, error) {
+	// 1. ตรวจจำนวนแต้มที่ขอ
+	if allocation.S < 0 || allocation.L < 0 || allocation.G < 0 || allocation.P < 0 {
+		return nil, apperrors.InvalidFormatError("talent points must not be negative", nil)
+	}
+	total := allocation.S + allocation.L + allocation.G + allocation.P
+	if total == 0 {
+		return nil, apperrors.InvalidFormatError("allocate at least 1 talent point", nil)
+	}
+
+	// 2. ดึงตัวละคร (ตรวจความเป็นเจ้าของ) และห้ามแก้ระหว่างต่อสู้
+	char, err := s._GetTalentEditableCharacter(playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if total > char.UnallocatedTalentPoints {
+		return nil, apperrors.New(422, "INSUFFICIENT_TALENT_POINTS",
+			fmt.Sprintf("not enough unallocated talent points: have %d, need %d", char.UnallocatedTalentPoints, total))
+	}
+
+	// 3. แจกแต้ม
+	previousUnallocated := char.UnallocatedTalentPoints
+	char.TalentS += allocation.S
+	char.TalentL += allocation.L
+	char.TalentG += allocation.G
+	char.TalentP += allocation.P
+	char.UnallocatedTalentPoints -= total
+
+	s.appLogger.Info("Allocated talent points",
+		"char_id", char.ID,
+		"s", allocation.S, "l", allocation.L, "g", allocation.G, "p", allocation.P,
+		"remaining", char.UnallocatedTalentPoints,
+	)
+	return s._SaveTalentChange(char, previousUnallocated)
+}
+
+// RespecTalents คืนแต้มพรสวรรค์ทั้งหมดที่เกินค่าเริ่มต้นของธาตุหลักกลับเข้ากองกลาง (มี cooldown ตาม TALENT_RESPEC_COOLDOWN_HOURS)
+func (s *characterService) RespecTalents(playerID, characterID uint) (*TalentsResponse, error) {
+	char, err := s._GetTalentEditableCharacter(playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. ตรวจ cooldown
+	if nextRespecAt := s._NextRespecAt(char); nextRespecAt != nil && time.Now().Before(*nextRespecAt) {
+		return nil, apperrors.NewWithDetails(429, "RESPEC_ON_COOLDOWN", "talent respec is on cooldown",
+			map[string]interface{}{"next_respec_at": nextRespecAt})
+	}
+
+	// 2. คำนวณแต้มที่คืนได้ (เฉพาะส่วนที่เกินค่าเริ่มต้น)
+	baseS, baseL, baseG, baseP := calculateInitialTalents(char.PrimaryElementID)
+	refund := refundableTalents(char.TalentS, baseS) + refundableTalents(char.TalentL, baseL) +
+		refundableTalents(char.TalentG, baseG) + refundableTalents(char.TalentP, baseP)
+	if refund == 0 {
+		return nil, apperrors.New(422, "NOTHING_TO_RESPEC", "character has no allocated talent points to refund")
+	}
+
+	// 3. รีเซ็ตกลับเป็นค่าเริ่มต้น แล้วเริ่มนับ cooldown
+	previousUnallocated := char.UnallocatedTalentPoints
+	char.TalentS = min(char.TalentS, baseS)
+	char.TalentL = min(char.TalentL, baseL)
+	char.TalentG = min(char.TalentG, baseG)
+	char.TalentP = min(char.TalentP, baseP)
+	char.UnallocatedTalentPoints += refund
+	now := time.Now()
+	char.LastRespecAt = &now
+
+	s.appLogger.Info("Respec talents", "char_id", char.ID, "refunded", refund)
+	return s._SaveTalentChange(char, previousUnallocated)
+}
+
+// _GetTalentEditableCharacter ดึงตัวละครของผู้เล่น และปฏิเสธถ้ากำลังอยู่ใน match (ค่าพลังถูกใช้อยู่)
+func (s *characterService) _GetTalentEditableCharacter(playerID, characterID uint) (*domain.Character, error) {
+	char, err := s.GetCharacterByID(playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+	inMatch, err := s.repoCharacter.HasActiveMatch(char.ID)
+	if err != nil {
+		s.appLogger.Error("failed to check active match for talent change", err, "char_id", char.ID)
+		return nil, apperrors.SystemError("failed to check active match")
+	}
+	if inMatch {
+		return nil, apperrors.New(409, "MATCH_IN_PROGRESS", "cannot change talents during an active match")
+	}
+	return char, nil
+}
+
+// _SaveTalentChange คำนวณค่าพลังใหม่ ปรับ HP/MP ปัจจุบันไม่ให้เกิน แล้วบันทึก
+// previousUnallocated = แต้มที่ยังไม่แจกตอนโหลดตัวละคร (request อื่นแก้พรสวรรค์ไปก่อน → 409 TALENT_CONFLICT ให้ client ส่งใหม่)
+func (s *characterService) _SaveTalentChange(char *domain.Character, previousUnallocated int) (*TalentsResponse, error) {
+	stats := CalculateDerivedStats(char, s.repoGameData)
+	clampToDerivedStats(char, stats)
+
+	if err := s.repoCharacter.SaveTalents(char, previousUnallocated); err != nil {
+		if errors.Is(err, ErrTalentConflict) {
+			return nil, err
+		}
+		s.appLogger.Error("failed to save character after talent change", err, "char_id", char.ID)
+		return nil, apperrors.SystemError("failed to update talents")
+	}
+	FillProgress(char, s.repoGameData)
+	return &TalentsResponse{
+		Character:    char,
+		Stats:        stats,
+		NextRespecAt: s._NextRespecAt(char),
+	}, nil
+}
+
+// _NextRespecAt คือเวลาที่รีเซ็ตพรสวรรค์ได้ครั้งถัดไป (nil = ยังไม่เคยรีเซ็ตหรือไม่มี cooldown)
+func (s *characterService) _NextRespecAt(char *domain.Character) *time.Time {
+	cooldownHours := configInt(s.repoGameData, "TALENT_RESPEC_COOLDOWN_HOURS", 0)
+	if char.LastRespecAt == nil || cooldownHours <= 0 {
+		return nil
+	}
+	next := char.LastRespecAt.Add(time.Duration(cooldownHours) * time.Hour)
+	return &next
+}
+
+// refundableTalents คือแต้มที่เกินค่าเริ่มต้น (ไม่ติดลบ)
+func refundableTalents(current, base int) int {
+	if current <= base {
+		return 0
+	}
+	return current - base
+}
@@ -0,0 +1,121 @@
+// file: internal/modules/character/spells.go
+package character
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"time"
+)
+
+// ==================== Spell Unlocks ====================
+// เงื่อนไขปลดล็อกเวทอยู่ใน domain.Spell (RequiredLevel, RequiredMasteryLevel, RequiresDiscovery)
+// - เวทที่ไม่มีเงื่อนไข (T0) ร่ายได้เสมอ ไม่ต้องมี CharacterSpellUnlock
+// - เวทที่มีเงื่อนไข: ครบเงื่อนไขครั้งแรก (ตอนดูรายการเวทหรือตอนร่าย) จะบันทึก CharacterSpellUnlock ไว้ แล้วไม่ล็อกกลับอีก
+// - combat.PrepareAndValidateCast ตรวจผ่าน CheckSpellUnlock และตอบ SPELL_LOCKED ถ้ายังไม่ครบ
+
+// รหัสเงื่อนไขที่ยังไม่ครบ (ใช้ใน missing_requirements)
+const (
+	SpellRequirementLevel        = "CHARACTER_LEVEL"
+	SpellRequirementMasteryLevel = "MASTERY_LEVEL"
+	SpellRequirementDiscovery    = "RECIPE_DISCOVERY"
+)
+
+// SpellRequirements คือเงื่อนไขปลดล็อกของเวท 1 ตัว (ค่า 0/false = ไม่มีเงื่อนไขข้อนั้น)
+type SpellRequirements struct {
+	CharacterLevel    int  `json:"character_level"`
+	MasteryID         uint `json:"mastery_id"`
+	MasteryLevel      int  `json:"mastery_level"`
+	RequiresDiscovery bool `json:"requires_discovery"` // ต้องค้นพบสูตรที่สร้างธาตุของเวทนี้ (spell.ElementID)
+}
+
+// SpellUnlockStatus คือสถานะการปลดล็อกเวท 1 ตัวของตัวละคร
+type SpellUnlockStatus struct {
+	Spell               *domain.Spell     `json:"spell"`
+	Unlocked            bool              `json:"unlocked"`
+	UnlockedAt          *time.Time        `json:"unlocked_at,omitempty"` // nil = ไม่มีเงื่อนไข หรือยังไม่ปลดล็อก
+	Requirements        SpellRequirements `json:"requirements"`
+	MissingRequirements []string          `json:"missing_requirements,omitempty"`
+}
+
+// NewSpellRequirements อ่านเงื่อนไขปลดล็อกจากเวท
+func NewSpellRequirements(spell *domain.Spell) SpellRequirements {
+	return SpellRequirements{
+		CharacterLevel:    spell.RequiredLevel,
+		MasteryID:         spell.MasteryID,
+		MasteryLevel:      spell.RequiredMasteryLevel,
+		RequiresDiscovery: spell.RequiresDiscovery,
+	}
+}
+
+// HasUnlockRequirements บอกว่าเวทนี้ต้องปลดล็อกก่อนร่ายหรือไม่
+func HasUnlockRequirements(spell *domain.Spell) bool {
+	return spell.RequiredLevel > 0 || spell.RequiredMasteryLevel > 0 || spell.RequiresDiscovery
+}
+
+// MissingSpellRequirements คืนรหัสเงื่อนไขที่ตัวละครยังไม่ครบ (ว่าง = ปลดล็อกได้)
+// discoveredElements คือธาตุที่ค้นพบสูตรแล้ว (ดู CharacterRepository.FindDiscoveredElementIDs)
+func MissingSpellRequirements(char *domain.Character, spell *domain.Spell, discoveredElements map[uint]bool) []string {
+	var missing []string
+	if char.Level < spell.RequiredLevel {
+		missing = append(missing, SpellRequirementLevel)
+	}
+	if spell.RequiredMasteryLevel > 0 && masteryLevel(char, spell.MasteryID) < spell.RequiredMasteryLevel {
+		missing = append(missing, SpellRequirementMasteryLevel)
+	}
+	if spell.RequiresDiscovery && !discoveredElements[spell.ElementID] {
+		missing = append(missing, SpellRequirementDiscovery)
+	}
+	return missing
+}
+
+// CheckSpellUnlock ตรวจว่าตัวละครร่ายเวทนี้ได้หรือไม่ แล้วบันทึกการปลดล็อกถ้าเพิ่งครบเงื่อนไข
+// คืนรหัสเงื่อนไขที่ยังไม่ครบ (ว่าง = ร่ายได้)
+func CheckSpellUnlock(char *domain.Character, spell *domain.Spell, repo CharacterRepository) ([]string, error) {
+	if !HasUnlockRequirements(spell) {
+		return nil, nil
+	}
+
+	unlocks, err := repo.FindSpellUnlocks(char.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, unlock := range unlocks {
+		if unlock.SpellID == spell.ID {
+			return nil, nil
+		}
+	}
+
+	discovered, err := discoveredElementSet(char.ID, spell, repo)
+	if err != nil {
+		return nil, err
+	}
+	if missing := MissingSpellRequirements(char, spell, discovered); len(missing) > 0 {
+		return missing, nil
+	}
+	return nil, repo.UnlockSpells(char.ID, []uint{spell.ID})
+}
+
+// discoveredElementSet โหลดธาตุที่ค้นพบแล้ว (เฉพาะเมื่อเวทต้องใช้)
+func discoveredElementSet(characterID uint, spell *domain.Spell, repo CharacterRepository) (map[uint]bool, error) {
+	discovered := make(map[uint]bool)
+	if spell != nil && !spell.RequiresDiscovery {
+		return discovered, nil
+	}
+	elementIDs, err := repo.FindDiscoveredElementIDs(characterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, elementID := range elementIDs {
+		discovered[elementID] = true
+	}
+	return discovered, nil
+}
+
+// masteryLevel คือเลเวลศาสตร์ของตัวละคร (0 = ยังไม่เคยฝึกศาสตร์นี้)
+func masteryLevel(char *domain.Character, masteryID uint) int {
+	for _, mastery := range char.Masteries {
+		if mastery.MasteryID == masteryID {
+			return mastery.Level
+		}
+	}
+	return 0
+}
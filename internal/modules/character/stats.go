@@ -0,0 +1,39 @@
+// file: internal/modules/character/stats.go
+package character
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+)
+
+// ==================== Derived Stats ====================
+// ค่าพลังที่คำนวณจากแต้มพรสวรรค์ (ใช้ร่วมกันระหว่างการสร้างตัวละคร, การแจกแต้ม/รีเซ็ต และการสร้าง Combatant)
+// - MaxHP      = STAT_HP_BASE + TalentS × STAT_HP_PER_TALENT_S
+// - MaxMP      = STAT_MP_BASE + TalentL × STAT_MP_PER_TALENT_L
+// - Initiative = STAT_INITIATIVE_BASE + TalentG × STAT_INITIATIVE_PER_TALENT_G (ค่าต่อแต้มเป็นทศนิยมได้ ปัดเศษทิ้ง)
+
+// DerivedStats คือค่าพลังสูงสุดของตัวละครตามแต้มพรสวรรค์ปัจจุบัน
+type DerivedStats struct {
+	MaxHP      int `json:"max_hp"`
+	MaxMP      int `json:"max_mp"`
+	Initiative int `json:"initiative"`
+}
+
+// CalculateDerivedStats คำนวณค่าพลังจากแต้มพรสวรรค์ของตัวละคร (config ที่อ่านไม่ได้ถือเป็น 0)
+func CalculateDerivedStats(char *domain.Character, gameDataRepo game_data.GameDataRepository) DerivedStats {
+	return DerivedStats{
+		MaxHP:      configInt(gameDataRepo, "STAT_HP_BASE", 0) + char.TalentS*configInt(gameDataRepo, "STAT_HP_PER_TALENT_S", 0),
+		MaxMP:      configInt(gameDataRepo, "STAT_MP_BASE", 0) + char.TalentL*configInt(gameDataRepo, "STAT_MP_PER_TALENT_L", 0),
+		Initiative: configInt(gameDataRepo, "STAT_INITIATIVE_BASE", 0) + int(float64(char.TalentG)*configFloat(gameDataRepo, "STAT_INITIATIVE_PER_TALENT_G", 0)),
+	}
+}
+
+// clampToDerivedStats ไม่ให้ HP/MP ปัจจุบันเกินค่าสูงสุดใหม่หลังแต้มพรสวรรค์เปลี่ยน
+func clampToDerivedStats(char *domain.Character, stats DerivedStats) {
+	if char.CurrentHP > stats.MaxHP {
+		char.CurrentHP = stats.MaxHP
+	}
+	if char.CurrentMP > stats.MaxMP {
+		char.CurrentMP = stats.MaxMP
+	}
+}
@@ -2,26 +2,30 @@
 package combat
 
 import (
+	"encoding/json"
 	"sage-of-elements-backend/internal/domain"
 )
 
 // ==================== AI Decision Making ====================
 // ไฟล์นี้รวบรวมฟังก์ชันที่เกี่ยวกับการตัดสินใจของ AI
-// - การเช็คเงื่อนไข (condition checking)
+// - การเช็คเงื่อนไข (condition checking) ครบทุกค่าใน domain.AICondition
 // - การเลือก action (action selection)
 // - การตรวจสอบทรัพยากร (resource validation)
 
 // AIDecisionContext เก็บข้อมูลที่ AI ต้องการในการตัดสินใจ
 type AIDecisionContext struct {
 	AICombatant    *domain.Combatant
-	PlayerTarget   *domain.Combatant
+	PlayerTarget   *domain.Combatant   // เป้าหลักในทีมตรงข้าม (ตัวละครที่ผู้เล่นควบคุมก่อน, NPC พันธมิตรจะได้ศัตรูตัวแรก)
+	Allies         []*domain.Combatant // ทีมเดียวกับ AI (ไม่รวมตัวเอง) ที่ยังมีชีวิตตอนเริ่มเทิร์น
+	Opponents      []*domain.Combatant // ทีมตรงข้ามที่ยังมีชีวิตตอนเริ่มเทิร์น
 	Match          *domain.CombatMatch
 	AvailableRules []*domain.EnemyAI
 }
 
 // AISelectedAction เก็บผลลัพธ์ของการตัดสินใจ
 type AISelectedAction struct {
-	Ability *domain.EnemyAbility
+	Action  domain.AIAction
+	Ability *domain.EnemyAbility // nil ถ้า Action ไม่ใช่ USE_ABILITY
 	Target  *domain.Combatant
 	Rule    *domain.EnemyAI
 }
@@ -38,8 +42,27 @@ func (s *combatService) SelectNextAction(ctx *AIDecisionContext) *AISelectedActi
 
 	// วน loop ตาม priority (rules ถูกเรียงจากน้อยไปมากแล้ว)
 	for _, rule := range ctx.AvailableRules {
-		// 1. เช็คเงื่อนไข
-		if !s._EvaluateCondition(ctx, rule) {
+		// 1. เช็คว่ามี ability ให้ใช้หรือไม่ (DEFEND/PASS/FLEE ไม่ใช้ ability)
+		usesAbility := rule.Action == domain.AIActionUseAbility
+		if usesAbility && rule.AbilityToUse == nil {
+			s.appLogger.Warn("AI rule has no ability, skipping",
+				"priority", rule.Priority,
+			)
+			continue
+		}
+
+		// 2. กำหนดเป้าหมาย (ก่อนเช็คเงื่อนไข เพราะเงื่อนไข TARGET_* ตรวจกับเป้าหมายนี้)
+		target := s._DetermineTarget(ctx, rule)
+		if target == nil {
+			s.appLogger.Debug("AI could not determine target, skipping",
+				"priority", rule.Priority,
+				"target_type", rule.Target,
+			)
+			continue
+		}
+
+		// 3. เช็คเงื่อนไข
+		if !s._EvaluateCondition(ctx, rule, target) {
 			s.appLogger.Debug("AI rule condition not met, skipping",
 				"priority", rule.Priority,
 				"condition", rule.Condition,
@@ -47,15 +70,17 @@ func (s *combatService) SelectNextAction(ctx *AIDecisionContext) *AISelectedActi
 			continue
 		}
 
-		// 2. เช็คว่ามี ability ให้ใช้หรือไม่
-		if rule.AbilityToUse == nil {
-			s.appLogger.Warn("AI rule has no ability, skipping",
+		// 4. DEFEND/PASS/FLEE ไม่ต้องใช้ทรัพยากร
+		if !usesAbility {
+			s.appLogger.Info("✅ AI selected action",
+				"action", rule.Action,
 				"priority", rule.Priority,
+				"target", target.ID,
 			)
-			continue
+			return &AISelectedAction{Action: rule.Action, Target: target, Rule: rule}
 		}
 
-		// 3. เช็คทรัพยากร (AP, MP)
+		// 5. เช็คทรัพยากร (AP, MP)
 		if !s._CanAffordAbility(ctx.AICombatant, rule.AbilityToUse) {
 			s.appLogger.Debug("AI cannot afford ability, checking next rule",
 				"priority", rule.Priority,
@@ -68,16 +93,7 @@ func (s *combatService) SelectNextAction(ctx *AIDecisionContext) *AISelectedActi
 			continue
 		}
 
-		// 4. กำหนดเป้าหมาย
-		target := s._DetermineTarget(ctx, rule)
-		if target == nil {
-			s.appLogger.Warn("AI could not determine target, skipping",
-				"priority", rule.Priority,
-			)
-			continue
-		}
-
-		// 5. เจอ action ที่ใช้ได้แล้ว!
+		// 6. เจอ action ที่ใช้ได้แล้ว!
 		s.appLogger.Info("✅ AI selected action",
 			"ability", rule.AbilityToUse.Name,
 			"priority", rule.Priority,
@@ -87,6 +103,7 @@ func (s *combatService) SelectNextAction(ctx *AIDecisionContext) *AISelectedActi
 		)
 
 		return &AISelectedAction{
+			Action:  rule.Action,
 			Ability: rule.AbilityToUse,
 			Target:  target,
 			Rule:    rule,
@@ -104,19 +121,44 @@ func (s *combatService) SelectNextAction(ctx *AIDecisionContext) *AISelectedActi
 // ==================== Condition Evaluation ====================
 
 // _EvaluateCondition เช็คว่าเงื่อนไขของ rule นี้เป็นจริงหรือไม่
-func (s *combatService) _EvaluateCondition(ctx *AIDecisionContext, rule *domain.EnemyAI) bool {
+// เงื่อนไข TARGET_* ตรวจกับเป้าหมายของ rule เอง (target จาก _DetermineTarget)
+// เงื่อนไขที่ไม่รู้จักถูกตรวจตั้งแต่ตอน seed แล้ว (seeddata.ValidateEnemies) ถ้ายังหลุดมาจะถือว่าไม่ผ่าน
+func (s *combatService) _EvaluateCondition(ctx *AIDecisionContext, rule *domain.EnemyAI, target *domain.Combatant) bool {
 	switch rule.Condition {
 	case domain.AIConditionAlways:
 		return s._CheckAlways()
 
 	case domain.AIConditionTurnIs:
 		return s._CheckTurnIs(ctx.Match, rule.ConditionValue)
+	case domain.AIConditionTurnIsEven:
+		return ctx.Match.TurnNumber%2 == 0
+	case domain.AIConditionTurnIsOdd:
+		return ctx.Match.TurnNumber%2 == 1
 
 	case domain.AIConditionSelfHPBelow:
-		return s._CheckSelfHPBelow(ctx.AICombatant, rule.ConditionValue)
+		return s._CheckHPBelow(ctx.AICombatant, rule.ConditionValue)
+	case domain.AIConditionSelfHPAbove:
+		return s._CheckHPAbove(ctx.AICombatant, rule.ConditionValue)
+	case domain.AIConditionTargetHPBelow:
+		return s._CheckHPBelow(target, rule.ConditionValue)
+	case domain.AIConditionTargetHPAbove:
+		return s._CheckHPAbove(target, rule.ConditionValue)
+
+	case domain.AIConditionSelfHasBuff:
+		return s._CheckHasEffect(ctx.AICombatant, rule.ConditionEffectID, domain.EffectType.IsBuff)
+	case domain.AIConditionSelfHasDebuff:
+		return s._CheckHasEffect(ctx.AICombatant, rule.ConditionEffectID, domain.EffectType.IsDebuff)
+	case domain.AIConditionTargetHasBuff:
+		return s._CheckHasEffect(target, rule.ConditionEffectID, domain.EffectType.IsBuff)
+	case domain.AIConditionTargetHasDebuff:
+		return s._CheckHasEffect(target, rule.ConditionEffectID, domain.EffectType.IsDebuff)
+	case domain.AIConditionTargetIsStaggered:
+		return s._CheckHasEffect(target, nil, domain.EffectType.IsCrowdControl)
+	case domain.AIConditionTargetHasShield:
+		return s._CheckHasShield(target)
 
 	default:
-		s.appLogger.Warn("Unknown AI condition type",
+		s.appLogger.Debug("Unsupported AI condition type",
 			"condition", rule.Condition,
 		)
 		return false
@@ -133,14 +175,71 @@ func (s *combatService) _CheckTurnIs(match *domain.CombatMatch, targetTurn float
 	return float64(match.TurnNumber) == targetTurn
 }
 
-// _CheckSelfHPBelow - เช็คว่า HP ของตัวเองต่ำกว่าเปอร์เซ็นต์ที่กำหนดหรือไม่
-func (s *combatService) _CheckSelfHPBelow(combatant *domain.Combatant, threshold float64) bool {
-	if combatant.Enemy == nil || combatant.Enemy.MaxHP == 0 {
+// _CheckHPBelow - เช็คว่า HP ต่ำกว่าหรือเท่ากับสัดส่วนที่กำหนดหรือไม่
+func (s *combatService) _CheckHPBelow(combatant *domain.Combatant, threshold float64) bool {
+	ratio, ok := s._HPRatio(combatant)
+	return ok && ratio <= threshold
+}
+
+// _CheckHPAbove - เช็คว่า HP สูงกว่าสัดส่วนที่กำหนดหรือไม่
+func (s *combatService) _CheckHPAbove(combatant *domain.Combatant, threshold float64) bool {
+	ratio, ok := s._HPRatio(combatant)
+	return ok && ratio > threshold
+}
+
+// _HPRatio คือ HP ปัจจุบัน / HP สูงสุด (ok = false ถ้าหา HP สูงสุดไม่ได้)
+func (s *combatService) _HPRatio(combatant *domain.Combatant) (float64, bool) {
+	if combatant == nil {
+		return 0, false
+	}
+	maxHP := s.getMaxHP(combatant)
+	if maxHP <= 0 {
+		return 0, false
+	}
+	return float64(combatant.CurrentHP) / float64(maxHP), true
+}
+
+// _CheckHasEffect - เช็คว่ามี active effect ที่ตรงเงื่อนไขหรือไม่
+// effectID != nil = ต้องเป็นเอฟเฟกต์นั้นเท่านั้น, nil = เอฟเฟกต์ใดก็ได้ที่ประเภทผ่าน matchType
+func (s *combatService) _CheckHasEffect(combatant *domain.Combatant, effectID *uint, matchType func(domain.EffectType) bool) bool {
+	if combatant == nil || len(combatant.ActiveEffects) == 0 {
+		return false
+	}
+	var activeEffects []domain.ActiveEffect
+	if err := json.Unmarshal(combatant.ActiveEffects, &activeEffects); err != nil {
 		return false
 	}
 
-	currentHPRatio := float64(combatant.CurrentHP) / float64(combatant.Enemy.MaxHP)
-	return currentHPRatio <= threshold
+	for _, active := range activeEffects {
+		if effectID != nil && active.EffectID != *effectID {
+			continue
+		}
+		effect, err := s.gameDataRepo.FindEffectByID(active.EffectID)
+		if err != nil || effect == nil {
+			continue
+		}
+		if matchType(effect.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// _CheckHasShield - เช็คว่ามี Shield (1102) ที่ยังเหลือค่าอยู่หรือไม่
+func (s *combatService) _CheckHasShield(combatant *domain.Combatant) bool {
+	if combatant == nil || len(combatant.ActiveEffects) == 0 {
+		return false
+	}
+	var activeEffects []domain.ActiveEffect
+	if err := json.Unmarshal(combatant.ActiveEffects, &activeEffects); err != nil {
+		return false
+	}
+	for _, active := range activeEffects {
+		if active.EffectID == 1102 && active.Value > 0 { // SHIELD
+			return true
+		}
+	}
+	return false
 }
 
 // ==================== Resource Validation ====================
@@ -155,15 +254,36 @@ func (s *combatService) _CanAffordAbility(combatant *domain.Combatant, ability *
 
 // ==================== Target Selection ====================
 
-// _DetermineTarget กำหนดเป้าหมายของ action ตาม rule
+// _DetermineTarget กำหนดเป้าหมายของ action ตาม rule (nil = ไม่มีเป้าหมายที่เข้าเงื่อนไข → ข้าม rule นี้)
+// เป้าหมายฝั่งผู้เล่นเลือกจาก ctx.Opponents, ฝั่งเพื่อนเลือกจาก ctx.Allies (ข้ามตัวที่ตายไประหว่างเทิร์น)
 func (s *combatService) _DetermineTarget(ctx *AIDecisionContext, rule *domain.EnemyAI) *domain.Combatant {
 	switch rule.Target {
-	case domain.AITargetPlayer:
-		return ctx.PlayerTarget
-
 	case domain.AITargetSelf:
 		return ctx.AICombatant
 
+	case domain.AITargetPlayer:
+		if ctx.PlayerTarget != nil && ctx.PlayerTarget.CurrentHP > 0 {
+			return ctx.PlayerTarget
+		}
+		return firstAlive(ctx.Opponents)
+	case domain.AITargetPlayerLowestHP:
+		return s._LowestHPCombatant(ctx.Opponents)
+	case domain.AITargetPlayerHighestThreat:
+		return highestThreatCombatant(ctx.Opponents)
+	case domain.AITargetRandomPlayer:
+		alive := aliveCombatants(ctx.Opponents)
+		if len(alive) == 0 {
+			return nil
+		}
+		return alive[s.rollIntn(ctx.Match, len(alive))]
+
+	case domain.AITargetAlly:
+		return firstAlive(ctx.Allies)
+	case domain.AITargetAllyLowestHP:
+		return s._LowestHPCombatant(ctx.Allies)
+	case domain.AITargetAllyWithoutBuff:
+		return s._AllyWithoutBuff(ctx.Allies, rule.AbilityToUse)
+
 	default:
 		// Default ให้ target เป็น player
 		s.appLogger.Debug("Unknown AI target type, defaulting to player",
@@ -172,3 +292,96 @@ func (s *combatService) _DetermineTarget(ctx *AIDecisionContext, rule *domain.En
 		return ctx.PlayerTarget
 	}
 }
+
+// _LowestHPCombatant คือตัวที่ยังมีชีวิตและเหลือ HP น้อยที่สุดตามสัดส่วน (เสมอกันเลือกตัวแรก)
+func (s *combatService) _LowestHPCombatant(combatants []*domain.Combatant) *domain.Combatant {
+	var lowest *domain.Combatant
+	lowestRatio := 0.0
+	for _, c := range aliveCombatants(combatants) {
+		ratio, ok := s._HPRatio(c)
+		if !ok {
+			continue
+		}
+		if lowest == nil || ratio < lowestRatio {
+			lowest, lowestRatio = c, ratio
+		}
+	}
+	return lowest
+}
+
+// _AllyWithoutBuff คือเพื่อนตัวแรกที่ยังไม่มีบัฟจาก ability นี้ (ability ไม่มีบัฟ = เพื่อนที่ไม่มีบัฟเลย)
+func (s *combatService) _AllyWithoutBuff(allies []*domain.Combatant, ability *domain.EnemyAbility) *domain.Combatant {
+	buffIDs := s._AbilityBuffEffectIDs(ability)
+	for _, ally := range aliveCombatants(allies) {
+		if len(buffIDs) == 0 {
+			if !s._CheckHasEffect(ally, nil, domain.EffectType.IsBuff) {
+				return ally
+			}
+			continue
+		}
+		hasAll := true
+		for _, effectID := range buffIDs {
+			if !s._CheckHasEffect(ally, &effectID, domain.EffectType.IsBuff) {
+				hasAll = false
+				break
+			}
+		}
+		if !hasAll {
+			return ally
+		}
+	}
+	return nil
+}
+
+// _AbilityBuffEffectIDs คือ effect_id ประเภทบัฟใน EffectsJSON ของ ability
+func (s *combatService) _AbilityBuffEffectIDs(ability *domain.EnemyAbility) []uint {
+	if ability == nil || len(ability.EffectsJSON) == 0 {
+		return nil
+	}
+	var effects []struct {
+		EffectID uint `json:"effect_id"`
+	}
+	if err := json.Unmarshal(ability.EffectsJSON, &effects); err != nil {
+		return nil
+	}
+	var buffIDs []uint
+	for _, e := range effects {
+		effect, err := s.gameDataRepo.FindEffectByID(e.EffectID)
+		if err == nil && effect != nil && effect.Type.IsBuff() {
+			buffIDs = append(buffIDs, e.EffectID)
+		}
+	}
+	return buffIDs
+}
+
+// highestThreatCombatant คือตัวที่ยังมีชีวิตและมี Threat สูงสุด (เสมอกันเลือกตัวแรก)
+func highestThreatCombatant(combatants []*domain.Combatant) *domain.Combatant {
+	var highest *domain.Combatant
+	for _, c := range aliveCombatants(combatants) {
+		if highest == nil || c.Threat > highest.Threat {
+			highest = c
+		}
+	}
+	return highest
+}
+
+// firstAlive คือตัวแรกที่ยังมีชีวิต (nil ถ้าไม่มี)
+func firstAlive(combatants []*domain.Combatant) *domain.Combatant {
+	for _, c := range combatants {
+		if c.CurrentHP > 0 {
+			return c
+		}
+	}
+	return nil
+}
+
+// aliveCombatants กรองเฉพาะตัวที่ยังมีชีวิต (HP อาจหมดระหว่างเทิร์นของ AI)
+func aliveCombatants(combatants []*domain.Combatant) []*domain.Combatant {
+	var alive []*domain.Combatant
+	for _, c := range combatants {
+		if c.CurrentHP > 0 {
+			alive = append(alive, c)
+		}
+	}
+	return alive
+}
@@ -0,0 +1,386 @@
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/pkg/applogger"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	s := newTestCombatService(nil)
+	vulnerable, defenseUp := uint(4102), uint(2204)
+
+	tests := []struct {
+		name   string
+		rule   domain.EnemyAI
+		turn   int
+		ai     *domain.Combatant
+		target *domain.Combatant
+		want   bool
+	}{
+		{name: "always", rule: domain.EnemyAI{Condition: domain.AIConditionAlways}, want: true},
+		{name: "turn is", rule: domain.EnemyAI{Condition: domain.AIConditionTurnIs, ConditionValue: 3}, turn: 3, want: true},
+		{name: "turn is another turn", rule: domain.EnemyAI{Condition: domain.AIConditionTurnIs, ConditionValue: 3}, turn: 4},
+		{name: "turn is even on odd turn", rule: domain.EnemyAI{Condition: domain.AIConditionTurnIsEven}, turn: 3},
+		{name: "turn is odd", rule: domain.EnemyAI{Condition: domain.AIConditionTurnIsOdd}, turn: 3, want: true},
+		{name: "self hp below includes the threshold", rule: domain.EnemyAI{Condition: domain.AIConditionSelfHPBelow, ConditionValue: 0.5}, ai: newTestEnemy(50, 100), want: true},
+		{name: "self hp below uses the scaled max hp", rule: domain.EnemyAI{Condition: domain.AIConditionSelfHPBelow, ConditionValue: 0.5}, ai: newTestEnemy(51, 100)},
+		{name: "self hp above is strict", rule: domain.EnemyAI{Condition: domain.AIConditionSelfHPAbove, ConditionValue: 0.5}, ai: newTestEnemy(50, 100)},
+		{name: "target hp below", rule: domain.EnemyAI{Condition: domain.AIConditionTargetHPBelow, ConditionValue: 0.3}, target: newTestCharacter(20, 100), want: true},
+		{name: "target hp above", rule: domain.EnemyAI{Condition: domain.AIConditionTargetHPAbove, ConditionValue: 0.3}, target: newTestCharacter(20, 100)},
+		{
+			name: "self has buff",
+			rule: domain.EnemyAI{Condition: domain.AIConditionSelfHasBuff},
+			ai:   withEffects(newTestEnemy(100, 100), domain.ActiveEffect{EffectID: 2204, Value: 50, TurnsRemaining: 1}),
+			want: true,
+		},
+		{
+			name:   "target has the named debuff",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetHasDebuff, ConditionEffectID: &vulnerable},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 4102, Value: 10, TurnsRemaining: 2}),
+			want:   true,
+		},
+		{
+			name:   "target has another debuff",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetHasDebuff, ConditionEffectID: &vulnerable},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 4101, Value: -15, TurnsRemaining: 2}),
+		},
+		{
+			name:   "target has buff ignores debuffs",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetHasBuff, ConditionEffectID: &defenseUp},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 4102, Value: 10, TurnsRemaining: 2}),
+		},
+		{
+			name:   "slowed target is staggered",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetIsStaggered},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 4101, Value: -15, TurnsRemaining: 2}),
+			want:   true,
+		},
+		{
+			name:   "vulnerable target is not staggered",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetIsStaggered},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 4102, Value: 10, TurnsRemaining: 2}),
+		},
+		{
+			name:   "target has shield",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetHasShield},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 1102, Value: 10, TurnsRemaining: 2}),
+			want:   true,
+		},
+		{
+			name:   "broken shield does not count",
+			rule:   domain.EnemyAI{Condition: domain.AIConditionTargetHasShield},
+			target: withEffects(newTestCharacter(100, 100), domain.ActiveEffect{EffectID: 1102, Value: 0, TurnsRemaining: 2}),
+		},
+		{name: "unknown condition", rule: domain.EnemyAI{Condition: "SELF_IS_BORED"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ai == nil {
+				tt.ai = newTestEnemy(100, 100)
+			}
+			if tt.target == nil {
+				tt.target = newTestCharacter(100, 100)
+			}
+			ctx := &AIDecisionContext{AICombatant: tt.ai, Match: &domain.CombatMatch{TurnNumber: tt.turn}}
+
+			if got := s._EvaluateCondition(ctx, &tt.rule, tt.target); got != tt.want {
+				t.Errorf("_EvaluateCondition() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetermineTarget(t *testing.T) {
+	s := newTestCombatService(nil)
+	ai := newTestEnemy(100, 100)
+	tank, hurt, dead, healer := newTestCharacter(80, 100), newTestCharacter(30, 100), newTestCharacter(0, 100), newTestCharacter(40, 40)
+	tank.Threat, hurt.Threat, dead.Threat, healer.Threat = 5, 10, 100, 20
+	guarded := withEffects(newTestEnemy(20, 100), domain.ActiveEffect{EffectID: 2204, Value: 50, TurnsRemaining: 1})
+	bare := newTestEnemy(90, 100)
+	names := map[*domain.Combatant]string{ai: "ai", tank: "tank", hurt: "hurt", dead: "dead", healer: "healer", guarded: "guarded", bare: "bare"}
+
+	guard := &domain.EnemyAbility{ID: 1, Name: "Guard", EffectsJSON: abilityEffects(abilityEffect{EffectID: 2204, Value: 50, Duration: 1})}
+	tests := []struct {
+		name   string
+		rule   domain.EnemyAI
+		player *domain.Combatant
+		want   string
+	}{
+		{name: "self", rule: domain.EnemyAI{Target: domain.AITargetSelf}, want: "ai"},
+		{name: "player", rule: domain.EnemyAI{Target: domain.AITargetPlayer}, player: healer, want: "healer"},
+		{name: "dead player falls back to first alive opponent", rule: domain.EnemyAI{Target: domain.AITargetPlayer}, player: dead, want: "tank"},
+		{name: "lowest hp is by ratio", rule: domain.EnemyAI{Target: domain.AITargetPlayerLowestHP}, want: "hurt"},
+		{name: "highest threat skips the dead", rule: domain.EnemyAI{Target: domain.AITargetPlayerHighestThreat}, want: "healer"},
+		{name: "ally", rule: domain.EnemyAI{Target: domain.AITargetAlly}, want: "guarded"},
+		{name: "ally lowest hp", rule: domain.EnemyAI{Target: domain.AITargetAllyLowestHP}, want: "guarded"},
+		{name: "ally without the ability's buff", rule: domain.EnemyAI{Target: domain.AITargetAllyWithoutBuff, AbilityToUse: guard}, want: "bare"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &AIDecisionContext{
+				AICombatant:  ai,
+				PlayerTarget: tt.player,
+				Allies:       []*domain.Combatant{guarded, bare},
+				Opponents:    []*domain.Combatant{dead, tank, hurt, healer},
+				Match:        &domain.CombatMatch{TurnNumber: 1},
+			}
+
+			got := s._DetermineTarget(ctx, &tt.rule)
+			if got == nil || names[got] != tt.want {
+				t.Errorf("_DetermineTarget() = %s, want %s", names[got], tt.want)
+			}
+		})
+	}
+
+	// RANDOM_PLAYER สุ่มจาก random stream ของ match → seed เดิมได้เป้าเดิมเสมอ และไม่เลือกตัวที่ตายแล้ว
+	for seed := int64(1); seed <= 20; seed++ {
+		ctx := &AIDecisionContext{AICombatant: ai, Opponents: []*domain.Combatant{dead, tank, hurt}, Match: &domain.CombatMatch{RandomSeed: seed}}
+		replay := &AIDecisionContext{AICombatant: ai, Opponents: []*domain.Combatant{dead, tank, hurt}, Match: &domain.CombatMatch{RandomSeed: seed}}
+		rule := &domain.EnemyAI{Target: domain.AITargetRandomPlayer}
+
+		got := s._DetermineTarget(ctx, rule)
+		if got == dead || got == nil {
+			t.Fatalf("seed %d: random player = %s, want an alive opponent", seed, names[got])
+		}
+		if again := s._DetermineTarget(replay, rule); again != got {
+			t.Fatalf("seed %d: random player = %s then %s, want the same pick", seed, names[got], names[again])
+		}
+	}
+}
+
+func TestSelectNextActionSpecialActions(t *testing.T) {
+	s := newTestCombatService(nil)
+	strike := &domain.EnemyAbility{ID: 1, Name: "Strike", APCost: 2, EffectsJSON: abilityEffects(abilityEffect{EffectID: 1101, Value: 10})}
+
+	tests := []struct {
+		name  string
+		rules []*domain.EnemyAI
+		want  domain.AIAction
+	}{
+		{
+			name: "special actions ignore resources",
+			rules: []*domain.EnemyAI{
+				{Priority: 1, Condition: domain.AIConditionAlways, Action: domain.AIActionUseAbility, Target: domain.AITargetPlayer, AbilityToUse: strike},
+				{Priority: 2, Condition: domain.AIConditionSelfHPBelow, ConditionValue: 0.5, Action: domain.AIActionDefend, Target: domain.AITargetSelf},
+				{Priority: 3, Condition: domain.AIConditionAlways, Action: domain.AIActionPass, Target: domain.AITargetSelf},
+			},
+			want: domain.AIActionDefend,
+		},
+		{
+			name: "condition still applies to special actions",
+			rules: []*domain.EnemyAI{
+				{Priority: 1, Condition: domain.AIConditionSelfHPBelow, ConditionValue: 0.1, Action: domain.AIActionFlee, Target: domain.AITargetSelf},
+				{Priority: 2, Condition: domain.AIConditionAlways, Action: domain.AIActionPass, Target: domain.AITargetSelf},
+			},
+			want: domain.AIActionPass,
+		},
+		{
+			name: "flee",
+			rules: []*domain.EnemyAI{
+				{Priority: 1, Condition: domain.AIConditionSelfHPBelow, ConditionValue: 0.5, Action: domain.AIActionFlee, Target: domain.AITargetSelf},
+			},
+			want: domain.AIActionFlee,
+		},
+		{
+			name: "ability without enough AP is skipped",
+			rules: []*domain.EnemyAI{
+				{Priority: 1, Condition: domain.AIConditionAlways, Action: domain.AIActionUseAbility, Target: domain.AITargetPlayer, AbilityToUse: strike},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ai := newTestEnemy(30, 100)
+			ai.CurrentAP = 1
+			ctx := &AIDecisionContext{
+				AICombatant:    ai,
+				PlayerTarget:   newTestCharacter(100, 100),
+				Match:          &domain.CombatMatch{TurnNumber: 1},
+				AvailableRules: tt.rules,
+			}
+
+			selected := s.SelectNextAction(ctx)
+			if tt.want == "" {
+				if selected != nil {
+					t.Fatalf("SelectNextAction() = %s, want no action", selected.Action)
+				}
+				return
+			}
+			if selected == nil || selected.Action != tt.want {
+				t.Fatalf("SelectNextAction() = %+v, want %s", selected, tt.want)
+			}
+			if selected.Ability != nil || selected.Target != ai {
+				t.Errorf("selected ability = %v, target = %v, want no ability on the AI itself", selected.Ability, selected.Target)
+			}
+		})
+	}
+}
+
+func TestExecuteAISpecialActions(t *testing.T) {
+	t.Run("defend", func(t *testing.T) {
+		s := newTestCombatService(nil)
+		ai := newTestEnemy(100, 100)
+		ai.CurrentAP = 3
+
+		if err := s.ExecuteAIAction(&domain.CombatMatch{}, ai, &AISelectedAction{Action: domain.AIActionDefend, Target: ai}); err != nil {
+			t.Fatalf("ExecuteAIAction() error = %v", err)
+		}
+		effects := activeEffectsOf(ai)
+		if len(effects) != 1 || effects[0].EffectID != 2204 || effects[0].Value != 50 || effects[0].TurnsRemaining != 1 {
+			t.Errorf("active effects = %+v, want DEFENSE_UP 50%% for 1 turn", effects)
+		}
+		if ai.CurrentAP != 0 {
+			t.Errorf("AP = %d after defending, want 0", ai.CurrentAP)
+		}
+	})
+
+	t.Run("pass keeps AP", func(t *testing.T) {
+		s := newTestCombatService(nil)
+		ai := newTestEnemy(100, 100)
+		ai.CurrentAP = 3
+		match := &domain.CombatMatch{}
+
+		if err := s.ExecuteAIAction(match, ai, &AISelectedAction{Action: domain.AIActionPass, Target: ai}); err != nil {
+			t.Fatalf("ExecuteAIAction() error = %v", err)
+		}
+		if ai.CurrentAP != 3 {
+			t.Errorf("AP = %d after passing, want 3", ai.CurrentAP)
+		}
+		assertEventTypes(t, match, domain.CombatEventPassed)
+	})
+
+	tests := []struct {
+		name     string
+		chance   string
+		wantFled bool
+		want     domain.CombatEventType
+	}{
+		{name: "flee succeeds by default", wantFled: true, want: domain.CombatEventFled},
+		{name: "flee fails without a chance", chance: "0", want: domain.CombatEventFleeFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestCombatService(map[string]string{"AI_FLEE_CHANCE": tt.chance})
+			ai := newTestEnemy(10, 100)
+			ai.CurrentAP = 2
+			match := &domain.CombatMatch{RandomSeed: 7}
+
+			if err := s.ExecuteAIAction(match, ai, &AISelectedAction{Action: domain.AIActionFlee, Target: ai}); err != nil {
+				t.Fatalf("ExecuteAIAction() error = %v", err)
+			}
+			if ai.HasFled != tt.wantFled {
+				t.Errorf("has fled = %t, want %t", ai.HasFled, tt.wantFled)
+			}
+			assertEventTypes(t, match, tt.want)
+		})
+	}
+}
+
+// --- Helpers ---
+
+// testEffectTypes คือประเภทของ effect ที่ใช้ในเทสต์ (ตาม seed data)
+var testEffectTypes = map[uint]domain.EffectType{
+	1101: domain.EffectTypeDamage,
+	1102: domain.EffectTypeShield,
+	1103: domain.EffectTypeHeal,
+	2101: domain.EffectTypeBuff,
+	2202: domain.EffectTypeBuff,
+	2203: domain.EffectTypeBuff,
+	2204: domain.EffectTypeBuff,
+	4101: domain.EffectTypeDebuffCC,
+	4102: domain.EffectTypeDebuff,
+}
+
+func newTestCombatService(configs map[string]string) *combatService {
+	return &combatService{
+		appLogger:    applogger.NewNopLogger(),
+		gameDataRepo: fakeGameData{configs: configs},
+	}
+}
+
+// newTestEnemy สร้างศัตรูธาตุกลาง (ไม่มีธาตุ = modifier ธาตุเป็น 1.0)
+func newTestEnemy(hp, maxHP int) *domain.Combatant {
+	enemyID := uint(1)
+	return &domain.Combatant{
+		ID:        uuid.Must(uuid.NewV7()),
+		EnemyID:   &enemyID,
+		Enemy:     &domain.Enemy{ID: enemyID, MaxHP: maxHP},
+		Team:      domain.MatchSideEnemy,
+		MaxHP:     maxHP,
+		CurrentHP: hp,
+	}
+}
+
+// newTestCharacter สร้างตัวละครฝั่งผู้เล่น (HP สูงสุดของตัวละครคือ Character.CurrentHP)
+func newTestCharacter(hp, maxHP int) *domain.Combatant {
+	characterID := uint(1)
+	return &domain.Combatant{
+		ID:          uuid.Must(uuid.NewV7()),
+		CharacterID: &characterID,
+		Character:   &domain.Character{ID: characterID, CurrentHP: maxHP},
+		Team:        domain.MatchSidePlayer,
+		CurrentHP:   hp,
+	}
+}
+
+func withEffects(c *domain.Combatant, effects ...domain.ActiveEffect) *domain.Combatant {
+	c.ActiveEffects, _ = json.Marshal(effects)
+	return c
+}
+
+func abilityEffects(effects ...abilityEffect) []byte {
+	data, _ := json.Marshal(effects)
+	return data
+}
+
+func assertEventTypes(t *testing.T, match *domain.CombatMatch, want ...domain.CombatEventType) {
+	t.Helper()
+	var got []domain.CombatEventType
+	for _, event := range match.PendingEvents {
+		got = append(got, event.Type)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+// --- Fakes ---
+
+type fakeGameData struct {
+	game_data.GameDataRepository
+	configs map[string]string
+	recipes map[uint]*domain.Recipe
+}
+
+func (f fakeGameData) GetGameConfigValue(key string) (string, error) {
+	return f.configs[key], nil
+}
+
+func (fakeGameData) FindEffectByID(id uint) (*domain.Effect, error) {
+	effectType, ok := testEffectTypes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.Effect{ID: id, Type: effectType}, nil
+}
+
+func (fakeGameData) GetMatchupModifier(attackerID, defenderID uint) (string, error) {
+	return "", nil
+}
+
+func (f fakeGameData) FindRecipeByOutputElementID(elementID uint) (*domain.Recipe, error) {
+	return f.recipes[elementID], nil
+}
@@ -11,13 +11,27 @@ import (
 // - การหักทรัพยากร (resource deduction)
 // - การ apply effects
 // - การ log action results
+// - การกระทำพิเศษที่ไม่ใช้ ability (DEFEND, PASS, FLEE) ซึ่งจบเทิร์นของ AI ทันที
 
 // ExecuteAIAction ทำการ execute action ที่เลือกแล้ว
 // จะหักทรัพยากร, apply effects, และ log ผลลัพธ์
 func (s *combatService) ExecuteAIAction(
+	match *domain.CombatMatch,
 	aiCombatant *domain.Combatant,
 	action *AISelectedAction,
 ) error {
+	switch action.Action {
+	case domain.AIActionDefend:
+		s._ExecuteDefend(match, aiCombatant, action.Target)
+		return nil
+	case domain.AIActionPass:
+		s._ExecutePass(match, aiCombatant)
+		return nil
+	case domain.AIActionFlee:
+		s._ExecuteFlee(match, aiCombatant)
+		return nil
+	}
+
 	s.appLogger.Info("🎯 AI executing action",
 		"ai_id", aiCombatant.ID,
 		"ability", action.Ability.Name,
@@ -27,8 +41,17 @@ func (s *combatService) ExecuteAIAction(
 	// 1. หักทรัพยากร (AP, MP)
 	s._DeductResources(aiCombatant, action.Ability)
 
+	castEvent := newCombatEvent(domain.CombatEventCast, aiCombatant, action.Target, 0)
+	castEvent.Details = eventDetails(map[string]interface{}{
+		"ability_id":   action.Ability.ID,
+		"ability_name": action.Ability.Name,
+		"ap_cost":      action.Ability.APCost,
+		"mp_cost":      action.Ability.MPCost,
+	})
+	s.recordEvent(match, castEvent)
+
 	// 2. Apply effects ของ ability
-	err := s._ApplyAbilityEffects(aiCombatant, action)
+	err := s._ApplyAbilityEffects(match, aiCombatant, action)
 	if err != nil {
 		s.appLogger.Error("Failed to apply AI ability effects", err,
 			"ai_id", aiCombatant.ID,
@@ -81,6 +104,7 @@ func (s *combatService) _DeductResources(
 
 // _ApplyAbilityEffects apply effects ทั้งหมดของ ability
 func (s *combatService) _ApplyAbilityEffects(
+	match *domain.CombatMatch,
 	aiCombatant *domain.Combatant,
 	action *AISelectedAction,
 ) error {
@@ -113,8 +137,59 @@ func (s *combatService) _ApplyAbilityEffects(
 		)
 
 		// เรียกใช้ effect manager ที่มีอยู่แล้ว
-		s.applyEffect(aiCombatant, action.Target, effectData, dummySpell)
+		s.applyEffect(match, aiCombatant, action.Target, effectData, dummySpell)
 	}
 
 	return nil
 }
+
+// ==================== Special Actions ====================
+
+// _ExecuteDefend ตั้งการ์ด: ให้ BUFF_DEFENSE_UP กับเป้าหมาย (ปกติคือตัวเอง) แล้วใช้ AP ที่เหลือทั้งหมด
+// ลดดาเมจ AI_DEFEND_REDUCTION_PERCENT% นาน AI_DEFEND_DURATION เทิร์น (หมดตอนต้นเทิร์นถัดไปของเป้าหมาย)
+func (s *combatService) _ExecuteDefend(match *domain.CombatMatch, aiCombatant *domain.Combatant, target *domain.Combatant) {
+	effectData := map[string]interface{}{
+		"effect_id": float64(2204), // BUFF_DEFENSE_UP
+		"value":     s._GetConfigFloat("AI_DEFEND_REDUCTION_PERCENT", 50),
+		"duration":  s._GetConfigFloat("AI_DEFEND_DURATION", 1),
+	}
+	dummySpell := &domain.Spell{ElementID: aiCombatant.Enemy.ElementID}
+	s.applyEffect(match, aiCombatant, target, effectData, dummySpell)
+
+	aiCombatant.CurrentAP = 0
+	s.appLogger.Info("🛡️ AI defended",
+		"ai_id", aiCombatant.ID,
+		"target", target.ID,
+		"reduction_percent", effectData["value"],
+	)
+}
+
+// _ExecutePass ข้ามเทิร์นโดยตั้งใจ AP ที่เหลือยังอยู่ (สะสมได้ถึง COMBAT_BASE_AP_CAP)
+func (s *combatService) _ExecutePass(match *domain.CombatMatch, aiCombatant *domain.Combatant) {
+	s.recordEvent(match, newCombatEvent(domain.CombatEventPassed, aiCombatant, nil, aiCombatant.CurrentAP))
+	s.appLogger.Info("AI passed turn",
+		"ai_id", aiCombatant.ID,
+		"ap_saved", aiCombatant.CurrentAP,
+	)
+}
+
+// _ExecuteFlee พยายามหนี (สำเร็จตามโอกาส AI_FLEE_CHANCE ผ่าน random stream ของ match)
+// หนีสำเร็จ = ออกจาก match: ไม่ได้เทิร์น, เป็นเป้าหมายไม่ได้, ไม่ดรอปของ และนับ EXP แค่บางส่วน (ดู _FledEnemyExpModifier)
+// หนีไม่สำเร็จ = เสียเทิร์นนี้ไป
+func (s *combatService) _ExecuteFlee(match *domain.CombatMatch, aiCombatant *domain.Combatant) {
+	chance := s._GetConfigFloat("AI_FLEE_CHANCE", 1.0)
+	if chance < 1 && s.rollFloat64(match) >= chance {
+		s.recordEvent(match, newCombatEvent(domain.CombatEventFleeFailed, aiCombatant, nil, 0))
+		s.appLogger.Info("AI failed to flee", "ai_id", aiCombatant.ID, "chance", chance)
+		return
+	}
+
+	aiCombatant.HasFled = true
+	aiCombatant.CurrentAP = 0
+	s.recordEvent(match, newCombatEvent(domain.CombatEventFled, aiCombatant, nil, 0))
+	s.appLogger.Info("🏃 AI fled the match",
+		"ai_id", aiCombatant.ID,
+		"match_id", match.ID,
+		"hp_remaining", aiCombatant.CurrentHP,
+	)
+}
@@ -0,0 +1,232 @@
+// file: internal/modules/combat/ai_ghost.go
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sort"
+)
+
+// ==================== Ghost AI (PvP แบบ async) ====================
+// ไฟล์นี้เล่นเทิร์นแทนตัวละครของผู้เล่นอีกคน (Combatant.IsAIControlled)
+// - ใช้เวทที่ตัวละครนั้น resolve ได้จริง: ธาตุ T0 ทั้ง 4 + ธาตุใน Defense Deck × ทุกศาสตร์ (เฉพาะที่ปลดล็อกแล้ว)
+//   รายการนี้ resolve ครั้งเดียวตอนสร้าง match และเก็บไว้ใน match/snapshot (replay ได้ผลเดิมแม้ตัวละครปลดล็อกเวทเพิ่มภายหลัง)
+// - ร่ายผ่าน ExecuteSpellCast เหมือนผู้เล่น (หัก AP/MP/Charge และบันทึก event ตามปกติ)
+// - ตัดสินใจแบบ deterministic (ไม่มีการสุ่ม) เพื่อให้ replay เล่นซ้ำได้ผลเดิม
+
+const (
+	ghostMaxActionsPerTurn = 5
+	ghostHealHPThreshold   = 0.4 // HP ต่ำกว่านี้จะลองฮีลตัวเองก่อน
+)
+
+// processGhostTurn เล่น 1 เทิร์นของตัวละครที่ AI ควบคุม แล้วส่งต่อเทิร์นถัดไป
+func (s *combatService) processGhostTurn(match *domain.CombatMatch, ghost *domain.Combatant) (*domain.CombatMatch, error) {
+	spells := s._LoadGhostSpells(ghost)
+
+	for i := 0; i < ghostMaxActionsPerTurn && ghost.CurrentAP > 0; i++ {
+		spell, target := s._ChooseGhostCast(match, ghost, spells)
+		if spell == nil {
+			break
+		}
+
+		if err := s.ExecuteSpellCast(match, ghost, target.ID, spell.ID, "INSTANT"); err != nil {
+			// ร่ายไม่ได้ (เช่นติดสถานะ) → จบเทิร์นแทนการ error ทั้ง match
+			s.appLogger.Warn("Ghost failed to cast, ending turn",
+				"ghost_id", ghost.ID,
+				"spell_id", spell.ID,
+				"error", err,
+			)
+			break
+		}
+
+		match = s.checkMatchEndCondition(match)
+		if match.Status != domain.MatchInProgress {
+			return match, nil
+		}
+	}
+
+	return s._EndAITurn(match)
+}
+
+// _PrepareGhostSpells resolve เวทที่ ghost ร่ายได้ครั้งเดียวตอนสร้าง match แล้วเก็บใน Combatant.GhostSpells
+// อ่านการปลดล็อก/ธาตุที่ค้นพบจาก DB อย่างละครั้ง และไม่บันทึกการปลดล็อกใหม่ (ระหว่างต่อสู้ใช้แค่รายการนี้)
+func (s *combatService) _PrepareGhostSpells(ghost *domain.Combatant, char *domain.Character) error {
+	unlocks, err := s.characterRepo.FindSpellUnlocks(char.ID)
+	if err != nil {
+		return err
+	}
+	unlocked := make(map[uint]bool, len(unlocks))
+	for _, unlock := range unlocks {
+		unlocked[unlock.SpellID] = true
+	}
+	discoveredIDs, err := s.characterRepo.FindDiscoveredElementIDs(char.ID)
+	if err != nil {
+		return err
+	}
+	discovered := make(map[uint]bool, len(discoveredIDs))
+	for _, elementID := range discoveredIDs {
+		discovered[elementID] = true
+	}
+
+	spellIDs := []uint{}
+	for _, spell := range s._ResolveGhostSpells(ghost, char.PrimaryElementID) {
+		// เวทที่ยังล็อกอยู่ ghost ก็ร่ายไม่ได้เหมือนผู้เล่น
+		if character.HasUnlockRequirements(spell) && !unlocked[spell.ID] &&
+			len(character.MissingSpellRequirements(char, spell, discovered)) > 0 {
+			continue
+		}
+		spellIDs = append(spellIDs, spell.ID)
+	}
+
+	ghost.GhostSpells, err = json.Marshal(spellIDs)
+	return err
+}
+
+// _LoadGhostSpells อ่านเวทของ ghost จากรายการที่เก็บไว้ตอนสร้าง match (เรียงตาม ID)
+// match ที่สร้างก่อนมีรายการนี้ → resolve ตามทฤษฎีแบบเดิม (ไม่ตรวจการปลดล็อก)
+func (s *combatService) _LoadGhostSpells(ghost *domain.Combatant) []*domain.Spell {
+	spellIDs, ok := ghostSpellIDs(ghost)
+	if !ok {
+		if ghost.Character == nil {
+			return nil
+		}
+		return s._ResolveGhostSpells(ghost, ghost.Character.PrimaryElementID)
+	}
+
+	spells := make([]*domain.Spell, 0, len(spellIDs))
+	for _, spellID := range spellIDs {
+		spell, err := s.gameDataRepo.FindSpellByID(spellID)
+		if err != nil || spell == nil {
+			s.appLogger.Warn("Ghost spell not found, skipping", "ghost_id", ghost.ID, "spell_id", spellID)
+			continue
+		}
+		spells = append(spells, spell)
+	}
+	return spells
+}
+
+// ghostSpellIDs แปลง Combatant.GhostSpells (false = ไม่มีรายการ เช่น match เก่าหรือไม่ใช่ ghost)
+func ghostSpellIDs(c *domain.Combatant) ([]uint, bool) {
+	if len(c.GhostSpells) == 0 {
+		return nil, false
+	}
+	var spellIDs []uint
+	if err := json.Unmarshal(c.GhostSpells, &spellIDs); err != nil {
+		return nil, false
+	}
+	return spellIDs, true
+}
+
+// _ResolveGhostSpells หาเวททั้งหมดที่ตัวละครนี้ร่ายได้ในทางทฤษฎี (เรียงตาม ID, ยังไม่กรองการปลดล็อก)
+func (s *combatService) _ResolveGhostSpells(ghost *domain.Combatant, primaryElementID uint) []*domain.Spell {
+	elementIDs := []uint{1, 2, 3, 4}
+	seenElements := map[uint]bool{1: true, 2: true, 3: true, 4: true}
+	for _, charge := range ghost.Deck {
+		if !seenElements[charge.ElementID] {
+			seenElements[charge.ElementID] = true
+			elementIDs = append(elementIDs, charge.ElementID)
+		}
+	}
+
+	masteries, err := s.gameDataRepo.FindAllMasteries()
+	if err != nil {
+		s.appLogger.Error("Failed to load masteries for ghost", err, "ghost_id", ghost.ID)
+		return nil
+	}
+
+	seenSpells := make(map[uint]bool)
+	var spells []*domain.Spell
+	for _, elementID := range elementIDs {
+		for _, mastery := range masteries {
+			spell, err := s.ResolveSpell(elementID, mastery.ID, primaryElementID)
+			if err != nil || spell == nil || seenSpells[spell.ID] {
+				continue
+			}
+			seenSpells[spell.ID] = true
+			spells = append(spells, spell)
+		}
+	}
+	sort.Slice(spells, func(i, j int) bool { return spells[i].ID < spells[j].ID })
+	return spells
+}
+
+// _ChooseGhostCast เลือกเวทและเป้าหมาย: ฮีลตัวเองเมื่อ HP ต่ำ ไม่งั้นโจมตีทีมตรงข้ามตัวที่ HP น้อยที่สุด
+// คืน nil ถ้าไม่มีอะไรให้ร่าย
+func (s *combatService) _ChooseGhostCast(
+	match *domain.CombatMatch,
+	ghost *domain.Combatant,
+	spells []*domain.Spell,
+) (*domain.Spell, *domain.Combatant) {
+	// 1. HP ต่ำ → ฮีลตัวเอง (เลือกท่าที่ฮีลเยอะสุด)
+	if maxHP := s.getMaxHP(ghost); maxHP > 0 && float64(ghost.CurrentHP)/float64(maxHP) < ghostHealHPThreshold {
+		var bestHeal *domain.Spell
+		bestHealValue := 0.0
+		for _, spell := range spells {
+			if spell.TargetType != domain.TargetTypeSelf || !s._CanGhostCast(ghost, spell) {
+				continue
+			}
+			if value := spellBaseValue(spell, domain.EffectTypeHeal); value > bestHealValue {
+				bestHeal, bestHealValue = spell, value
+			}
+		}
+		if bestHeal != nil {
+			return bestHeal, ghost
+		}
+	}
+
+	// 2. หาเป้าหมาย: ทีมตรงข้ามที่ยังสู้อยู่และ HP น้อยที่สุด (ghost เป็นพันธมิตร co-op ได้ด้วย)
+	var target *domain.Combatant
+	for _, c := range s.findTeamOpponents(match, ghost) {
+		if target == nil || c.CurrentHP < target.CurrentHP {
+			target = c
+		}
+	}
+	if target == nil {
+		return nil, nil
+	}
+
+	// 3. เลือกเวทโจมตีที่ดาเมจพื้นฐานต่อ AP สูงสุด
+	var best *domain.Spell
+	bestScore := 0.0
+	for _, spell := range spells {
+		if spell.TargetType != domain.TargetTypeEnemy || spell.APCost <= 0 || !s._CanGhostCast(ghost, spell) {
+			continue
+		}
+		damage := spellBaseValue(spell, domain.EffectTypeDamage) + spellBaseValue(spell, domain.EffectTypeTrueDamage)
+		if score := damage / float64(spell.APCost); score > bestScore {
+			best, bestScore = spell, score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return best, target
+}
+
+// _CanGhostCast ตรวจ AP/MP/Element Charge แบบเดียวกับ PrepareAndValidateCast (โหมด INSTANT)
+func (s *combatService) _CanGhostCast(ghost *domain.Combatant, spell *domain.Spell) bool {
+	if ghost.CurrentAP < spell.APCost || ghost.CurrentMP < spell.MPCost {
+		return false
+	}
+	if spell.ElementID <= 4 {
+		return true // T0 ไม่ใช้ Charge
+	}
+	for _, charge := range ghost.Deck {
+		if charge.ElementID == spell.ElementID && !charge.IsConsumed {
+			return true
+		}
+	}
+	return false
+}
+
+// spellBaseValue รวม BaseValue ของ effect ประเภทที่กำหนดในเวท
+func spellBaseValue(spell *domain.Spell, effectType domain.EffectType) float64 {
+	total := 0.0
+	for _, spellEffect := range spell.Effects {
+		if spellEffect.Effect != nil && spellEffect.Effect.Type == effectType {
+			total += spellEffect.BaseValue
+		}
+	}
+	return total
+}
@@ -13,6 +13,7 @@ import (
 // โครงสร้าง AI System:
 // - ai_manager.go: Main orchestrator (ไฟล์นี้)
 // - ai_decision.go: Decision making (condition checking, action selection)
+// - ai_utility.go: Decision making แบบให้คะแนน (ศัตรูที่ตั้ง Enemy.AIMode = UTILITY)
 // - ai_execution.go: Action execution (resource deduction, effect application)
 
 // processAllAITurns ประมวลผลเทิร์นของ AI ทั้งหมดจนกว่าจะกลับมาเป็นเทิร์นผู้เล่น
@@ -41,8 +42,8 @@ func (s *combatService) processAllAITurns(match *domain.CombatMatch) (*domain.Co
 			break
 		}
 
-		// ถ้าเป็นเทิร์นผู้เล่น (CharacterID != nil) ให้หยุด loop
-		if currentCombatant.CharacterID != nil {
+		// ถ้าเป็นเทิร์นผู้เล่น (คนเล่นจริง) ให้หยุด loop
+		if isHumanControlled(currentCombatant) {
 			s.appLogger.Debug("Player turn reached, stopping AI loop",
 				"turns_processed", turnsProcessed,
 			)
@@ -65,6 +66,20 @@ func (s *combatService) processAllAITurns(match *domain.CombatMatch) (*domain.Co
 			// ตรวจสอบจบเกมหลังจาก AI เล่นเสร็จ
 			match = s.checkMatchEndCondition(match)
 
+			turnsProcessed++
+		} else if currentCombatant.IsAIControlled {
+			// ตัวละครของผู้เล่นอื่นที่ AI เล่นแทน (PvP แบบ async)
+			s.appLogger.Info("Processing ghost turn",
+				"ghost_id", currentCombatant.ID,
+				"turn_count", turnsProcessed+1,
+			)
+
+			var err error
+			match, err = s.processGhostTurn(match, currentCombatant)
+			if err != nil {
+				return nil, err
+			}
+
 			turnsProcessed++
 		}
 	}
@@ -135,6 +150,16 @@ func (s *combatService) _ValidateAICombatant(aiCombatant *domain.Combatant) bool
 		return false
 	}
 
+	if aiCombatant.Enemy.UsesUtilityAI() {
+		if len(aiCombatant.Enemy.Abilities) == 0 {
+			s.appLogger.Warn("Utility AI combatant has no abilities",
+				"ai_id", aiCombatant.ID,
+			)
+			return false
+		}
+		return true
+	}
+
 	if len(aiCombatant.Enemy.AI) == 0 {
 		s.appLogger.Warn("AI combatant has no AI rules",
 			"ai_id", aiCombatant.ID,
@@ -150,25 +175,45 @@ func (s *combatService) _PrepareDecisionContext(
 	match *domain.CombatMatch,
 	aiCombatant *domain.Combatant,
 ) *AIDecisionContext {
-	// หา player target
-	playerTarget := s.findPlayerCombatant(match)
+	// เรียง AI rules ตาม priority (น้อยไปมาก = ทำก่อน)
+	aiRules := aiCombatant.Enemy.AI
+	sort.Slice(aiRules, func(i, j int) bool {
+		return aiRules[i].Priority < aiRules[j].Priority
+	})
+
+	// แบ่งทีมจาก combatant ที่ยังมีชีวิต (เรียงตามลำดับใน match เพื่อให้ผลเลือกเป้าหมายคงที่)
+	var allies, opponents []*domain.Combatant
+	for _, c := range s.findAliveCombatants(match) {
+		switch {
+		case c.ID == aiCombatant.ID:
+		case isEnemySide(c) == isEnemySide(aiCombatant):
+			allies = append(allies, c)
+		default:
+			opponents = append(opponents, c)
+		}
+	}
+
+	// เป้าหลัก: ตัวละครที่ผู้เล่นควบคุมในทีมตรงข้าม ถ้าไม่มีใช้ตัวแรกที่ยังสู้อยู่
+	playerTarget := firstAlive(opponents)
+	for _, c := range opponents {
+		if isHumanControlled(c) {
+			playerTarget = c
+			break
+		}
+	}
 	if playerTarget == nil {
-		s.appLogger.Error("Player combatant not found in match",
+		s.appLogger.Error("No opposing combatant left in match",
 			nil,
 			"match_id", match.ID,
 		)
 		return nil
 	}
 
-	// เรียง AI rules ตาม priority (น้อยไปมาก = ทำก่อน)
-	aiRules := aiCombatant.Enemy.AI
-	sort.Slice(aiRules, func(i, j int) bool {
-		return aiRules[i].Priority < aiRules[j].Priority
-	})
-
 	return &AIDecisionContext{
 		AICombatant:    aiCombatant,
 		PlayerTarget:   playerTarget,
+		Allies:         allies,
+		Opponents:      opponents,
 		Match:          match,
 		AvailableRules: aiRules,
 	}
@@ -198,7 +243,7 @@ func (s *combatService) _ExecuteActionLoop(
 		)
 
 		// เลือก action ถัดไป
-		selectedAction := s.SelectNextAction(ctx)
+		selectedAction := s._SelectAIAction(ctx)
 
 		// ถ้าไม่เจอ action ที่ทำได้ ให้หยุด loop
 		if selectedAction == nil {
@@ -210,11 +255,20 @@ func (s *combatService) _ExecuteActionLoop(
 		}
 
 		// Execute action ที่เลือก
-		err := s.ExecuteAIAction(aiCombatant, selectedAction)
+		err := s.ExecuteAIAction(ctx.Match, aiCombatant, selectedAction)
 		if err != nil {
 			return err
 		}
 
+		// DEFEND/PASS/FLEE จบเทิร์นทันที
+		if selectedAction.Action.EndsTurn() {
+			s.appLogger.Info("AI action ended the turn",
+				"ai_id", aiCombatant.ID,
+				"action", selectedAction.Action,
+			)
+			break
+		}
+
 		// ตรวจสอบว่ายัง loop ต่อได้ไหม
 		if aiCombatant.CurrentAP <= 0 {
 			s.appLogger.Info("AI AP depleted, ending action loop",
@@ -241,6 +295,14 @@ func (s *combatService) _ExecuteActionLoop(
 	return nil
 }
 
+// _SelectAIAction เลือก action ตามโหมด AI ของศัตรู (UTILITY = ให้คะแนน, นอกนั้น = ไล่กฎ EnemyAI)
+func (s *combatService) _SelectAIAction(ctx *AIDecisionContext) *AISelectedAction {
+	if ctx.AICombatant.Enemy.UsesUtilityAI() {
+		return s.SelectUtilityAction(ctx)
+	}
+	return s.SelectNextAction(ctx)
+}
+
 // _EndAITurn จบเทิร์นของ AI และเริ่มเทิร์นถัดไป
 func (s *combatService) _EndAITurn(match *domain.CombatMatch) (*domain.CombatMatch, error) {
 	s.appLogger.Debug("Ending AI turn", "match_id", match.ID)
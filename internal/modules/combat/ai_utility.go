@@ -0,0 +1,289 @@
+// file: internal/modules/combat/ai_utility.go
+package combat
+
+import (
+	"encoding/json"
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sort"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== Utility AI ====================
+// ไฟล์นี้คือ AI ทางเลือกสำหรับศัตรูที่ตั้ง Enemy.AIMode = UTILITY (ศัตรูแบบเดิมยังไล่กฎ EnemyAI ใน ai_decision.go)
+// - ให้คะแนนทุก ability ที่จ่ายไหว × เป้าหมายที่ยังสู้อยู่ (ตัวเอง, เพื่อน, ฝั่งตรงข้าม) แล้วเลือกคะแนนสูงสุด
+// - ดาเมจคาดการณ์ใช้ modifier ชุดเดียวกับการร่ายจริง (CalculateCombinedModifiers → GetMatchupModifier)
+//   แล้วปรับตามบัฟ/ดีบัฟ/โล่/การหลบหลีกของเป้าหมาย
+// - ท่าที่คาดว่าฆ่าเป้าหมายได้ (lethal) ได้โบนัส, บัฟ/ดีบัฟที่เป้าหมายมีอยู่แล้วไม่ได้คะแนน
+// - คะแนนหารด้วยต้นทุน (AP + MP × aiUtilityMPWeight) = ความคุ้มค่าของทรัพยากร
+// - Enemy.AIDifficulty คุมความสุ่มของคะแนนและจำนวนท่าที่มองล่วงหน้า (ดู aiDifficultyProfiles)
+// ความสุ่มใช้ random stream ของ match → replay ได้ผลเดิมเสมอ
+
+const (
+	aiUtilityLethalBonus       = 100.0 // คะแนนพิเศษเมื่อคาดว่าท่านี้ฆ่าเป้าหมายได้
+	aiUtilityMPWeight          = 0.1   // MP 10 หน่วยมีค่าเท่า AP 1 หน่วย
+	aiUtilityStatusWeight      = 4.0   // คะแนนพื้นฐานต่อ 1 เทิร์นของบัฟ/ดีบัฟ
+	aiUtilityStatusValueWeight = 0.25  // คะแนนเพิ่มต่อค่า value ของบัฟ/ดีบัฟ ต่อ 1 เทิร์น
+	aiUtilityShieldWeight      = 0.8   // โล่ 1 หน่วยมีค่าน้อยกว่าฮีล 1 หน่วยเล็กน้อย
+	aiUtilityMPDamageWeight    = 0.5   // ทำลาย MP 1 หน่วยมีค่าครึ่งหนึ่งของดาเมจ HP
+	aiUtilityLookaheadDiscount = 0.8   // น้ำหนักของท่าถัดไปที่มองล่วงหน้า
+)
+
+// aiDifficultyProfile คือพฤติกรรมของ Utility AI ในแต่ละระดับความยาก
+type aiDifficultyProfile struct {
+	Noise     float64 // คะแนนแกว่งสุ่ม ±Noise (สัดส่วนของคะแนน)
+	Lookahead int     // จำนวนท่าถัดไปที่มองล่วงหน้าด้วย AP/MP ที่เหลือ
+}
+
+var aiDifficultyProfiles = map[domain.AIDifficulty]aiDifficultyProfile{
+	domain.AIDifficultyEasy:      {Noise: 0.5},
+	domain.AIDifficultyNormal:    {Noise: 0.2},
+	domain.AIDifficultyHard:      {Noise: 0.05, Lookahead: 1},
+	domain.AIDifficultyNightmare: {Lookahead: 2},
+}
+
+// aiProfileFor คือโปรไฟล์ของระดับความยาก (ค่าว่าง/ไม่รู้จัก = NORMAL)
+func aiProfileFor(difficulty domain.AIDifficulty) aiDifficultyProfile {
+	if profile, ok := aiDifficultyProfiles[difficulty]; ok {
+		return profile
+	}
+	return aiDifficultyProfiles[domain.AIDifficultyNormal]
+}
+
+// abilityEffect คือ effect 1 ตัวใน EnemyAbility.EffectsJSON
+type abilityEffect struct {
+	EffectID uint    `json:"effect_id"`
+	Value    float64 `json:"value"`
+	Duration int     `json:"duration"`
+}
+
+// utilityPlan คือสถานะสมมติระหว่างมองล่วงหน้า (ไม่แตะ combatant จริง)
+type utilityPlan struct {
+	AP int
+	MP int
+	HP map[uuid.UUID]int // HP คาดการณ์ของแต่ละเป้าหมายหลังท่าก่อนหน้า
+}
+
+// SelectUtilityAction เลือก ability × เป้าหมายที่ได้คะแนนสูงสุด (nil = ไม่มีท่าที่คุ้มจะใช้ → จบเทิร์น)
+func (s *combatService) SelectUtilityAction(ctx *AIDecisionContext) *AISelectedAction {
+	ai := ctx.AICombatant
+	profile := aiProfileFor(ai.Enemy.AIDifficulty)
+	targets := s._UtilityTargets(ctx)
+	plan := utilityPlan{AP: ai.CurrentAP, MP: ai.CurrentMP, HP: make(map[uuid.UUID]int, len(targets))}
+	for _, target := range targets {
+		plan.HP[target.ID] = target.CurrentHP
+	}
+
+	var best *AISelectedAction
+	bestScore := 0.0
+	for _, ability := range sortedAbilities(ai.Enemy.Abilities) {
+		if !s._CanAffordAbility(ai, ability) {
+			continue
+		}
+		for _, target := range targets {
+			score := s._ScoreUtilityAction(ctx, ability, target, plan, profile.Lookahead)
+			if score <= 0 {
+				continue
+			}
+			if profile.Noise > 0 {
+				score *= 1 + profile.Noise*(s.rollFloat64(ctx.Match)*2-1)
+			}
+			if score > bestScore {
+				bestScore = score
+				best = &AISelectedAction{Action: domain.AIActionUseAbility, Ability: ability, Target: target}
+			}
+		}
+	}
+
+	if best == nil {
+		s.appLogger.Info("❌ Utility AI found no worthwhile action",
+			"ai_id", ai.ID,
+			"ap_remaining", ai.CurrentAP,
+		)
+		return nil
+	}
+	s.appLogger.Info("✅ Utility AI selected action",
+		"ability", best.Ability.Name,
+		"target", best.Target.ID,
+		"score", bestScore,
+		"difficulty", ai.Enemy.AIDifficulty,
+	)
+	return best
+}
+
+// _UtilityTargets คือเป้าหมายที่ Utility AI พิจารณา: ตัวเอง, เพื่อน และฝั่งตรงข้ามที่ยังสู้อยู่
+func (s *combatService) _UtilityTargets(ctx *AIDecisionContext) []*domain.Combatant {
+	targets := []*domain.Combatant{ctx.AICombatant}
+	targets = append(targets, aliveCombatants(ctx.Allies)...)
+	return append(targets, aliveCombatants(ctx.Opponents)...)
+}
+
+// _ScoreUtilityAction ให้คะแนน ability กับเป้าหมาย 1 ตัวภายใต้ plan (รวมท่าถัดไปถ้า lookahead > 0)
+func (s *combatService) _ScoreUtilityAction(
+	ctx *AIDecisionContext,
+	ability *domain.EnemyAbility,
+	target *domain.Combatant,
+	plan utilityPlan,
+	lookahead int,
+) float64 {
+	targetHP := plan.HP[target.ID]
+	if targetHP <= 0 {
+		return 0
+	}
+	value, damage := s._UtilityValue(ctx.AICombatant, ability, target, targetHP)
+	if value <= 0 {
+		return 0
+	}
+
+	cost := math.Max(1, float64(ability.APCost)) + float64(ability.MPCost)*aiUtilityMPWeight
+	score := value / cost
+	if damage >= targetHP {
+		score += aiUtilityLethalBonus
+	}
+
+	if lookahead > 0 {
+		next := utilityPlan{AP: plan.AP - ability.APCost, MP: plan.MP - ability.MPCost, HP: make(map[uuid.UUID]int, len(plan.HP))}
+		for id, hp := range plan.HP {
+			next.HP[id] = hp
+		}
+		next.HP[target.ID] = targetHP - damage
+		score += aiUtilityLookaheadDiscount * s._BestUtilityFollowUp(ctx, next, lookahead-1)
+	}
+	return score
+}
+
+// _BestUtilityFollowUp คือคะแนนสูงสุดของท่าถัดไปที่ทำได้ด้วย AP/MP ที่เหลือใน plan (ไม่สุ่ม)
+func (s *combatService) _BestUtilityFollowUp(ctx *AIDecisionContext, plan utilityPlan, lookahead int) float64 {
+	best := 0.0
+	for _, ability := range sortedAbilities(ctx.AICombatant.Enemy.Abilities) {
+		if ability.APCost > plan.AP || ability.MPCost > plan.MP {
+			continue
+		}
+		for _, target := range s._UtilityTargets(ctx) {
+			if score := s._ScoreUtilityAction(ctx, ability, target, plan, lookahead); score > best {
+				best = score
+			}
+		}
+	}
+	return best
+}
+
+// _UtilityValue คือมูลค่าของ ability ต่อเป้าหมาย (ติดลบ = ช่วยฝั่งตรงข้าม/ทำร้ายพวกเดียวกัน)
+// และดาเมจ HP ที่คาดว่าจะทำได้ (ใช้เช็ค lethal และมองล่วงหน้า)
+func (s *combatService) _UtilityValue(
+	caster *domain.Combatant,
+	ability *domain.EnemyAbility,
+	target *domain.Combatant,
+	targetHP int,
+) (float64, int) {
+	var effects []abilityEffect
+	if err := json.Unmarshal(ability.EffectsJSON, &effects); err != nil {
+		return 0, 0
+	}
+
+	friendly := target.ID == caster.ID || isEnemySide(target) == isEnemySide(caster)
+	side := 1.0
+	if !friendly {
+		side = -1.0 // ของดีให้ฝั่งตรงข้าม = เสียคะแนน
+	}
+	missingHP := math.Max(0, float64(s.getMaxHP(target)-targetHP))
+
+	value, damage := 0.0, 0.0
+	for _, e := range effects {
+		duration := math.Max(1, float64(e.Duration))
+		switch e.EffectID {
+		case 1101: // DAMAGE
+			expected := s._ExpectedAbilityDamage(caster, target, e.Value)
+			if friendly {
+				value -= expected
+				continue
+			}
+			value += math.Min(expected, float64(targetHP)) // ดาเมจที่เกิน HP ไม่มีค่า
+			damage += expected
+		case 1102: // SHIELD
+			value += side * e.Value * aiUtilityShieldWeight
+		case 1103: // HEAL
+			value += side * math.Min(e.Value, missingHP)
+		case 1104: // MP_DAMAGE
+			value -= side * e.Value * aiUtilityMPDamageWeight
+		case 2101: // BUFF_HP_REGEN (ฮีลที่เกิน HP ที่หายไปไม่มีค่า)
+			if !s._HasActiveEffect(target, e.EffectID) {
+				value += side * math.Min(e.Value*duration, missingHP)
+			}
+		default:
+			effect, err := s.gameDataRepo.FindEffectByID(e.EffectID)
+			if err != nil || effect == nil || s._HasActiveEffect(target, e.EffectID) {
+				continue
+			}
+			status := duration * (aiUtilityStatusWeight + math.Abs(e.Value)*aiUtilityStatusValueWeight)
+			switch {
+			case effect.Type.IsBuff():
+				value += side * status
+			case effect.Type.IsDebuff():
+				value -= side * status
+			}
+		}
+	}
+	return value, int(math.Round(damage))
+}
+
+// _ExpectedAbilityDamage คาดการณ์ดาเมจของ ability ต่อเป้าหมาย (ค่าเฉลี่ย ไม่สุ่มการหลบหลีก)
+// ใช้ modifier ชุดเดียวกับการร่ายจริง แล้วปรับตาม effect บนเป้าหมายและผู้ใช้ท่า
+func (s *combatService) _ExpectedAbilityDamage(caster *domain.Combatant, target *domain.Combatant, baseValue float64) float64 {
+	spell := &domain.Spell{ElementID: caster.Enemy.ElementID} // ธาตุเดียวกับ dummy spell ตอน execute
+	damage := baseValue
+	if mods, err := s.CalculateCombinedModifiers(caster, target, spell, 1.0, 1101); err == nil {
+		damage *= mods.CombinedMod
+	}
+
+	shield := 0.0
+	for _, effect := range activeEffectsOf(target) {
+		switch effect.EffectID {
+		case 2204: // BUFF_DEFENSE_UP
+			reduction := 0.5
+			if effect.Value > 0 {
+				reduction = float64(effect.Value) / 100.0
+			}
+			damage *= math.Max(0, 1.0-reduction)
+		case 4102: // DEBUFF_VULNERABLE
+			damage *= 1.0 + float64(effect.Value)/100.0
+		case 2201: // BUFF_EVASION
+			damage *= math.Max(0, 1.0-float64(effect.Value)/100.0)
+		case 1102: // SHIELD
+			shield += float64(effect.Value)
+		}
+	}
+	for _, effect := range activeEffectsOf(caster) {
+		if effect.EffectID == 2202 { // BUFF_DAMAGE_UP
+			damage *= 1.0 + float64(effect.Value)/100.0
+		}
+	}
+	return math.Max(0, damage-shield)
+}
+
+// _HasActiveEffect เช็คว่าเป้าหมายมี effect นี้อยู่แล้วหรือไม่ (ใส่ซ้ำไม่ได้คะแนน)
+func (s *combatService) _HasActiveEffect(target *domain.Combatant, effectID uint) bool {
+	for _, effect := range activeEffectsOf(target) {
+		if effect.EffectID == effectID {
+			return true
+		}
+	}
+	return false
+}
+
+// activeEffectsOf อ่าน ActiveEffects ของ combatant (JSON เสีย = ไม่มี effect)
+func activeEffectsOf(combatant *domain.Combatant) []domain.ActiveEffect {
+	var effects []domain.ActiveEffect
+	if combatant.ActiveEffects != nil {
+		json.Unmarshal(combatant.ActiveEffects, &effects)
+	}
+	return effects
+}
+
+// sortedAbilities เรียง ability ตาม ID เพื่อให้ลำดับการสุ่มคงที่ไม่ว่าจะโหลดมาจากที่ไหน
+func sortedAbilities(abilities []*domain.EnemyAbility) []*domain.EnemyAbility {
+	sorted := append([]*domain.EnemyAbility(nil), abilities...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
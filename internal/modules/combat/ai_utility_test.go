@@ -0,0 +1,148 @@
+package combat
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestScoreUtilityAction(t *testing.T) {
+	// คะแนน = มูลค่า / (AP + MP × 0.1) และ +100 ถ้าคาดว่าฆ่าเป้าหมายได้
+	s := newTestCombatService(nil)
+
+	tests := []struct {
+		name          string
+		effects       []abilityEffect
+		apCost        int
+		mpCost        int
+		onSelf        bool
+		targetHP      int
+		targetEffects []domain.ActiveEffect
+		want          float64
+	}{
+		{name: "lethal hit gets the bonus", effects: []abilityEffect{{EffectID: 1101, Value: 30}}, targetHP: 30, want: 130},
+		{name: "hit below target hp", effects: []abilityEffect{{EffectID: 1101, Value: 20}}, targetHP: 30, want: 20},
+		{name: "overkill only counts the remaining hp", effects: []abilityEffect{{EffectID: 1101, Value: 50}}, apCost: 2, targetHP: 30, want: 115},
+		{name: "mp adds to the cost", effects: []abilityEffect{{EffectID: 1101, Value: 20}}, mpCost: 10, targetHP: 100, want: 10},
+		{
+			name:          "shield soaks the expected damage",
+			effects:       []abilityEffect{{EffectID: 1101, Value: 30}},
+			targetHP:      30,
+			targetEffects: []domain.ActiveEffect{{EffectID: 1102, Value: 15, TurnsRemaining: 2}},
+			want:          15,
+		},
+		{
+			name:          "defending target is no longer lethal",
+			effects:       []abilityEffect{{EffectID: 1101, Value: 30}},
+			targetHP:      30,
+			targetEffects: []domain.ActiveEffect{{EffectID: 2204, Value: 50, TurnsRemaining: 1}},
+			want:          15,
+		},
+		{
+			name:          "vulnerable target becomes lethal",
+			effects:       []abilityEffect{{EffectID: 1101, Value: 20}},
+			targetHP:      30,
+			targetEffects: []domain.ActiveEffect{{EffectID: 4102, Value: 50, TurnsRemaining: 2}},
+			want:          130,
+		},
+		{name: "damage on itself is worthless", effects: []abilityEffect{{EffectID: 1101, Value: 30}}, onSelf: true, targetHP: 100},
+		{name: "heal is capped at missing hp", effects: []abilityEffect{{EffectID: 1103, Value: 40}}, onSelf: true, targetHP: 70, want: 30},
+		{name: "heal at full hp is worthless", effects: []abilityEffect{{EffectID: 1103, Value: 40}}, onSelf: true, targetHP: 100},
+		{name: "heal on an opponent is worthless", effects: []abilityEffect{{EffectID: 1103, Value: 40}}, targetHP: 30},
+		{name: "new buff", effects: []abilityEffect{{EffectID: 2202, Value: 20, Duration: 2}}, onSelf: true, targetHP: 100, want: 18},
+		{
+			name:          "buff already active is worthless",
+			effects:       []abilityEffect{{EffectID: 2202, Value: 20, Duration: 2}},
+			onSelf:        true,
+			targetHP:      100,
+			targetEffects: []domain.ActiveEffect{{EffectID: 2202, Value: 20, TurnsRemaining: 1}},
+		},
+		{name: "debuff on an opponent", effects: []abilityEffect{{EffectID: 4102, Value: 20, Duration: 2}}, targetHP: 100, want: 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ai := newTestEnemy(100, 100)
+			target := newTestCharacter(tt.targetHP, 100)
+			if tt.onSelf {
+				ai.CurrentHP = tt.targetHP
+				target = ai
+			}
+			withEffects(target, tt.targetEffects...)
+			apCost := tt.apCost
+			if apCost == 0 {
+				apCost = 1
+			}
+			ability := &domain.EnemyAbility{ID: 1, Name: "Test", APCost: apCost, MPCost: tt.mpCost, EffectsJSON: abilityEffects(tt.effects...)}
+			ctx := &AIDecisionContext{AICombatant: ai, Match: &domain.CombatMatch{}}
+			plan := utilityPlan{HP: map[uuid.UUID]int{target.ID: tt.targetHP}}
+
+			if got := s._ScoreUtilityAction(ctx, ability, target, plan, 0); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("_ScoreUtilityAction() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectUtilityAction(t *testing.T) {
+	s := newTestCombatService(nil)
+	strike := &domain.EnemyAbility{ID: 1, Name: "Strike", APCost: 1, EffectsJSON: abilityEffects(abilityEffect{EffectID: 1101, Value: 30})}
+	smash := &domain.EnemyAbility{ID: 2, Name: "Smash", APCost: 2, EffectsJSON: abilityEffects(abilityEffect{EffectID: 1101, Value: 50})}
+	mend := &domain.EnemyAbility{ID: 3, Name: "Mend", APCost: 1, EffectsJSON: abilityEffects(abilityEffect{EffectID: 1103, Value: 40})}
+
+	newAI := func(ap int, difficulty domain.AIDifficulty, abilities ...*domain.EnemyAbility) *domain.Combatant {
+		ai := newTestEnemy(100, 100)
+		ai.CurrentAP = ap
+		ai.Enemy.AIDifficulty = difficulty
+		ai.Enemy.Abilities = abilities
+		return ai
+	}
+
+	t.Run("finishes the target it can kill", func(t *testing.T) {
+		healthy, weak := newTestCharacter(100, 100), newTestCharacter(25, 100)
+		ctx := &AIDecisionContext{
+			AICombatant: newAI(1, domain.AIDifficultyNightmare, strike, mend),
+			Opponents:   []*domain.Combatant{healthy, weak},
+			Match:       &domain.CombatMatch{},
+		}
+
+		selected := s.SelectUtilityAction(ctx)
+		if selected == nil || selected.Ability != strike || selected.Target != weak {
+			t.Fatalf("SelectUtilityAction() = %+v, want Strike on the weak opponent", selected)
+		}
+	})
+
+	t.Run("nothing worth doing", func(t *testing.T) {
+		ctx := &AIDecisionContext{
+			AICombatant: newAI(1, domain.AIDifficultyNightmare, mend),
+			Opponents:   []*domain.Combatant{newTestCharacter(30, 100)},
+			Match:       &domain.CombatMatch{},
+		}
+
+		if selected := s.SelectUtilityAction(ctx); selected != nil {
+			t.Fatalf("SelectUtilityAction() = %+v, want no action at full hp", selected)
+		}
+	})
+
+	t.Run("lookahead plans two strikes over one smash", func(t *testing.T) {
+		// มองท่าเดียว: Smash = 50/2 + 100 ชนะ Strike = 30
+		// มองล่วงหน้า: Strike แล้ว Strike ซ้ำ (ฆ่าได้) = 30 + 0.8 × (20 + 100) = 126 ชนะ Smash = 125
+		target := newTestCharacter(50, 100)
+		ctx := &AIDecisionContext{
+			AICombatant: newAI(2, domain.AIDifficultyNightmare, strike, smash),
+			Opponents:   []*domain.Combatant{target},
+			Match:       &domain.CombatMatch{},
+		}
+		plan := utilityPlan{AP: 2, HP: map[uuid.UUID]int{ctx.AICombatant.ID: 100, target.ID: 50}}
+		if greedyStrike, greedySmash := s._ScoreUtilityAction(ctx, strike, target, plan, 0), s._ScoreUtilityAction(ctx, smash, target, plan, 0); greedySmash <= greedyStrike {
+			t.Fatalf("without lookahead: strike = %.2f, smash = %.2f, want smash ahead", greedyStrike, greedySmash)
+		}
+
+		selected := s.SelectUtilityAction(ctx)
+		if selected == nil || selected.Ability != strike {
+			t.Fatalf("SelectUtilityAction() = %+v, want Strike", selected)
+		}
+	})
+}
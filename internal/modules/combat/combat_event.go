@@ -0,0 +1,201 @@
+// file: internal/modules/combat/combat_event.go
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/datatypes"
+)
+
+// ==================== Combat Event Log ====================
+// ไฟล์นี้รวบรวม helper สำหรับบันทึก CombatEvent ระหว่างประมวลผลการต่อสู้
+// - event จะถูกเก็บไว้ใน match.PendingEvents ตามลำดับที่เกิดขึ้น
+// - UpdateMatch จะบันทึก event ลง DB พร้อมกับสถานะ match
+// - PerformAction ส่ง event ทั้งหมดกลับไปให้ client ใน response
+
+// recordEvent ใส่ลำดับ (Sequence) และเทิร์นให้ event แล้วเก็บไว้ใน match
+func (s *combatService) recordEvent(match *domain.CombatMatch, event *domain.CombatEvent) {
+	if match == nil || event == nil {
+		return
+	}
+
+	match.EventSequence++
+	event.MatchID = match.ID
+	event.Sequence = match.EventSequence
+	event.TurnNumber = match.TurnNumber
+	match.PendingEvents = append(match.PendingEvents, event)
+
+	// Threat = ดาเมจ + ฮีลที่ผู้ร่ายทำไป (AI ใช้เลือกเป้าหมาย PLAYER_HIGHEST_THREAT)
+	switch event.Type {
+	case domain.CombatEventDamage, domain.CombatEventRetaliation, domain.CombatEventHeal:
+		if event.SourceID != nil && event.Value > 0 {
+			if source := s.findCombatantByID(match, *event.SourceID); source != nil {
+				source.Threat += event.Value
+			}
+		}
+	}
+}
+
+// _RecordApplicationEvents แปลงผลลัพธ์จาก ApplyCalculatedEffects เป็น event
+func (s *combatService) _RecordApplicationEvents(
+	match *domain.CombatMatch,
+	caster *domain.Combatant,
+	spell *domain.Spell,
+	result *EffectApplicationResult,
+) {
+	for _, applied := range result.AppliedEffects {
+		effectID := applied.EffectID
+		spellID := spell.ID
+		base := domain.CombatEvent{
+			SourceID: combatantIDPtr(caster.ID),
+			TargetID: combatantIDPtr(applied.TargetID),
+			SpellID:  &spellID,
+			EffectID: &effectID,
+		}
+
+		if applied.Evaded {
+			event := base
+			event.Type = domain.CombatEventEvaded
+			s.recordEvent(match, &event)
+			continue
+		}
+
+		switch applied.EffectType {
+		case "DAMAGE":
+			if applied.Absorbed > 0 {
+				event := base
+				event.Type = domain.CombatEventShieldAbsorbed
+				event.Value = int(applied.Absorbed)
+				s.recordEvent(match, &event)
+			}
+			event := base
+			event.Type = domain.CombatEventDamage
+			event.Value = int(applied.ActualValue)
+			s.recordEvent(match, &event)
+			if applied.Retaliation > 0 {
+				s._RecordDirectEvent(match, domain.CombatEventRetaliation,
+					s.findCombatantByID(match, applied.TargetID), caster, 2203, int(applied.Retaliation), nil)
+			}
+		case "HEAL":
+			event := base
+			event.Type = domain.CombatEventHeal
+			event.Value = int(applied.ActualValue)
+			s.recordEvent(match, &event)
+		case "MP_DAMAGE":
+			event := base
+			event.Type = domain.CombatEventMPDamage
+			event.Value = int(applied.ActualValue)
+			event.Details = eventDetails(applied.Details)
+			s.recordEvent(match, &event)
+		default:
+			// SHIELD, BUFF, DEBUFF (รวม Stance)
+			event := base
+			event.Type = domain.CombatEventEffectApplied
+			event.Value = int(applied.ActualValue)
+			s.recordEvent(match, &event)
+		}
+	}
+}
+
+// newCombatEvent สร้าง event พื้นฐานจาก source/target (nil ได้)
+func newCombatEvent(eventType domain.CombatEventType, source *domain.Combatant, target *domain.Combatant, value int) *domain.CombatEvent {
+	event := &domain.CombatEvent{
+		Type:  eventType,
+		Value: value,
+	}
+	if source != nil {
+		event.SourceID = combatantIDPtr(source.ID)
+	}
+	if target != nil {
+		event.TargetID = combatantIDPtr(target.ID)
+	}
+	return event
+}
+
+// combatantIDPtr คืน pointer ของ UUID (copy ค่า เพื่อไม่ผูกกับตัวแปรเดิม)
+func combatantIDPtr(id uuid.UUID) *uuid.UUID {
+	return &id
+}
+
+// eventSpellID คืน pointer ของ Spell ID (nil สำหรับ dummy spell ของ AI ที่ไม่มี ID)
+func eventSpellID(spell *domain.Spell) *uint {
+	if spell == nil || spell.ID == 0 {
+		return nil
+	}
+	spellID := spell.ID
+	return &spellID
+}
+
+// eventDetails แปลงข้อมูลเพิ่มเติมเป็น JSON (คืน nil ถ้าไม่มีข้อมูลหรือแปลงไม่ได้)
+func eventDetails(details interface{}) datatypes.JSON {
+	if details == nil {
+		return nil
+	}
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// _RecordTickEvent บันทึก event ของ effect ต่อเนื่องที่ทำงานต้นเทิร์น (HP/MP Regen, DoT)
+func (s *combatService) _RecordTickEvent(match *domain.CombatMatch, combatant *domain.Combatant, effect domain.ActiveEffect, amount int) {
+	event := newCombatEvent(domain.CombatEventTick, nil, combatant, amount)
+	event.SourceID = combatantIDPtr(effect.SourceID)
+	event.EffectID = &effect.EffectID
+	event.Details = eventDetails(map[string]interface{}{
+		"turns_remaining": effect.TurnsRemaining,
+		"hp_after":        combatant.CurrentHP,
+		"mp_after":        combatant.CurrentMP,
+	})
+	s.recordEvent(match, event)
+}
+
+// _RecordLegacyEffectApplied บันทึก EFFECT_APPLIED สำหรับ effect ที่ apply ผ่าน applyEffect (AI)
+// โดยอ่านค่าล่าสุดของ effect นั้นจาก ActiveEffects ของเป้าหมาย
+func (s *combatService) _RecordLegacyEffectApplied(
+	match *domain.CombatMatch,
+	caster *domain.Combatant,
+	target *domain.Combatant,
+	effectID uint,
+	spell *domain.Spell,
+) {
+	var activeEffects []domain.ActiveEffect
+	if target.ActiveEffects != nil {
+		if err := json.Unmarshal(target.ActiveEffects, &activeEffects); err != nil {
+			return
+		}
+	}
+
+	for i := len(activeEffects) - 1; i >= 0; i-- {
+		if activeEffects[i].EffectID != effectID {
+			continue
+		}
+		event := newCombatEvent(domain.CombatEventEffectApplied, caster, target, activeEffects[i].Value)
+		event.EffectID = &effectID
+		event.SpellID = eventSpellID(spell)
+		event.Details = eventDetails(map[string]interface{}{
+			"turns_remaining": activeEffects[i].TurnsRemaining,
+		})
+		s.recordEvent(match, event)
+		return
+	}
+}
+
+// _RecordDirectEvent บันทึก event ของ direct effect (1000s) จาก applyDamage/applyHeal/applyShield/applyMpDamage
+func (s *combatService) _RecordDirectEvent(
+	match *domain.CombatMatch,
+	eventType domain.CombatEventType,
+	source *domain.Combatant,
+	target *domain.Combatant,
+	effectID uint,
+	value int,
+	spell *domain.Spell,
+) {
+	event := newCombatEvent(eventType, source, target, value)
+	event.EffectID = &effectID
+	event.SpellID = eventSpellID(spell)
+	s.recordEvent(match, event)
+}
@@ -0,0 +1,52 @@
+// file: internal/modules/combat/combat_random.go
+package combat
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"sage-of-elements-backend/internal/domain"
+	"time"
+)
+
+// ==================== Match Random Source ====================
+// ทุกการสุ่มในสนามรบ (Evasion, Multi-Cast ฯลฯ) ต้องผ่านไฟล์นี้เท่านั้น
+// - Seed ถูกสุ่มครั้งเดียวตอน CreateMatch และเก็บไว้ใน CombatMatch.RandomSeed
+// - แต่ละครั้งที่สุ่มจะเลื่อน CombatMatch.RandomCursor ไป 1 ช่อง
+// - ค่าที่ได้ = splitmix64(seed, cursor) → seed เดิม + action ชุดเดิม = ผลลัพธ์เดิมเสมอ
+//   (ไม่ต้องเก็บ state ของ generator ลง DB และไม่ขึ้นกับ global math/rand)
+
+// newMatchSeed สุ่ม seed ใหม่สำหรับ match (fallback เป็นเวลาปัจจุบันถ้า crypto/rand ใช้ไม่ได้)
+func newMatchSeed() int64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:]))
+}
+
+// _NextRandom ดึงเลขสุ่ม 64-bit ถัดไปจาก random stream ของ match
+func (s *combatService) _NextRandom(match *domain.CombatMatch) uint64 {
+	match.RandomCursor++
+	return splitmix64(uint64(match.RandomSeed) + uint64(match.RandomCursor)*0x9E3779B97F4A7C15)
+}
+
+// rollIntn สุ่มเลขจำนวนเต็มในช่วง [0, n)
+func (s *combatService) rollIntn(match *domain.CombatMatch, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(s._NextRandom(match) % uint64(n))
+}
+
+// rollFloat64 สุ่มเลขทศนิยมในช่วง [0.0, 1.0)
+func (s *combatService) rollFloat64(match *domain.CombatMatch) float64 {
+	return float64(s._NextRandom(match)>>11) / float64(uint64(1)<<53)
+}
+
+// splitmix64 คือ mixing function มาตรฐาน (กระจายบิตได้ดี และคำนวณซ้ำได้เหมือนเดิมทุกครั้ง)
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,83 @@
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+)
+
+func TestMatchRandomIsDeterministic(t *testing.T) {
+	s := &combatService{}
+	a := &domain.CombatMatch{RandomSeed: 42}
+	b := &domain.CombatMatch{RandomSeed: 42}
+	other := &domain.CombatMatch{RandomSeed: 43}
+
+	differs := false
+	for i := 0; i < 100; i++ {
+		va, vb, vo := s._NextRandom(a), s._NextRandom(b), s._NextRandom(other)
+		if va != vb {
+			t.Fatalf("draw %d: same seed gave %d and %d", i, va, vb)
+		}
+		differs = differs || va != vo
+	}
+	if !differs {
+		t.Error("different seeds produced the same stream")
+	}
+	if a.RandomCursor != 100 {
+		t.Errorf("cursor = %d, want 100", a.RandomCursor)
+	}
+}
+
+func TestMatchRandomResumesFromCursor(t *testing.T) {
+	// match ที่โหลดจาก DB กลางทาง (seed + cursor) ต้องสุ่มต่อได้ค่าเดียวกับ match ที่สุ่มต่อเนื่อง
+	s := &combatService{}
+	continuous := &domain.CombatMatch{RandomSeed: -7}
+	for i := 0; i < 5; i++ {
+		s._NextRandom(continuous)
+	}
+	resumed := &domain.CombatMatch{RandomSeed: -7, RandomCursor: 5}
+
+	for i := 0; i < 10; i++ {
+		if want, got := s._NextRandom(continuous), s._NextRandom(resumed); want != got {
+			t.Fatalf("draw %d after resume = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestMatchRandomRanges(t *testing.T) {
+	s := &combatService{}
+	match := &domain.CombatMatch{RandomSeed: 2024}
+
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{name: "coin", n: 2},
+		{name: "d6", n: 6},
+		{name: "percent", n: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[int]bool)
+			for i := 0; i < 2000; i++ {
+				v := s.rollIntn(match, tt.n)
+				if v < 0 || v >= tt.n {
+					t.Fatalf("rollIntn(%d) = %d, out of range", tt.n, v)
+				}
+				seen[v] = true
+			}
+			if len(seen) != tt.n {
+				t.Errorf("rollIntn(%d) produced %d distinct values, want all %d", tt.n, len(seen), tt.n)
+			}
+		})
+	}
+
+	before := match.RandomCursor
+	if v := s.rollIntn(match, 0); v != 0 || match.RandomCursor != before {
+		t.Errorf("rollIntn(0) = %d and moved the cursor, want 0 without drawing", v)
+	}
+	for i := 0; i < 1000; i++ {
+		if f := s.rollFloat64(match); f < 0 || f >= 1 {
+			t.Fatalf("rollFloat64() = %f, out of [0, 1)", f)
+		}
+	}
+}
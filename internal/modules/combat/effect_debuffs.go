@@ -31,6 +31,7 @@ func (s *combatService) applyDebuffSlow(caster *domain.Combatant, target *domain
 	activeEffects = append(activeEffects, newEffect)
 	newEffectsJSON, _ := json.Marshal(activeEffects)
 	target.ActiveEffects = newEffectsJSON
+	s.recalculateStats(target) // ลำดับเทิร์นในรอบนี้เปลี่ยนทันที
 	s.appLogger.Info("Applied DEBUFF_SLOW effect", "caster", caster.ID, "target", target.ID, "duration", duration)
 }
 
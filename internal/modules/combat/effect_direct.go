@@ -4,7 +4,6 @@ package combat
 import (
 	"encoding/json"
 	"math"
-	"math/rand"
 	"sage-of-elements-backend/internal/domain"
 	"sort"
 )
@@ -16,7 +15,7 @@ import (
 // ============================================================================
 
 // --- ⭐️ ผู้เชี่ยวชาญด้านการทำ Damage (เวอร์ชันอัปเกรดเต็มรูปแบบ) ⭐️ ---
-func (s *combatService) applyDamage(caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
+func (s *combatService) applyDamage(match *domain.CombatMatch, caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
 
 	// --- ⭐️ ขั้นตอนที่ 1: Logic เช็ค Evasion (หลบหลีก) ⭐️ ---
 	// ต้องเช็คก่อน! ถ้าหลบได้ คือจบเลย ไม่ต้องคำนวณอะไรต่อ
@@ -41,11 +40,11 @@ func (s *combatService) applyDamage(caster *domain.Combatant, target *domain.Com
 
 	if hasEvasionBuff && evasionChance > 0 {
 		// สุ่มเลข 0-99
-		roll := rand.Intn(100) // ผลลัพธ์คือ 0 ถึง 99
+		roll := s.rollIntn(match, 100) // ผลลัพธ์คือ 0 ถึง 99 (จาก random stream ของ match)
 		s.appLogger.Info("Performing Evasion check", "target_id", target.ID, "chance", evasionChance, "roll", roll)
 		if roll < evasionChance { // ถ้าเลขสุ่ม < โอกาสหลบ
 			s.appLogger.Info("Attack EVADED!", "caster", caster.ID, "target", target.ID, "spell_id", spell.ID)
-			// TODO: อาจจะส่ง Event บอก Client ว่า "MISS!"
+			s._RecordDirectEvent(match, domain.CombatEventEvaded, caster, target, 1101, 0, spell)
 			return // ⭐️ จบการทำงาน! ไม่ต้องคำนวณ Damage หรือ Shield ต่อ!
 		} else {
 			s.appLogger.Info("Evasion check failed, attack proceeds", "target_id", target.ID)
@@ -208,6 +207,11 @@ func (s *combatService) applyDamage(caster *domain.Combatant, target *domain.Com
 	}
 	hpAfter := target.CurrentHP // HP สุดท้าย
 
+	if shieldAbsorbedTotal > 0 {
+		s._RecordDirectEvent(match, domain.CombatEventShieldAbsorbed, caster, target, 1101, shieldAbsorbedTotal, spell)
+	}
+	s._RecordDirectEvent(match, domain.CombatEventDamage, caster, target, 1101, hpBefore-hpAfter, spell)
+
 	// --- ⭐️ ขั้นตอนที่ 8: (ใหม่!) Logic เช็ค Retaliation (ID 2203) บนเป้าหมาย ⭐️ ---
 	// (สะท้อน Damage กลับไปหา Caster)
 	var targetActiveEffectsForRetaliation []domain.ActiveEffect
@@ -242,6 +246,7 @@ func (s *combatService) applyDamage(caster *domain.Combatant, target *domain.Com
 			caster.CurrentHP = 0
 		} // กัน Caster เลือดติดลบ
 		s.appLogger.Info("Applied Retaliation damage to caster", "caster_id", caster.ID, "damage_taken", retaliationDamage, "caster_hp_before", casterHpBefore, "caster_hp_after", caster.CurrentHP)
+		s._RecordDirectEvent(match, domain.CombatEventRetaliation, target, caster, 2203, casterHpBefore-caster.CurrentHP, nil)
 	}
 	// --- ⭐️ สิ้นสุด Logic Retaliation ⭐️ ---
 
@@ -259,7 +264,7 @@ func (s *combatService) applyDamage(caster *domain.Combatant, target *domain.Com
 }
 
 // --- ปรับปรุงผู้เชี่ยวชาญ Heal ---
-func (s *combatService) applyHeal(caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
+func (s *combatService) applyHeal(match *domain.CombatMatch, caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
 	// --- ⭐️ เพิ่มการตรวจสอบ Type Assertion ⭐️ ---
 	effectIDFloat, ok1 := effectData["effect_id"].(float64)
 	baseValueFloat, ok2 := effectData["value"].(float64)
@@ -305,10 +310,11 @@ func (s *combatService) applyHeal(caster *domain.Combatant, target *domain.Comba
 	hpAfter := target.CurrentHP
 
 	s.appLogger.Info("Applied HEAL_HP effect", "caster", caster.ID, "target", target.ID, "heal", healAmount, "target_hp_before", hpBefore, "target_hp_after", hpAfter)
+	s._RecordDirectEvent(match, domain.CombatEventHeal, caster, target, 1103, hpAfter-hpBefore, spell)
 }
 
 // --- ⭐️ เพิ่ม ผู้เชี่ยวชาญ Shield! ⭐️ ---
-func (s *combatService) applyShield(caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
+func (s *combatService) applyShield(match *domain.CombatMatch, caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
 	// --- การตรวจสอบ Type Assertion ---
 	effectIDFloat, ok1 := effectData["effect_id"].(float64)
 	baseValueFloat, ok2 := effectData["value"].(float64)
@@ -401,10 +407,11 @@ func (s *combatService) applyShield(caster *domain.Combatant, target *domain.Com
 	target.ActiveEffects = newEffectsJSON
 
 	s.appLogger.Info("Applied SHIELD effect", "target", target.ID, "shield_hp", shieldHP, "duration", shieldDuration)
+	s._RecordDirectEvent(match, domain.CombatEventEffectApplied, caster, target, 1102, shieldHP, spell)
 }
 
 // --- ⭐️ เพิ่ม ผู้เชี่ยวชาญ MP Damage/Drain! ⭐️ ---
-func (s *combatService) applyMpDamage(caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
+func (s *combatService) applyMpDamage(match *domain.CombatMatch, caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
 	// --- การตรวจสอบ Type Assertion ---
 	effectIDFloat, ok1 := effectData["effect_id"].(float64)
 	baseValueFloat, ok2 := effectData["value"].(float64)
@@ -489,4 +496,5 @@ func (s *combatService) applyMpDamage(caster *domain.Combatant, target *domain.C
 		"target_mp_before", mpBefore,
 		"target_mp_after", mpAfter,
 	)
+	s._RecordDirectEvent(match, domain.CombatEventMPDamage, caster, target, 1104, actualMpLost, spell)
 }
@@ -2,8 +2,10 @@
 package combat
 
 import (
+	"bytes"
 	"encoding/json"
 	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
 )
 
 // ============================================================================
@@ -23,7 +25,7 @@ import (
 // - effect_synergy.go  : Stance effects (3000s)
 // ============================================================================
 
-func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant) {
+func (s *combatService) processEffectTicksAndExpiry(match *domain.CombatMatch, combatant *domain.Combatant) {
 	// 1. ตรวจสอบว่ามี ActiveEffects หรือไม่
 	if combatant.ActiveEffects == nil {
 		s.appLogger.Debug("No active effects to process", "combatant_id", combatant.ID)
@@ -68,9 +70,11 @@ func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant)
 				if newHP > maxHP {
 					newHP = maxHP
 				} // กันเลือดเกิน
+				hpBefore := combatant.CurrentHP
 				if newHP != combatant.CurrentHP { // อัปเดตและ Log เฉพาะเมื่อมีการเปลี่ยนแปลง
 					combatant.CurrentHP = newHP
 					s.appLogger.Info("Applied HP_REGEN tick", "combatant_id", combatant.ID, "heal", healAmount, "new_hp", combatant.CurrentHP)
+					s._RecordTickEvent(match, combatant, currentEffect, newHP-hpBefore)
 					somethingChanged = true
 				}
 			}
@@ -81,9 +85,11 @@ func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant)
 				if newMP > maxMP {
 					newMP = maxMP
 				} // กัน MP เกิน
+				mpBefore := combatant.CurrentMP
 				if newMP != combatant.CurrentMP { // อัปเดตและ Log เฉพาะเมื่อมีการเปลี่ยนแปลง
 					combatant.CurrentMP = newMP
 					s.appLogger.Info("Applied MP_REGEN tick", "combatant_id", combatant.ID, "regen", regenAmount, "new_mp", combatant.CurrentMP)
+					s._RecordTickEvent(match, combatant, currentEffect, newMP-mpBefore)
 					somethingChanged = true
 				}
 			}
@@ -94,9 +100,11 @@ func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant)
 				if newHP < 0 {
 					newHP = 0
 				} // กันเลือดติดลบ
+				hpBefore := combatant.CurrentHP
 				if newHP != combatant.CurrentHP { // อัปเดตและ Log เฉพาะเมื่อมีการเปลี่ยนแปลง
 					combatant.CurrentHP = newHP
 					s.appLogger.Info("Applied IGNITE DoT tick", "combatant_id", combatant.ID, "damage", dotAmount, "new_hp", combatant.CurrentHP) // ⭐️ แก้ Log!
+					s._RecordTickEvent(match, combatant, currentEffect, hpBefore-newHP)
 					somethingChanged = true
 				}
 			}
@@ -117,6 +125,10 @@ func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant)
 		} else {
 			// Effect หมดอายุแล้ว Log บอก และตั้ง Flag ว่ามีการเปลี่ยนแปลง
 			s.appLogger.Info("Effect has expired", "combatant_id", combatant.ID, "effect_id", currentEffect.EffectID, "value_at_expiry", currentEffect.Value)
+			expiredEvent := newCombatEvent(domain.CombatEventEffectExpired, nil, combatant, currentEffect.Value)
+			expiredEvent.SourceID = combatantIDPtr(currentEffect.SourceID)
+			expiredEvent.EffectID = &currentEffect.EffectID
+			s.recordEvent(match, expiredEvent)
 			somethingChanged = true
 		}
 		// --- สิ้นสุดเช็คหมดอายุ ---
@@ -139,10 +151,12 @@ func (s *combatService) processEffectTicksAndExpiry(combatant *domain.Combatant)
 	}
 }
 
+// recalculateStats คำนวณ Initiative ใหม่จากค่าพื้นฐาน + DEBUFF_SLOW (ใช้จัดคิวเทิร์นใน endTurn)
+// ตัวละครใช้สูตรเดียวกับตอนสร้าง match (character.CalculateDerivedStats)
 func (s *combatService) recalculateStats(combatant *domain.Combatant) {
 	var baseInitiative int
 	if combatant.CharacterID != nil && combatant.Character != nil { // ⭐️ เพิ่ม nil check
-		baseInitiative = 50 + combatant.Character.TalentG
+		baseInitiative = character.CalculateDerivedStats(combatant.Character, s.gameDataRepo).Initiative
 	} else if combatant.EnemyID != nil && combatant.Enemy != nil { // ⭐️ เพิ่ม nil check
 		baseInitiative = combatant.Enemy.Initiative
 	}
@@ -167,6 +181,9 @@ func (s *combatService) getMaxHP(combatant *domain.Combatant) int {
 		// เราใช้ current_hp จาก character data เป็น MaxHP (ตามที่เราทำใน turn_manager)
 		return combatant.Character.CurrentHP
 	} else if combatant.EnemyID != nil && combatant.Enemy != nil {
+		if combatant.MaxHP > 0 {
+			return combatant.MaxHP // ปรับตามเลเวลของด่านแล้ว
+		}
 		return combatant.Enemy.MaxHP
 	}
 	s.appLogger.Warn("Could not determine MaxHP for combatant", "id", combatant.ID)
@@ -194,20 +211,29 @@ func (s *combatService) getMaxMP(combatant *domain.Combatant) int {
 // This is the central hub that delegates work to specific effect handlers
 // ============================================================================
 
-func (s *combatService) applyEffect(caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
+func (s *combatService) applyEffect(match *domain.CombatMatch, caster *domain.Combatant, target *domain.Combatant, effectData map[string]interface{}, spell *domain.Spell) {
 	effectID := uint(effectData["effect_id"].(float64))
 
+	// Direct effects (1000s) บันทึก event เองจากผลลัพธ์จริง
+	// ส่วน buff/debuff/stance บันทึกเป็น EFFECT_APPLIED เมื่อ ActiveEffects ของเป้าหมายเปลี่ยนจริง
+	effectsBefore := target.ActiveEffects
+	defer func() {
+		if effectID >= 2000 && !bytes.Equal(effectsBefore, target.ActiveEffects) {
+			s._RecordLegacyEffectApplied(match, caster, target, effectID, spell)
+		}
+	}()
+
 	// Route to appropriate specialist based on new 1000-based ID structure
 	switch effectID {
 	// --- Direct Effects (1000s) ---
 	case 1101: // DAMAGE
-		s.applyDamage(caster, target, effectData, spell)
+		s.applyDamage(match, caster, target, effectData, spell)
 	case 1102: // SHIELD
-		s.applyShield(caster, target, effectData, spell)
+		s.applyShield(match, caster, target, effectData, spell)
 	case 1103: // HEAL
-		s.applyHeal(caster, target, effectData, spell)
+		s.applyHeal(match, caster, target, effectData, spell)
 	case 1104: // MP_DAMAGE
-		s.applyMpDamage(caster, target, effectData, spell)
+		s.applyMpDamage(match, caster, target, effectData, spell)
 
 	// --- Buffs (2000s) ---
 	case 2101: // BUFF_HP_REGEN
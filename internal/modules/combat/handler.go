@@ -8,8 +8,10 @@ import (
 	"sage-of-elements-backend/pkg/applogger"
 	"sage-of-elements-backend/pkg/appresponse"
 	"sage-of-elements-backend/pkg/appvalidator"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -26,9 +28,12 @@ type TrainingEnemyInput struct {
 type CreateMatchRequest struct {
 	CharacterID     uint                   `json:"character_id" validate:"required"`
 	MatchType       string                 `json:"match_type" validate:"required,oneof=TRAINING STORY PVP"`
-	StageID         *uint                  `json:"stage_id,omitempty"`         // Required for STORY
-	OpponentID      *uint                  `json:"opponent_id,omitempty"`      // Required for PVP
-	TrainingEnemies []TrainingEnemyInput   `json:"training_enemies,omitempty"` // Required for TRAINING
+	StageID         *uint                  `json:"stage_id,omitempty"`                           // Required for STORY
+	OpponentID      *uint                  `json:"opponent_id,omitempty"`                        // Required for PVP
+	Live            bool                   `json:"-"`                                            // PVP: true = ดวลสด (ตั้งได้จาก CreateRankedMatch เท่านั้น เพราะทั้งสองฝ่ายเข้าคิวเอง)
+	TrainingEnemies []TrainingEnemyInput   `json:"training_enemies,omitempty"`                   // Required for TRAINING
+	AllyCharacters  []uint                 `json:"ally_characters,omitempty" validate:"max=2"`   // co-op: ตัวละครของผู้เล่นอื่นที่เข้าทีมเดียวกับผู้ท้าชิง (AI เล่นแทนเสมอ)
+	AllyEnemies     []TrainingEnemyInput   `json:"ally_enemies,omitempty" validate:"max=2,dive"` // NPC พันธมิตร (ใช้ข้อมูลศัตรู + กฎ AI ของมัน)
 	DeckID          *uint                  `json:"deck_id,omitempty"`
 	Deck            []DeckSlotInput        `json:"deck,omitempty" validate:"max=8,dive"`
 	Modifiers       *domain.MatchModifiers `json:"modifiers,omitempty"`
@@ -42,8 +47,10 @@ type PerformActionRequest struct {
 }
 
 type PerformActionResponse struct {
-	UpdatedMatch    *domain.CombatMatch  `json:"updatedMatch"`
-	PerformedAction PerformActionRequest `json:"performedAction"`
+	UpdatedMatch    *domain.CombatMatch   `json:"updatedMatch"`
+	PerformedAction PerformActionRequest  `json:"performedAction"`
+	Events          []*domain.CombatEvent `json:"events"`         // ⭐️ เหตุการณ์ทั้งหมดใน action นี้ (เรียงตาม sequence)
+	Loot            []*domain.LootDrop    `json:"loot,omitempty"` // ของดรอปจากศัตรูที่เข้าคลังแล้ว (เฉพาะ action ที่ทำให้ชนะ)
 }
 
 // --- DTO สำหรับ ResolveSpell (Endpoint แยก) ---
@@ -67,20 +74,28 @@ type CombatHandler struct {
 	appLogger applogger.Logger
 	validator *validator.Validate
 	service   CombatService
+	liveHub   *LiveHub
 }
 
-func NewCombatHandler(appLogger applogger.Logger, validator *validator.Validate, service CombatService) *CombatHandler {
+func NewCombatHandler(appLogger applogger.Logger, validator *validator.Validate, service CombatService, liveHub *LiveHub) *CombatHandler {
 	return &CombatHandler{
 		appLogger: appLogger,
 		validator: validator,
 		service:   service,
+		liveHub:   liveHub,
 	}
 }
 
 func (h *CombatHandler) RegisterProtectedRoutes(router fiber.Router) {
 	router.Post("/", h.CreateMatch)
+	router.Get("/active", h.GetActiveMatch)        // 🔁 match ที่ตัวละครกำลังเล่นอยู่ (resume หลังหลุด)
+	router.Post("/replays/verify", h.VerifyReplay) // 🎞️ Import replay แล้ว re-simulate
+	router.Get("/:id/replay", h.GetMatchReplay)    // 🎞️ Export replay ของ match ที่จบแล้ว
 	router.Post("/:id/actions", h.PerformAction)
-	router.Get("/resolve-spell", h.ResolveSpell) // ⭐️ GET Endpoint สำหรับ ResolveSpell
+	router.Post("/:id/forfeit", h.ForfeitMatch)                           // 🏳️ ยอมแพ้ (นับเป็นแพ้ ต่างจากระบบ abort)
+	router.Get("/:id/ws", h.UpgradeLiveMatch, websocket.New(h.LiveMatch)) // ⚔️ PvP สดผ่าน WebSocket
+	router.Get("/resolve-spell", h.ResolveSpell)                          // ⭐️ GET Endpoint สำหรับ ResolveSpell
+	router.Get("/:id", h.GetMatch)                                        // ต้องอยู่ท้ายสุด ไม่งั้นจะทับ /active, /resolve-spell
 }
 
 // --- Handler Functions ---
@@ -129,6 +144,44 @@ func (h *CombatHandler) PerformAction(c *fiber.Ctx) error {
 	return appresponse.Success(c, fiber.StatusOK, "Action performed successfully", actionResponse, nil)
 }
 
+func (h *CombatHandler) GetActiveMatch(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	charIDStr := c.Query("character_id")
+	if charIDStr == "" {
+		return apperrors.InvalidFormatError("Missing character_id query parameter", nil)
+	}
+	charID, err := strconv.ParseUint(charIDStr, 10, 32)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid character_id format", nil)
+	}
+
+	match, err := h.service.GetActiveMatch(claims.UserID, uint(charID))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Active match retrieved successfully", match, nil)
+}
+
+func (h *CombatHandler) GetMatch(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	match, err := h.service.GetMatch(claims.UserID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Match retrieved successfully", match, nil)
+}
+
+func (h *CombatHandler) ForfeitMatch(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+
+	match, err := h.service.ForfeitMatch(claims.UserID, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Match forfeited", match, nil)
+}
+
 // ✨⭐️ Handler สำหรับ ResolveSpell (GET Endpoint) ⭐️✨
 func (h *CombatHandler) ResolveSpell(c *fiber.Ctx) error {
 	claims := c.Locals("user_claims").(*appauth.Claims)
@@ -190,3 +243,31 @@ func (h *CombatHandler) ResolveSpell(c *fiber.Ctx) error {
 
 	return appresponse.Success(c, fiber.StatusOK, "Spell resolved successfully", response, nil)
 }
+
+// 🎞️ Handler สำหรับ Export Replay
+func (h *CombatHandler) GetMatchReplay(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	matchID := c.Params("id")
+
+	replay, err := h.service.GetMatchReplay(claims.UserID, matchID)
+	if err != nil {
+		return err
+	}
+
+	return appresponse.Success(c, fiber.StatusOK, "Replay retrieved successfully", replay, nil)
+}
+
+// 🎞️ Handler สำหรับ Import Replay แล้ว Re-simulate เพื่อตรวจสอบ
+func (h *CombatHandler) VerifyReplay(c *fiber.Ctx) error {
+	replay := new(MatchReplay)
+	if err := c.BodyParser(replay); err != nil {
+		return apperrors.InvalidFormatError("Cannot parse replay JSON", nil)
+	}
+
+	result, err := h.service.VerifyReplay(replay)
+	if err != nil {
+		return err
+	}
+
+	return appresponse.Success(c, fiber.StatusOK, "Replay verified", result, nil)
+}
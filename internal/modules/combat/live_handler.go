@@ -0,0 +1,98 @@
+// file: internal/modules/combat/live_handler.go
+package combat
+
+import (
+	"encoding/json"
+	"errors"
+	"sage-of-elements-backend/pkg/appauth"
+	"sage-of-elements-backend/pkg/apperrors"
+	"sage-of-elements-backend/pkg/appvalidator"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== Live Match Handler (WebSocket) ====================
+// GET /combat/:id/ws (JWT เดียวกับ REST, ส่งผ่าน ?access_token= ได้เพราะ browser ตั้ง header เองไม่ได้)
+// - client ส่ง PerformActionRequest เป็น JSON ในเทิร์นของตัวเอง
+// - ผลลัพธ์ถูก broadcast (MATCH_UPDATED) ให้ทั้งสองฝั่งจาก service, error ส่งกลับเฉพาะผู้ส่ง
+// - ถ้าผู้เล่นหลุดทุกการเชื่อมต่อระหว่าง match → มีเวลา LIVE_RECONNECT_GRACE ให้ต่อกลับมา
+//   ไม่กลับมาทัน → scheduler (ProcessTimeouts) ให้ยอมแพ้แทน (อีกฝ่ายชนะ)
+
+// UpgradeLiveMatch ตรวจสิทธิ์ก่อน upgrade (error ยังตอบเป็น HTTP ปกติได้)
+func (h *CombatHandler) UpgradeLiveMatch(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	if _, err := h.service.GetLiveMatch(claims.UserID, c.Params("id")); err != nil {
+		return err
+	}
+	return c.Next()
+}
+
+// LiveMatch คือ read loop ของการเชื่อมต่อ 1 เส้น
+func (h *CombatHandler) LiveMatch(conn *websocket.Conn) {
+	claims := conn.Locals("user_claims").(*appauth.Claims)
+	matchID := conn.Params("id")
+
+	client := h.liveHub.Join(matchID, claims.UserID, conn)
+	defer h.leaveLiveMatch(matchID, client)
+
+	// 1. ส่งสถานะปัจจุบัน (โหลดหลัง Join เพื่อไม่พลาด update ที่เกิดระหว่าง upgrade, กลับมาจากการหลุด = ล้างเส้นตาย)
+	match, err := h.service.ReconnectLiveMatch(claims.UserID, matchID)
+	if err != nil {
+		h.sendLiveError(client, err)
+		return
+	}
+	if err := h.liveHub.Send(client, &LiveMessage{Type: LiveMessageMatchState, Data: match}); err != nil {
+		return
+	}
+
+	// 2. รับ action จนกว่าจะปิดการเชื่อมต่อ
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return // client ปิด หรือหลุด
+		}
+
+		req := new(PerformActionRequest)
+		if err := json.Unmarshal(raw, req); err != nil {
+			h.sendLiveError(client, apperrors.InvalidFormatError("Cannot parse JSON", nil))
+			continue
+		}
+		if validationResult := appvalidator.Validate(h.validator, req); !validationResult.IsValid {
+			h.sendLiveError(client, apperrors.ValidationError("Validation failed", validationResult.Errors))
+			continue
+		}
+
+		// สำเร็จ → service broadcast ให้ทุกคนใน match แล้ว (รวมผู้ส่ง)
+		if _, err := h.service.PerformAction(claims.UserID, matchID, *req); err != nil {
+			h.sendLiveError(client, err)
+		}
+	}
+}
+
+// leaveLiveMatch เอาการเชื่อมต่อออก และเริ่มนับเวลาให้กลับมาถ้าไม่เหลือการเชื่อมต่อใน match แล้ว
+func (h *CombatHandler) leaveLiveMatch(matchID string, client *LiveClient) {
+	if h.liveHub.Leave(matchID, client) {
+		return // ยังเปิดอยู่ในแท็บอื่น
+	}
+	if err := h.service.DisconnectLiveMatch(client.PlayerID, matchID); err != nil {
+		// match จบไปแล้วเป็นกรณีปกติ (ปิดการเชื่อมต่อหลังจบเกม)
+		h.appLogger.Debug("No reconnect deadline on disconnect", "match_id", matchID, "player_id", client.PlayerID, "reason", err.Error())
+		return
+	}
+	h.appLogger.Warn("Player disconnected from live match, waiting for reconnect", "match_id", matchID, "player_id", client.PlayerID)
+}
+
+func (h *CombatHandler) sendLiveError(client *LiveClient, err error) {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		h.appLogger.Error("Live match action failed", err, "player_id", client.PlayerID)
+		appErr = apperrors.SystemError("unexpected error")
+	}
+	if sendErr := h.liveHub.Send(client, &LiveMessage{Type: LiveMessageError, Data: appErr}); sendErr != nil {
+		h.appLogger.Warn("Failed to send live error", "player_id", client.PlayerID, "error", sendErr)
+	}
+}
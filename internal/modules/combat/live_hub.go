@@ -0,0 +1,222 @@
+// file: internal/modules/combat/live_hub.go
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/pkg/apperrors"
+	"sage-of-elements-backend/pkg/applogger"
+	"sync"
+	"time"
+)
+
+// ==================== Live Hub (PvP สด) ====================
+// ไฟล์นี้ดูแลการเชื่อมต่อ WebSocket ของแต่ละ match
+// - ผู้เล่นหลายคน (หรือหลายแท็บ) ต่อเข้า match เดียวกันได้
+// - ทุกครั้งที่ match ถูกบันทึก (action, forfeit, หมดเวลา, หลุด/กลับมาเชื่อมต่อ) service จะ Broadcast สถานะใหม่ให้ทุกคนใน match
+// - hub อยู่ในหน่วยความจำของ process เดียว (ยังไม่รองรับหลาย instance)
+
+// ประเภทข้อความที่ server ส่งให้ client
+const (
+	LiveMessageMatchState   = "MATCH_STATE"   // สถานะ match ตอนเชื่อมต่อสำเร็จ
+	LiveMessageMatchUpdated = "MATCH_UPDATED" // match เปลี่ยน (data = PerformActionResponse)
+	LiveMessageError        = "ERROR"         // action ของผู้ส่งถูกปฏิเสธ (ส่งให้ผู้ส่งคนเดียว)
+)
+
+// LiveMessage คือข้อความที่ส่งผ่าน WebSocket
+type LiveMessage struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// LiveConn คือการเชื่อมต่อที่ hub เขียนข้อความออกไปได้ (*websocket.Conn ใช้ได้ทันที)
+type LiveConn interface {
+	WriteJSON(v interface{}) error
+}
+
+// LiveClient คือผู้เล่น 1 การเชื่อมต่อใน match
+type LiveClient struct {
+	PlayerID uint
+	conn     LiveConn
+	writeMu  sync.Mutex // websocket เขียนพร้อมกันหลาย goroutine ไม่ได้
+}
+
+// LiveHub เก็บ client ของทุก match ที่เปิด WebSocket อยู่
+type LiveHub struct {
+	appLogger applogger.Logger
+	mu        sync.RWMutex
+	rooms     map[string]map[*LiveClient]struct{}
+}
+
+func NewLiveHub(appLogger applogger.Logger) *LiveHub {
+	return &LiveHub{
+		appLogger: appLogger,
+		rooms:     make(map[string]map[*LiveClient]struct{}),
+	}
+}
+
+// Join เพิ่มการเชื่อมต่อเข้า match
+func (h *LiveHub) Join(matchID string, playerID uint, conn LiveConn) *LiveClient {
+	client := &LiveClient{PlayerID: playerID, conn: conn}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[matchID] == nil {
+		h.rooms[matchID] = make(map[*LiveClient]struct{})
+	}
+	h.rooms[matchID][client] = struct{}{}
+	return client
+}
+
+// Leave เอาการเชื่อมต่อออกจาก match แล้วคืนว่าผู้เล่นคนนี้ยังมีการเชื่อมต่ออื่นค้างอยู่หรือไม่
+func (h *LiveHub) Leave(matchID string, client *LiveClient) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.rooms[matchID]
+	delete(room, client)
+	if len(room) == 0 {
+		delete(h.rooms, matchID)
+		return false
+	}
+	for other := range room {
+		if other.PlayerID == client.PlayerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Send ส่งข้อความให้ client คนเดียว
+func (h *LiveHub) Send(client *LiveClient, msg *LiveMessage) error {
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+	return client.conn.WriteJSON(msg)
+}
+
+// Broadcast ส่งข้อความให้ทุกคนใน match (hub เป็น nil ได้ เช่น simulator ที่ไม่มี WebSocket)
+func (h *LiveHub) Broadcast(matchID string, msg *LiveMessage) {
+	if h == nil {
+		return
+	}
+
+	h.mu.RLock()
+	clients := make([]*LiveClient, 0, len(h.rooms[matchID]))
+	for client := range h.rooms[matchID] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		if err := h.Send(client, msg); err != nil {
+			// การเชื่อมต่อที่ตายแล้วจะถูกเอาออกตอน read loop ของมันจบ
+			h.appLogger.Warn("Failed to push live message", "match_id", matchID, "player_id", client.PlayerID, "error", err)
+		}
+	}
+}
+
+// _PublishMatchUpdate แจ้งทุกคนที่ดู match นี้อยู่หลัง match ถูกบันทึก
+func (s *combatService) _PublishMatchUpdate(match *domain.CombatMatch, action PerformActionRequest, events []*domain.CombatEvent) {
+	s.liveHub.Broadcast(match.ID.String(), &LiveMessage{
+		Type: LiveMessageMatchUpdated,
+		Data: &PerformActionResponse{
+			UpdatedMatch:    match,
+			PerformedAction: action,
+			Events:          events,
+		},
+	})
+}
+
+// GetLiveMatch ตรวจว่าผู้เล่นเปิด WebSocket ของ match นี้ได้: ต้องเป็น PvP สดที่ยังไม่จบ และผู้เล่นคุมตัวละครใน match
+func (s *combatService) GetLiveMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	match, err := s.combatRepo.FindMatchByID(matchID)
+	if err != nil {
+		return nil, apperrors.NotFoundError("match not found")
+	}
+	if s.findControlledCombatant(match, playerID) == nil {
+		return nil, apperrors.PermissionDeniedError("you are not part of this match")
+	}
+	if !isLiveDuel(match) {
+		return nil, apperrors.New(400, "NOT_A_LIVE_MATCH", "only live PvP matches can be joined over WebSocket")
+	}
+	if match.Status != domain.MatchInProgress {
+		return nil, apperrors.New(400, "MATCH_FINISHED", "this match has already finished")
+	}
+	return match, nil
+}
+
+// ReconnectLiveMatch ตรวจสิทธิ์เหมือน GetLiveMatch และล้างเส้นตายหลุดการเชื่อมต่อของผู้เล่น (ถ้ามี)
+func (s *combatService) ReconnectLiveMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	var match *domain.CombatMatch
+	err := retryOnMatchConflict(func() (err error) {
+		match, err = s._ReconnectLiveMatch(playerID, matchID)
+		return err
+	})
+	return match, err
+}
+
+func (s *combatService) _ReconnectLiveMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	match, err := s.GetLiveMatch(playerID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	actor := s.findControlledCombatant(match, playerID)
+	if actor.DisconnectDeadline == nil {
+		return match, nil
+	}
+
+	actor.DisconnectDeadline = nil
+	s.recordEvent(match, newCombatEvent(domain.CombatEventReconnected, actor, nil, 0))
+
+	events := match.PendingEvents
+	updatedMatch, err := s.combatRepo.UpdateMatch(match)
+	if err != nil {
+		return nil, err
+	}
+	s._PublishMatchUpdate(updatedMatch, PerformActionRequest{}, events)
+
+	s.appLogger.Info("Player reconnected to live match", "match_id", matchID, "player_id", playerID)
+	return updatedMatch, nil
+}
+
+// DisconnectLiveMatch ตั้งเส้นตายให้ผู้เล่นที่หลุดทุกการเชื่อมต่อกลับมา (LIVE_RECONNECT_GRACE)
+// ไม่กลับมาทันเวลา → ProcessTimeouts ให้ยอมแพ้แทน, เทิร์นที่ค้างระหว่างนั้นยังถูก END_TURN ตาม turn timer ตามปกติ
+func (s *combatService) DisconnectLiveMatch(playerID uint, matchID string) error {
+	return retryOnMatchConflict(func() error {
+		return s._DisconnectLiveMatch(playerID, matchID)
+	})
+}
+
+func (s *combatService) _DisconnectLiveMatch(playerID uint, matchID string) error {
+	match, err := s.GetLiveMatch(playerID, matchID)
+	if err != nil {
+		return err
+	}
+	actor := s.findControlledCombatant(match, playerID)
+	if actor.DisconnectDeadline != nil {
+		return nil // หลุดซ้ำก่อนกลับมา → นับเวลาต่อจากเดิม
+	}
+
+	deadline := time.Now().Add(s._GetConfigSeconds("LIVE_RECONNECT_GRACE", 60))
+	actor.DisconnectDeadline = &deadline
+	disconnectEvent := newCombatEvent(domain.CombatEventDisconnected, actor, nil, 0)
+	disconnectEvent.Details = eventDetails(map[string]interface{}{"reconnect_deadline": deadline})
+	s.recordEvent(match, disconnectEvent)
+
+	events := match.PendingEvents
+	updatedMatch, err := s.combatRepo.UpdateMatch(match)
+	if err != nil {
+		return err
+	}
+	s._PublishMatchUpdate(updatedMatch, PerformActionRequest{}, events)
+	return nil
+}
+
+// findDisconnectedCombatant หาผู้เล่นที่หลุดการเชื่อมต่อเกินเส้นตายแล้ว
+func (s *combatService) findDisconnectedCombatant(match *domain.CombatMatch, now time.Time) *domain.Combatant {
+	for _, c := range match.Combatants {
+		if isHumanControlled(c) && isDeadlinePassed(c.DisconnectDeadline, now) {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// file: internal/modules/combat/loot.go
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sort"
+)
+
+// ==================== Enemy Loot ====================
+// ไฟล์นี้สุ่มของดรอปจากตาราง loot ของศัตรูเมื่อผู้เล่นชนะ
+// - สุ่มผ่าน random stream ของ match → replay ได้ผลเดิมเสมอ
+// - ของที่ได้ถูกเก็บใน match.PendingLoot แล้ว repository เพิ่มเข้าคลังใน transaction เดียวกับ UpdateMatch
+//   (replay ไม่เรียก UpdateMatch จึงไม่เพิ่มของซ้ำ)
+
+// _RollEnemyLoot สุ่มของดรอปจากศัตรูทุกตัวที่ถูกกำจัด แล้วมอบให้ผู้ชนะ
+func (s *combatService) _RollEnemyLoot(match *domain.CombatMatch, winner *domain.Combatant) {
+	if winner == nil || winner.CharacterID == nil {
+		return
+	}
+
+	items := make(map[uint]int)
+	for _, enemy := range s.findEnemyCombatants(match) {
+		if enemy.Enemy == nil || enemy.CurrentHP > 0 {
+			continue
+		}
+
+		// เรียงตาม ID เพื่อให้ลำดับการสุ่มคงที่ไม่ว่าจะโหลดมาจากที่ไหน
+		loots := append([]*domain.EnemyLoot(nil), enemy.Enemy.Loots...)
+		sort.Slice(loots, func(i, j int) bool { return loots[i].ID < loots[j].ID })
+
+		for _, loot := range loots {
+			if loot.DropChance <= 0 || s.rollFloat64(match) >= loot.DropChance {
+				continue
+			}
+			amount := loot.MinAmount
+			if loot.MaxAmount > loot.MinAmount {
+				amount += s.rollIntn(match, loot.MaxAmount-loot.MinAmount+1)
+			}
+			if amount <= 0 {
+				continue
+			}
+
+			event := newCombatEvent(domain.CombatEventLootDropped, enemy, winner, amount)
+			event.Details = eventDetails(map[string]interface{}{"element_id": loot.ElementID})
+			s.recordEvent(match, event)
+			items[loot.ElementID] += amount
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	elementIDs := make([]uint, 0, len(items))
+	for elementID := range items {
+		elementIDs = append(elementIDs, elementID)
+	}
+	sort.Slice(elementIDs, func(i, j int) bool { return elementIDs[i] < elementIDs[j] })
+	for _, elementID := range elementIDs {
+		match.PendingLoot = append(match.PendingLoot, &domain.LootDrop{
+			CharacterID: *winner.CharacterID,
+			ElementID:   elementID,
+			Quantity:    items[elementID],
+		})
+	}
+	s._AddResultLoot(match, *winner.CharacterID, items)
+
+	s.appLogger.Info("Enemy loot dropped",
+		"match_id", match.ID,
+		"character_id", *winner.CharacterID,
+		"items", items,
+	)
+}
@@ -0,0 +1,134 @@
+// file: internal/modules/combat/mastery_progress.go
+package combat
+
+import (
+	"encoding/json"
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sort"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== Mastery Progress ====================
+// ไฟล์นี้ให้ MXP กับศาสตร์ (Mastery) ของเวทที่ผู้เล่นร่ายสำเร็จ
+// - ตอนร่าย: ExecuteSpellCast คิด MXP แล้วเก็บใน details ของ CAST event ("mastery_id", "mxp")
+// - ตอนจบ match: รวม MXP จาก CAST event ทั้ง match แล้วบันทึก Level/MXP ลงตัวละครครั้งเดียว
+//   (เขียนพร้อมการจบ match ใน UpdateMatch, match ค้างที่ถูก abort จะไม่ได้ MXP, replay ไม่แตะตัวละครจริง)
+// MXP ต่อครั้ง = MXP_PER_CAST × MXP_CAST_MODE_<MODE>_MOD × MXP_<MATCH_TYPE>_MATCH_MOD
+
+// castMxpDetails คือส่วนของ CAST event details ที่ใช้รวม MXP
+type castMxpDetails struct {
+	MasteryID uint `json:"mastery_id"`
+	Mxp       int  `json:"mxp"`
+}
+
+// _CalculateCastMxp คิด MXP ของการร่าย 1 ครั้ง (0 ถ้าผู้ร่ายไม่ใช่ตัวละครที่ผู้เล่นควบคุม)
+func (s *combatService) _CalculateCastMxp(match *domain.CombatMatch, caster *domain.Combatant, castingMode string) int {
+	if caster.CharacterID == nil || !isHumanControlled(caster) {
+		return 0
+	}
+	base := s._GetConfigFloat("MXP_PER_CAST", 0)
+	if base <= 0 {
+		return 0
+	}
+
+	modeMod := 1.0
+	switch castingMode {
+	case "CHARGE", "OVERCHARGE":
+		modeMod = s._GetConfigFloat("MXP_CAST_MODE_"+castingMode+"_MOD", 1.0)
+	}
+	matchMod := s._GetConfigFloat("MXP_"+string(match.MatchType)+"_MATCH_MOD", 1.0)
+
+	return int(math.Round(base * modeMod * matchMod))
+}
+
+// _GrantMasteryExp รวม MXP จากการร่ายทั้ง match แล้วบันทึกเข้าตัวละครแต่ละตัว (เรียกหลังให้รางวัลอื่นครบแล้ว)
+func (s *combatService) _GrantMasteryExp(match *domain.CombatMatch) {
+	gains := make(map[uuid.UUID]map[uint]int)
+	for _, event := range s._MatchEvents(match) {
+		if event.Type != domain.CombatEventCast || event.SourceID == nil || len(event.Details) == 0 {
+			continue
+		}
+		var details castMxpDetails
+		if err := json.Unmarshal(event.Details, &details); err != nil || details.Mxp <= 0 || details.MasteryID == 0 {
+			continue
+		}
+		if gains[*event.SourceID] == nil {
+			gains[*event.SourceID] = make(map[uint]int)
+		}
+		gains[*event.SourceID][details.MasteryID] += details.Mxp
+	}
+
+	cfg := character.LoadMasteryProgressionConfig(s.gameDataRepo)
+	for _, combatant := range match.Combatants {
+		if combatant.CharacterID == nil || len(gains[combatant.ID]) == 0 {
+			continue
+		}
+		s._ApplyMasteryGains(match, combatant, gains[combatant.ID], cfg)
+	}
+}
+
+// _ApplyMasteryGains เก็บ MXP ของตัวละคร 1 ตัวไว้ใน match.PendingMasteries (สร้างแถวศาสตร์ใหม่ถ้ายังไม่เคยมี)
+func (s *combatService) _ApplyMasteryGains(
+	match *domain.CombatMatch,
+	combatant *domain.Combatant,
+	gains map[uint]int,
+	cfg character.MasteryProgressionConfig,
+) {
+	characterID := *combatant.CharacterID
+	char, err := s.characterRepo.FindByID(characterID)
+	if err != nil || char == nil {
+		return
+	}
+
+	masteryIDs := make([]uint, 0, len(gains))
+	for masteryID := range gains {
+		masteryIDs = append(masteryIDs, masteryID)
+	}
+	sort.Slice(masteryIDs, func(i, j int) bool { return masteryIDs[i] < masteryIDs[j] })
+
+	updated := make([]*domain.CharacterMastery, 0, len(masteryIDs))
+	var levelUpEvents []*domain.CombatEvent
+	totalMxp := 0
+	for _, masteryID := range masteryIDs {
+		var mastery *domain.CharacterMastery
+		for _, m := range char.Masteries {
+			if m.MasteryID == masteryID {
+				mastery = m
+				break
+			}
+		}
+		if mastery == nil {
+			mastery = &domain.CharacterMastery{CharacterID: characterID, MasteryID: masteryID, Level: 1}
+		}
+
+		levelBefore := mastery.Level
+		if levelsGained := character.ApplyMasteryExp(mastery, gains[masteryID], cfg); levelsGained > 0 {
+			event := newCombatEvent(domain.CombatEventMasteryLevelUp, combatant, nil, mastery.Level)
+			event.Details = eventDetails(map[string]interface{}{
+				"mastery_id":        masteryID,
+				"level_before":      levelBefore,
+				"level_after":       mastery.Level,
+				"mxp_to_next_level": cfg.MxpToNextLevel(mastery.Level),
+			})
+			levelUpEvents = append(levelUpEvents, event)
+		}
+		updated = append(updated, mastery)
+		totalMxp += gains[masteryID]
+	}
+
+	match.PendingMasteries = append(match.PendingMasteries, updated...)
+	for _, event := range levelUpEvents {
+		s.recordEvent(match, event)
+	}
+	if result := matchResultFor(match, characterID); result != nil {
+		result.MxpGained += totalMxp
+	}
+	s.appLogger.Info("granted mastery exp",
+		"character_id", characterID,
+		"match_id", match.ID,
+		"mxp", gains,
+	)
+}
@@ -0,0 +1,161 @@
+// file: internal/modules/combat/match_result.go
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"sort"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== Match Result ====================
+// ไฟล์นี้สร้างสรุปผลของ match ที่จบแล้ว (1 แถวต่อตัวละครใน match)
+// - สร้างตอนจบ match (_EndMatch / _EndMatchByTimeout) แล้วเก็บไว้ใน match.PendingResults
+// - repository บันทึกพร้อม UpdateMatch ครั้งสุดท้าย (replay ไม่เรียก UpdateMatch จึงไม่เขียนซ้ำ)
+// - สถิติในสนามนับจาก Combat Event ทั้ง match, รางวัลถูกเติมโดย _GrantVictoryExp / _RecordStageClear
+
+// combatantStats คือสถิติของ combatant 1 ตัวที่นับจาก event
+type combatantStats struct {
+	turns          int
+	damageDealt    int
+	damageReceived int
+	healingDone    int
+	spellsCast     int
+}
+
+// _BuildMatchResults สร้างสรุปผลให้ทุกตัวละครใน match (ต้องเรียกก่อนแจกรางวัล)
+func (s *combatService) _BuildMatchResults(match *domain.CombatMatch, winnerSide domain.MatchSide, reason domain.MatchEndReason) {
+	stats := s._CollectCombatantStats(match)
+
+	finishedAt := match.CreatedAt
+	if match.FinishedAt != nil {
+		finishedAt = *match.FinishedAt
+	}
+	duration := int(finishedAt.Sub(match.CreatedAt).Seconds())
+	if duration < 0 {
+		duration = 0
+	}
+
+	match.PendingResults = nil
+	for _, c := range match.Combatants {
+		if c.CharacterID == nil {
+			continue
+		}
+		side := combatantTeam(c)
+		outcome := domain.MatchOutcomeLose
+		if side == winnerSide {
+			outcome = domain.MatchOutcomeWin
+		}
+
+		stat := stats[c.ID]
+		match.PendingResults = append(match.PendingResults, &domain.MatchResult{
+			MatchID:         match.ID,
+			CharacterID:     *c.CharacterID,
+			MatchType:       match.MatchType,
+			StageID:         match.StageID,
+			Ranked:          match.Ranked,
+			Outcome:         outcome,
+			WinnerSide:      winnerSide,
+			EndReason:       reason,
+			TurnsTaken:      stat.turns,
+			DamageDealt:     stat.damageDealt,
+			DamageReceived:  stat.damageReceived,
+			HealingDone:     stat.healingDone,
+			SpellsCast:      stat.spellsCast,
+			DurationSeconds: duration,
+			StartedAt:       match.CreatedAt,
+			FinishedAt:      finishedAt,
+		})
+	}
+}
+
+// _CollectCombatantStats นับสถิติจาก event ที่บันทึกแล้ว + event ที่ยังค้างใน request นี้
+func (s *combatService) _CollectCombatantStats(match *domain.CombatMatch) map[uuid.UUID]*combatantStats {
+	stats := make(map[uuid.UUID]*combatantStats, len(match.Combatants))
+	for _, c := range match.Combatants {
+		stats[c.ID] = &combatantStats{}
+	}
+
+	for _, event := range s._MatchEvents(match) {
+		var source, target *combatantStats
+		if event.SourceID != nil {
+			source = stats[*event.SourceID]
+		}
+		if event.TargetID != nil {
+			target = stats[*event.TargetID]
+		}
+
+		switch event.Type {
+		case domain.CombatEventTurnStarted:
+			if source != nil {
+				source.turns++
+			}
+		case domain.CombatEventCast:
+			if source != nil {
+				source.spellsCast++
+			}
+		case domain.CombatEventDamage, domain.CombatEventRetaliation:
+			if source != nil {
+				source.damageDealt += event.Value
+			}
+			if target != nil {
+				target.damageReceived += event.Value
+			}
+		case domain.CombatEventHeal:
+			if source != nil {
+				source.healingDone += event.Value
+			}
+		}
+	}
+	return stats
+}
+
+// _MatchEvents คือ event ทั้ง match: ที่บันทึกแล้ว + ที่ยังค้างใน request นี้ (เรียงตาม sequence)
+func (s *combatService) _MatchEvents(match *domain.CombatMatch) []*domain.CombatEvent {
+	events, err := s.combatRepo.FindEventsByMatchID(match.ID.String())
+	if err != nil {
+		s.appLogger.Error("Failed to load match events", err, "match_id", match.ID)
+	}
+	return append(events, match.PendingEvents...)
+}
+
+// matchResultFor หาสรุปผลของตัวละครใน match ที่กำลังจบ (nil ถ้าไม่มี)
+func matchResultFor(match *domain.CombatMatch, characterID uint) *domain.MatchResult {
+	for _, result := range match.PendingResults {
+		if result.CharacterID == characterID {
+			return result
+		}
+	}
+	return nil
+}
+
+// _AddResultLoot เพิ่มของที่เข้าคลังลงในสรุปผล (รวมจำนวนของธาตุเดียวกัน)
+func (s *combatService) _AddResultLoot(match *domain.CombatMatch, characterID uint, items map[uint]int) {
+	result := matchResultFor(match, characterID)
+	if result == nil || len(items) == 0 {
+		return
+	}
+
+	var loot []domain.StageRewardElement
+	if len(result.Loot) > 0 {
+		if err := json.Unmarshal(result.Loot, &loot); err != nil {
+			s.appLogger.Warn("Invalid match result loot, resetting", "match_id", match.ID, "error", err)
+			loot = nil
+		}
+	}
+	merged := make(map[uint]int, len(loot)+len(items))
+	for _, item := range loot {
+		merged[item.ElementID] += item.Quantity
+	}
+	for elementID, quantity := range items {
+		merged[elementID] += quantity
+	}
+
+	loot = loot[:0]
+	for elementID, quantity := range merged {
+		loot = append(loot, domain.StageRewardElement{ElementID: elementID, Quantity: quantity})
+	}
+	sort.Slice(loot, func(i, j int) bool { return loot[i].ElementID < loot[j].ElementID })
+	result.Loot = eventDetails(loot)
+}
@@ -0,0 +1,127 @@
+// file: internal/modules/combat/match_teams.go
+package combat
+
+import (
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/pkg/apperrors"
+
+	"github.com/gofrs/uuid"
+)
+
+// ==================== Teams ====================
+// ไฟล์นี้สร้างพันธมิตรฝั่งผู้ท้าชิง (Combatant.Team = PLAYER) สำหรับการต่อสู้แบบ 2v2 / 3v3
+// - co-op (AllyCharacters): ตัวละครของผู้เล่นอื่น ใช้ Defense Deck ของเจ้าของ และ AI เล่นแทนเสมอแบบเดียวกับ PvP async
+//   (ผู้ท้าชิงลากตัวละครคนอื่นเข้า match ได้โดยเจ้าของไม่ได้ตกลง จึงไม่ให้ผูกเทิร์นหรือ match ของเจ้าของ)
+// - NPC (AllyEnemies): ใช้ข้อมูลศัตรู + กฎ AI ของมันตามปกติ แต่เล็งฝั่ง ENEMY แทน
+// - ฝั่งผู้ท้าชิงมีได้ไม่เกิน COMBAT_MAX_TEAM_SIZE ตัว (รวมตัวผู้ท้าชิงเอง)
+// ลำดับเทิร์นของทุกทีมมาจากคิว Initiative ใน endTurn และ match จบเมื่อทีมใดทีมหนึ่งไม่เหลือใครสู้ได้
+
+// _CreateAllyCombatants สร้าง combatant ของพันธมิตรทั้งหมดตาม request (เรียงตาม request: co-op ก่อน NPC)
+func (s *combatService) _CreateAllyCombatants(
+	playerID uint,
+	req CreateMatchRequest,
+	snapshotCharacters map[uint]*domain.Character,
+	snapshotEnemies map[uint]*domain.Enemy,
+) ([]*domain.Combatant, error) {
+	allyCount := len(req.AllyCharacters) + len(req.AllyEnemies)
+	if allyCount == 0 {
+		return nil, nil
+	}
+	if maxTeamSize := int(s._GetConfigFloat("COMBAT_MAX_TEAM_SIZE", 3)); 1+allyCount > maxTeamSize {
+		return nil, apperrors.New(422, "TEAM_TOO_LARGE",
+			fmt.Sprintf("a team can have at most %d combatants", maxTeamSize))
+	}
+
+	var allies []*domain.Combatant
+	for _, allyCharID := range req.AllyCharacters {
+		if snapshotCharacters[allyCharID] != nil {
+			return nil, apperrors.InvalidFormatError(fmt.Sprintf("character %d is already in this match", allyCharID), nil)
+		}
+		ally, allyChar, err := s._NewAllyCharacterCombatant(playerID, allyCharID)
+		if err != nil {
+			return nil, err
+		}
+		allies = append(allies, ally)
+		snapshotCharacters[allyCharID] = allyChar
+	}
+
+	for _, enemyInfo := range req.AllyEnemies {
+		enemyData, err := s.enemyRepo.FindByID(enemyInfo.EnemyID)
+		if err != nil || enemyData == nil {
+			return nil, apperrors.NotFoundError(fmt.Sprintf("enemy with id %d not found", enemyInfo.EnemyID))
+		}
+		allies = append(allies, s._NewEnemyCombatant(enemyData, nil, domain.MatchSidePlayer))
+		snapshotEnemies[enemyData.ID] = enemyData
+	}
+
+	s.appLogger.Info("Allies joined match",
+		"ally_characters", req.AllyCharacters,
+		"ally_enemies", len(req.AllyEnemies),
+	)
+	return allies, nil
+}
+
+// _NewAllyCharacterCombatant สร้าง combatant ของตัวละครผู้เล่นอื่นที่มาช่วยฝั่งผู้ท้าชิง โดยให้ AI เล่นแทน (คืนตัวละครไว้ทำ snapshot ด้วย)
+func (s *combatService) _NewAllyCharacterCombatant(playerID, allyCharID uint) (*domain.Combatant, *domain.Character, error) {
+	allyChar, err := s.characterRepo.FindByID(allyCharID)
+	if err != nil || allyChar == nil {
+		return nil, nil, apperrors.NotFoundError(fmt.Sprintf("ally character with id %d not found", allyCharID))
+	}
+	if allyChar.PlayerID == playerID {
+		return nil, nil, apperrors.InvalidFormatError("ally characters must belong to other players", nil)
+	}
+
+	allyStats := character.CalculateDerivedStats(allyChar, s.gameDataRepo)
+	ally := &domain.Combatant{
+		ID:             uuid.Must(uuid.NewV7()),
+		CharacterID:    &allyChar.ID,
+		Team:           domain.MatchSidePlayer,
+		IsAIControlled: true,
+		Initiative:     allyStats.Initiative,
+		CurrentHP:      allyStats.MaxHP,
+		CurrentMP:      allyChar.CurrentMP,
+		CurrentAP:      0,
+	}
+	if err := s._LoadDefenseDeck(ally); err != nil {
+		return nil, nil, apperrors.SystemError("failed to load ally deck")
+	}
+	if err := s._PrepareGhostSpells(ally, allyChar); err != nil {
+		s.appLogger.Error("Failed to resolve ally spells", err, "character_id", allyChar.ID)
+		return nil, nil, apperrors.SystemError("failed to resolve ally spells")
+	}
+	return ally, allyChar, nil
+}
+
+// _LoadDefenseDeck ใส่ Defense Deck ของเจ้าของตัวละครให้ combatant (ไม่ได้ตั้งไว้ = ใช้ได้แค่ธาตุ T0)
+func (s *combatService) _LoadDefenseDeck(combatant *domain.Combatant) error {
+	defenseDeck, err := s.deckRepo.FindDefenseDeck(*combatant.CharacterID)
+	if err != nil {
+		s.appLogger.Error("Failed to load defense deck", err, "character_id", *combatant.CharacterID)
+		return err
+	}
+	if defenseDeck == nil {
+		return nil
+	}
+	for _, slot := range defenseDeck.Slots {
+		combatant.Deck = append(combatant.Deck, &domain.CombatantDeck{
+			ID:          uuid.Must(uuid.NewV7()),
+			CombatantID: combatant.ID,
+			ElementID:   slot.ElementID,
+			IsConsumed:  false,
+		})
+	}
+	return nil
+}
+
+// findTeamOpponents คือ combatant ที่ยังสู้อยู่ของทีมตรงข้ามกับ combatant นี้ (เรียงตามลำดับใน match)
+func (s *combatService) findTeamOpponents(match *domain.CombatMatch, combatant *domain.Combatant) []*domain.Combatant {
+	var opponents []*domain.Combatant
+	for _, c := range s.findAliveCombatants(match) {
+		if combatantTeam(c) != combatantTeam(combatant) {
+			opponents = append(opponents, c)
+		}
+	}
+	return opponents
+}
@@ -2,7 +2,9 @@
 package combat
 
 import (
+	"errors"
 	"sage-of-elements-backend/internal/domain"
+	"strconv"
 
 	"github.com/gofrs/uuid"
 )
@@ -26,48 +28,130 @@ func (s *combatService) findCombatantByID(match *domain.CombatMatch, id uuid.UUI
 	return nil
 }
 
-// findPlayerCombatant ค้นหา combatant ที่เป็น player (ตัวแรกที่เจอ)
+// findPlayerCombatant ค้นหา combatant ฝั่งผู้เล่น (ผู้ท้าชิง) ตัวแรกที่เจอ
 // Returns nil ถ้าไม่เจอ player ในแมตช์
 func (s *combatService) findPlayerCombatant(match *domain.CombatMatch) *domain.Combatant {
 	for _, c := range match.Combatants {
-		if c.CharacterID != nil {
+		if isHumanControlled(c) && !isEnemySide(c) {
 			return c
 		}
 	}
 	return nil
 }
 
+// findCharacterCombatant ค้นหา combatant ของตัวละครที่ระบุ (nil ถ้าไม่อยู่ใน match)
+func (s *combatService) findCharacterCombatant(match *domain.CombatMatch, characterID uint) *domain.Combatant {
+	for _, c := range match.Combatants {
+		if c.CharacterID != nil && *c.CharacterID == characterID {
+			return c
+		}
+	}
+	return nil
+}
+
+// findControlledCombatant ค้นหา combatant ที่ผู้เล่นคนนี้ส่ง action ได้ (PvP สดจะมีผู้เล่นมากกว่า 1 คนใน match)
+// Returns nil ถ้าผู้เล่นไม่ได้ควบคุมตัวละครใดใน match นี้
+func (s *combatService) findControlledCombatant(match *domain.CombatMatch, playerID uint) *domain.Combatant {
+	for _, c := range match.Combatants {
+		if isHumanControlled(c) && c.Character != nil && c.Character.PlayerID == playerID {
+			return c
+		}
+	}
+	return nil
+}
+
+// isHumanControlled คือตัวละครที่ผู้เล่นส่ง action เอง (ไม่ใช่ศัตรู และไม่ใช่ตัวละครที่ AI เล่นแทน)
+func isHumanControlled(c *domain.Combatant) bool {
+	return c.CharacterID != nil && !c.IsAIControlled
+}
+
+// isEnemySide คือ combatant ฝั่งตรงข้ามผู้ท้าชิง (ศัตรู หรือตัวละครฝ่ายถูกท้าใน PvP)
+func isEnemySide(c *domain.Combatant) bool {
+	return combatantTeam(c) == domain.MatchSideEnemy
+}
+
+// combatantTeam คือทีมของ combatant (match เก่าที่ยังไม่มี Team: ศัตรูและฝ่ายถูกท้าอยู่ฝั่ง ENEMY)
+func combatantTeam(c *domain.Combatant) domain.MatchSide {
+	if c.Team != "" {
+		return c.Team
+	}
+	if c.EnemyID != nil || c.IsOpponent {
+		return domain.MatchSideEnemy
+	}
+	return domain.MatchSidePlayer
+}
+
+// isLiveDuel คือ PvP สด: ฝ่ายถูกท้าเป็นผู้เล่นที่ส่ง action เอง
+func isLiveDuel(match *domain.CombatMatch) bool {
+	for _, c := range match.Combatants {
+		if c.IsOpponent && isHumanControlled(c) {
+			return true
+		}
+	}
+	return false
+}
+
 // ==================== Combatant Filtering ====================
 
-// findPlayerCombatants ค้นหา combatants ที่เป็น player ทั้งหมด
+// findPlayerCombatants ค้นหา combatants ฝั่งผู้เล่น (ผู้ท้าชิง) ทั้งหมด
 func (s *combatService) findPlayerCombatants(match *domain.CombatMatch) []*domain.Combatant {
 	var players []*domain.Combatant
 	for _, c := range match.Combatants {
-		if c.CharacterID != nil {
+		if !isEnemySide(c) {
 			players = append(players, c)
 		}
 	}
 	return players
 }
 
-// findEnemyCombatants ค้นหา combatants ที่เป็น enemy ทั้งหมด
+// findEnemyCombatants ค้นหา combatants ฝั่งศัตรูทั้งหมด (รวมตัวละครฝ่ายถูกท้าใน PvP)
 func (s *combatService) findEnemyCombatants(match *domain.CombatMatch) []*domain.Combatant {
 	var enemies []*domain.Combatant
 	for _, c := range match.Combatants {
-		if c.EnemyID != nil {
+		if isEnemySide(c) {
 			enemies = append(enemies, c)
 		}
 	}
 	return enemies
 }
 
-// findAliveCombatants ค้นหา combatants ที่ยังมีชีวิต (HP > 0)
+// findAliveCombatants ค้นหา combatants ที่ยังสู้อยู่ (HP > 0 และยังไม่หนีออกจาก match)
 func (s *combatService) findAliveCombatants(match *domain.CombatMatch) []*domain.Combatant {
 	var alive []*domain.Combatant
 	for _, c := range match.Combatants {
-		if c.CurrentHP > 0 {
+		if c.CurrentHP > 0 && !c.HasFled {
 			alive = append(alive, c)
 		}
 	}
 	return alive
 }
+
+// _GetConfigFloat อ่าน game config เป็นทศนิยม (ใช้ fallback ถ้าไม่มีหรือรูปแบบผิด)
+func (s *combatService) _GetConfigFloat(key string, fallback float64) float64 {
+	valueStr, err := s.gameDataRepo.GetGameConfigValue(key)
+	if err != nil {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// ==================== Concurrency ====================
+
+// matchConflictRetries คือจำนวนครั้งที่ลองใหม่เมื่อบันทึก match ชนกับ request อื่น
+const matchConflictRetries = 3
+
+// retryOnMatchConflict เรียก fn ซ้ำเมื่อ UpdateMatch คืน ErrMatchConflict
+// fn ต้องโหลด match ใหม่และตรวจเงื่อนไขใหม่ทุกครั้ง (เช่น ยังเป็นเทิร์นของผู้เล่นอยู่ไหม), ชนครบทุกครั้ง → คืน 409 ให้ client ส่งใหม่
+func retryOnMatchConflict(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < matchConflictRetries; attempt++ {
+		if err = fn(); !errors.Is(err, ErrMatchConflict) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+// file: internal/modules/combat/pvp_rating.go
+package combat
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/pvp"
+)
+
+// ==================== Ranked Rating ====================
+// ไฟล์นี้ปรับคะแนน Glicko-2 ของทั้งสองฝั่งเมื่อ Ranked match จบ (ชนะ/แพ้/ยอมแพ้/หมดเวลา)
+// - คำนวณจากคะแนน "ก่อน" match ของทั้งคู่ แล้วบันทึกพร้อมประวัติ
+// - คะแนนและประวัติถูกเก็บไว้ใน match แล้วเขียนพร้อมการจบ match ใน UpdateMatch (save ไม่สำเร็จ = คะแนนไม่เปลี่ยน)
+// - บันทึก RATING_CHANGED ให้ client แสดงผลได้ทันทีจาก event ของ match
+
+// _UpdatePvpRatings ปรับคะแนนของผู้ท้า (ฝั่งผู้เล่น) และคู่ต่อสู้ (ฝั่งศัตรู) ตามผลของ match
+func (s *combatService) _UpdatePvpRatings(match *domain.CombatMatch, playerDefeated bool) {
+	if !match.Ranked || s.pvpRepo == nil {
+		return
+	}
+
+	player := s.findPlayerCombatant(match)
+	var opponent *domain.Combatant
+	for _, c := range s.findEnemyCombatants(match) {
+		if c.CharacterID != nil {
+			opponent = c
+			break
+		}
+	}
+	if player == nil || opponent == nil {
+		s.appLogger.Warn("Ranked match without two characters, rating unchanged", "match_id", match.ID)
+		return
+	}
+
+	playerRating, err := s._GetOrDefaultPvpRating(*player.CharacterID)
+	if err != nil {
+		s.appLogger.Error("Failed to load pvp rating", err, "character_id", *player.CharacterID)
+		return
+	}
+	opponentRating, err := s._GetOrDefaultPvpRating(*opponent.CharacterID)
+	if err != nil {
+		s.appLogger.Error("Failed to load pvp rating", err, "character_id", *opponent.CharacterID)
+		return
+	}
+
+	tau := pvp.GlickoTau(s.gameDataRepo)
+	newPlayerRating := pvp.ApplyMatchResult(*playerRating, *opponentRating, !playerDefeated, tau)
+	newOpponentRating := pvp.ApplyMatchResult(*opponentRating, *playerRating, playerDefeated, tau)
+
+	s._SavePvpRating(match, player, playerRating.Rating, &newPlayerRating, *opponent.CharacterID, !playerDefeated)
+	s._SavePvpRating(match, opponent, opponentRating.Rating, &newOpponentRating, *player.CharacterID, playerDefeated)
+}
+
+// _SavePvpRating เก็บคะแนนใหม่และประวัติไว้ใน match และบันทึก event ของตัวละคร 1 ตัว
+func (s *combatService) _SavePvpRating(
+	match *domain.CombatMatch,
+	combatant *domain.Combatant,
+	ratingBefore float64,
+	rating *domain.CharacterPvpRating,
+	opponentCharacterID uint,
+	won bool,
+) {
+	match.PendingRatings = append(match.PendingRatings, rating)
+	match.PendingRatingHistory = append(match.PendingRatingHistory, &domain.PvpRatingHistory{
+		CharacterID:         rating.CharacterID,
+		MatchID:             match.ID,
+		OpponentCharacterID: opponentCharacterID,
+		Won:                 won,
+		RatingBefore:        ratingBefore,
+		RatingAfter:         rating.Rating,
+	})
+
+	event := newCombatEvent(domain.CombatEventRatingChanged, combatant, nil, int(math.Round(rating.Rating-ratingBefore)))
+	event.Details = eventDetails(map[string]interface{}{
+		"rating_before": ratingBefore,
+		"rating_after":  rating.Rating,
+		"tier":          pvp.RankTierForRating(rating.Rating),
+		"won":           won,
+	})
+	s.recordEvent(match, event)
+
+	s.appLogger.Info("Pvp rating updated",
+		"match_id", match.ID,
+		"character_id", rating.CharacterID,
+		"rating_before", ratingBefore,
+		"rating_after", rating.Rating,
+	)
+}
+
+func (s *combatService) _GetOrDefaultPvpRating(characterID uint) (*domain.CharacterPvpRating, error) {
+	rating, err := s.pvpRepo.FindRatingByCharacterID(characterID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		rating = pvp.NewDefaultRating(characterID, s.gameDataRepo)
+	}
+	return rating, nil
+}
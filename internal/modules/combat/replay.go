@@ -0,0 +1,529 @@
+// file: internal/modules/combat/replay.go
+package combat
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/internal/modules/pve"
+	"sage-of-elements-backend/pkg/apperrors"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/datatypes"
+)
+
+// ==================== Match Replay ====================
+// ไฟล์นี้รวบรวมทุกอย่างที่เกี่ยวกับ replay:
+// - Snapshot สถานะเริ่มต้นตอน CreateMatch (combatants, decks, game configs, seed)
+// - การบันทึก action ของผู้เล่นที่ถูกยอมรับ (CombatAction)
+// - Replay file format (versioned JSON) สำหรับ export / import
+// - Re-simulator: เล่น action ซ้ำผ่าน executePlayerAction เพื่อตรวจสอบสถานะสุดท้าย
+
+// ReplayFormatVersion คือเวอร์ชันของ replay file format (เพิ่มเมื่อโครงสร้างเปลี่ยนแบบไม่ backward compatible)
+const ReplayFormatVersion = 1
+
+// MatchReplay คือ replay file ที่ export/import ได้
+type MatchReplay struct {
+	Version    int                    `json:"version"`
+	MatchID    uuid.UUID              `json:"matchId"`
+	MatchType  domain.MatchType       `json:"matchType"`
+	Snapshot   MatchSnapshot          `json:"snapshot"`
+	Actions    []*domain.CombatAction `json:"actions"`
+	FinalState *ReplayFinalState      `json:"finalState,omitempty"`
+}
+
+// MatchSnapshot คือสถานะเริ่มต้นของ match ณ ตอนสร้าง
+type MatchSnapshot struct {
+	StageID     *uint                `json:"stageId,omitempty"`
+	Modifiers   datatypes.JSON       `json:"modifiers,omitempty"`
+	Seed        int64                `json:"seed,string"`
+	TurnNumber  int                  `json:"turnNumber"`
+	CurrentTurn uuid.UUID            `json:"currentTurn"`
+	Combatants  []*SnapshotCombatant `json:"combatants"`
+	GameConfigs map[string]string    `json:"gameConfigs"`
+}
+
+// SnapshotCombatant คือข้อมูล combatant 1 ตัวใน snapshot (รวม deck และข้อมูลตัวละคร/ศัตรู ณ ตอนเริ่ม)
+type SnapshotCombatant struct {
+	ID            uuid.UUID               `json:"id"`
+	CharacterID   *uint                   `json:"characterId,omitempty"`
+	Character     *domain.Character       `json:"character,omitempty"`
+	EnemyID       *uint                   `json:"enemyId,omitempty"`
+	Enemy         *domain.Enemy           `json:"enemy,omitempty"`
+	Team          domain.MatchSide        `json:"team,omitempty"` // ว่าง = replay ก่อนมีทีม (อนุมานจาก EnemyID/Opponent)
+	Opponent      bool                    `json:"opponent,omitempty"`
+	AIControlled  bool                    `json:"aiControlled,omitempty"`
+	Level         int                     `json:"level,omitempty"`
+	MaxHP         int                     `json:"maxHp,omitempty"`
+	Initiative    int                     `json:"initiative"`
+	CurrentHP     int                     `json:"currentHp"`
+	CurrentMP     int                     `json:"currentMp"`
+	CurrentAP     int                     `json:"currentAp"`
+	ActiveEffects datatypes.JSON          `json:"activeEffects,omitempty"`
+	Deck          []*domain.CombatantDeck `json:"deck,omitempty"`
+	GhostSpells   datatypes.JSON          `json:"ghostSpells,omitempty"` // ว่าง = replay ก่อนมีรายการเวทของ ghost
+	SealElements  datatypes.JSON          `json:"sealElements,omitempty"`
+}
+
+// ReplayFinalState คือสถานะสุดท้ายที่ใช้เทียบผลการ re-simulate
+type ReplayFinalState struct {
+	Status      domain.MatchStatus      `json:"status"`
+	TurnNumber  int                     `json:"turnNumber"`
+	CurrentTurn uuid.UUID               `json:"currentTurn"`
+	Combatants  []*ReplayCombatantState `json:"combatants"`
+}
+
+// ReplayCombatantState คือค่าพลังสุดท้ายของ combatant 1 ตัว
+type ReplayCombatantState struct {
+	ID            uuid.UUID             `json:"id"`
+	CurrentHP     int                   `json:"currentHp"`
+	CurrentMP     int                   `json:"currentMp"`
+	CurrentAP     int                   `json:"currentAp"`
+	HasFled       bool                  `json:"hasFled,omitempty"`
+	ActiveEffects []domain.ActiveEffect `json:"activeEffects"`
+}
+
+// ReplayVerification คือผลลัพธ์ของการ re-simulate replay
+type ReplayVerification struct {
+	Version         int               `json:"version"`
+	MatchID         uuid.UUID         `json:"matchId"`
+	ActionsReplayed int               `json:"actionsReplayed"`
+	Verified        bool              `json:"verified"`
+	Mismatches      []string          `json:"mismatches,omitempty"`
+	SimulatedState  *ReplayFinalState `json:"simulatedState"`
+}
+
+// ==================== Recording ====================
+
+// recordAction บันทึก action ของผู้เล่นที่ถูกยอมรับลง match.PendingActions
+func (s *combatService) recordAction(match *domain.CombatMatch, actor *domain.Combatant, req PerformActionRequest) {
+	match.ActionSequence++
+	action := &domain.CombatAction{
+		MatchID:     match.ID,
+		Sequence:    match.ActionSequence,
+		TurnNumber:  match.TurnNumber,
+		CombatantID: actor.ID,
+		ActionType:  req.ActionType,
+		CastMode:    req.CastMode,
+		SpellID:     req.SpellID,
+	}
+	if req.TargetID != nil {
+		if targetID, err := uuid.FromString(*req.TargetID); err == nil {
+			action.TargetID = &targetID
+		}
+	}
+	match.PendingActions = append(match.PendingActions, action)
+}
+
+// _BuildMatchSnapshot สร้าง snapshot สถานะเริ่มต้นของ match (เรียกใน CreateMatch ก่อนบันทึก)
+func (s *combatService) _BuildMatchSnapshot(
+	match *domain.CombatMatch,
+	characters map[uint]*domain.Character,
+	enemies map[uint]*domain.Enemy,
+) (datatypes.JSON, error) {
+	snapshot := MatchSnapshot{
+		StageID:     match.StageID,
+		Modifiers:   match.Modifiers,
+		Seed:        match.RandomSeed,
+		TurnNumber:  match.TurnNumber,
+		CurrentTurn: match.CurrentTurn,
+		GameConfigs: make(map[string]string),
+	}
+
+	configs, err := s.gameDataRepo.FindAllGameConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		snapshot.GameConfigs[config.Key] = config.Value
+	}
+
+	for _, c := range match.Combatants {
+		sc := &SnapshotCombatant{
+			ID:            c.ID,
+			CharacterID:   c.CharacterID,
+			EnemyID:       c.EnemyID,
+			Team:          c.Team,
+			Opponent:      c.IsOpponent,
+			AIControlled:  c.IsAIControlled,
+			Level:         c.Level,
+			MaxHP:         c.MaxHP,
+			Initiative:    c.Initiative,
+			CurrentHP:     c.CurrentHP,
+			CurrentMP:     c.CurrentMP,
+			CurrentAP:     c.CurrentAP,
+			ActiveEffects: c.ActiveEffects,
+			Deck:          c.Deck,
+			GhostSpells:   c.GhostSpells,
+			SealElements:  c.SealElements,
+		}
+		if c.CharacterID != nil {
+			sc.Character = characters[*c.CharacterID]
+		}
+		if c.EnemyID != nil {
+			sc.Enemy = enemies[*c.EnemyID]
+		}
+		snapshot.Combatants = append(snapshot.Combatants, sc)
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// ==================== Export ====================
+
+// GetMatchReplay สร้าง replay file ของ match (เฉพาะผู้เล่นที่อยู่ใน match และ match ต้องจบแล้ว)
+func (s *combatService) GetMatchReplay(playerID uint, matchID string) (*MatchReplay, error) {
+	match, err := s.combatRepo.FindMatchByID(matchID)
+	if err != nil {
+		return nil, apperrors.NotFoundError("match not found")
+	}
+
+	isParticipant := false
+	for _, c := range match.Combatants {
+		// ทั้งผู้ท้าชิงและเจ้าของตัวละครฝ่ายป้องกัน (PvP แบบ async) ดู replay ได้
+		if c.Character != nil && c.Character.PlayerID == playerID {
+			isParticipant = true
+			break
+		}
+	}
+	if !isParticipant {
+		return nil, apperrors.PermissionDeniedError("you are not part of this match")
+	}
+
+	// ⚠️ replay มี seed อยู่ด้วย ถ้าเปิดให้ดูระหว่างเล่นจะทำนายผลสุ่มถัดไปได้
+	if match.Status == domain.MatchInProgress {
+		return nil, apperrors.New(409, "MATCH_IN_PROGRESS", "replay is only available after the match has ended")
+	}
+	if len(match.Snapshot) == 0 {
+		return nil, apperrors.New(404, "REPLAY_NOT_AVAILABLE", "this match was created before replays were recorded")
+	}
+
+	var snapshot MatchSnapshot
+	if err := json.Unmarshal(match.Snapshot, &snapshot); err != nil {
+		s.appLogger.Error("Failed to unmarshal match snapshot", err, "match_id", matchID)
+		return nil, apperrors.SystemError("failed to read match snapshot")
+	}
+
+	actions, err := s.combatRepo.FindActionsByMatchID(matchID)
+	if err != nil {
+		s.appLogger.Error("Failed to load match actions", err, "match_id", matchID)
+		return nil, apperrors.SystemError("failed to load match actions")
+	}
+
+	return &MatchReplay{
+		Version:    ReplayFormatVersion,
+		MatchID:    match.ID,
+		MatchType:  match.MatchType,
+		Snapshot:   snapshot,
+		Actions:    actions,
+		FinalState: s._CaptureFinalState(match),
+	}, nil
+}
+
+// _CaptureFinalState เก็บค่าที่ใช้เทียบผลจาก match
+func (s *combatService) _CaptureFinalState(match *domain.CombatMatch) *ReplayFinalState {
+	state := &ReplayFinalState{
+		Status:      match.Status,
+		TurnNumber:  match.TurnNumber,
+		CurrentTurn: match.CurrentTurn,
+	}
+	for _, c := range match.Combatants {
+		var effects []domain.ActiveEffect
+		if c.ActiveEffects != nil {
+			json.Unmarshal(c.ActiveEffects, &effects)
+		}
+		state.Combatants = append(state.Combatants, &ReplayCombatantState{
+			ID:            c.ID,
+			CurrentHP:     c.CurrentHP,
+			CurrentMP:     c.CurrentMP,
+			CurrentAP:     c.CurrentAP,
+			HasFled:       c.HasFled,
+			ActiveEffects: effects,
+		})
+	}
+	return state
+}
+
+// ==================== Re-Simulation ====================
+
+// VerifyReplay เล่น action ใน replay ซ้ำจาก snapshot แล้วเทียบกับ FinalState ที่บันทึกไว้
+// ไม่มีการเขียน DB หรือให้ EXP/รางวัลใดๆ ระหว่าง re-simulate
+func (s *combatService) VerifyReplay(replay *MatchReplay) (*ReplayVerification, error) {
+	if replay == nil {
+		return nil, apperrors.InvalidFormatError("replay is required", nil)
+	}
+	if replay.Version != ReplayFormatVersion {
+		return nil, apperrors.New(422, "UNSUPPORTED_REPLAY_VERSION",
+			fmt.Sprintf("replay version %d is not supported (expected %d)", replay.Version, ReplayFormatVersion))
+	}
+	if err := replay.validate(); err != nil {
+		return nil, err
+	}
+
+	// 1. สร้าง service สำหรับ simulate (ใช้ config จาก snapshot และไม่แตะข้อมูลตัวละครจริง)
+	sim := *s
+	sim.gameDataRepo = &snapshotGameDataRepository{GameDataRepository: s.gameDataRepo, configs: replay.Snapshot.GameConfigs}
+	sim.characterRepo = &replayCharacterRepository{CharacterRepository: s.characterRepo}
+	sim.pveRepo = &replayPveRepository{PveRepository: s.pveRepo}
+	sim.pvpRepo = nil // ไม่ปรับคะแนน Ranked ซ้ำ
+
+	// 2. สร้าง match จาก snapshot
+	match := replay.Snapshot.toMatch(replay)
+
+	// 3. เล่นเทิร์นเปิดของ AI (ถ้ามี) เหมือน CreateMatch แล้วเล่น action ทีละตัวตามลำดับ
+	result := &ReplayVerification{
+		Version: ReplayFormatVersion,
+		MatchID: replay.MatchID,
+	}
+	actions := replay.Actions
+	if opened, err := sim.processAllAITurns(match); err != nil {
+		result.Mismatches = append(result.Mismatches, fmt.Sprintf("opening AI turns failed during simulation: %v", err))
+		actions = nil
+	} else {
+		match = opened
+	}
+	for _, action := range actions {
+		if match.Status != domain.MatchInProgress {
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("action #%d was recorded after the simulated match had already ended", action.Sequence))
+			break
+		}
+		actor := sim.findCombatantByID(match, action.CombatantID)
+		if actor == nil {
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("action #%d references unknown combatant %s", action.Sequence, action.CombatantID))
+			break
+		}
+		if action.ActionType == ActionTypeForfeit {
+			// ยอมแพ้ได้ทุกเมื่อ (ไม่ต้องเป็นเทิร์นของตัวเอง) แต่เฉพาะตัวละครที่ผู้เล่นคุมเองเหมือน ForfeitMatch
+			if !isHumanControlled(actor) {
+				result.Mismatches = append(result.Mismatches,
+					fmt.Sprintf("action #%d is a forfeit by %s, which is not a player-controlled character", action.Sequence, actor.ID))
+				break
+			}
+			sim._EndMatchByForfeit(match, actor)
+			result.ActionsReplayed++
+			continue
+		}
+		if match.CurrentTurn != actor.ID {
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("action #%d was sent by %s but the simulated turn belongs to %s", action.Sequence, actor.ID, match.CurrentTurn))
+			break
+		}
+
+		if action.ActionType == ActionTypeMatchTimeout {
+			// match หมดเวลา → จบเป็นแพ้เหมือนตอนเล่นจริง
+			sim._EndMatchByTimeout(match)
+			result.ActionsReplayed++
+			continue
+		}
+
+		updated, err := sim.executePlayerAction(match, actor, actionToRequest(action))
+		if err != nil {
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("action #%d was rejected during simulation: %v", action.Sequence, err))
+			break
+		}
+		match = updated
+		result.ActionsReplayed++
+	}
+
+	// 4. เทียบผลลัพธ์
+	result.SimulatedState = sim._CaptureFinalState(match)
+	if replay.FinalState != nil {
+		result.Mismatches = append(result.Mismatches, compareFinalStates(replay.FinalState, result.SimulatedState)...)
+	}
+	result.Verified = len(result.Mismatches) == 0
+
+	s.appLogger.Info("Replay verification completed",
+		"match_id", replay.MatchID,
+		"actions_replayed", result.ActionsReplayed,
+		"verified", result.Verified,
+		"mismatch_count", len(result.Mismatches),
+	)
+
+	return result, nil
+}
+
+// validate ตรวจโครงสร้างของ replay ที่ import เข้ามาก่อน simulate (ไฟล์มาจากผู้ใช้ ห้ามเชื่อว่าครบ)
+// - ไม่มีรายการ null ใน combatants / deck / actions
+// - combatant แต่ละตัวต้องเป็นตัวละครหรือศัตรูอย่างใดอย่างหนึ่ง พร้อมข้อมูลที่ตรงกับ ID
+func (replay *MatchReplay) validate() error {
+	if len(replay.Snapshot.Combatants) == 0 {
+		return apperrors.InvalidFormatError("replay snapshot has no combatants", nil)
+	}
+	for i, sc := range replay.Snapshot.Combatants {
+		if sc == nil {
+			return apperrors.InvalidFormatError(fmt.Sprintf("replay snapshot combatant #%d is empty", i), nil)
+		}
+		hasCharacter := sc.CharacterID != nil || sc.Character != nil
+		hasEnemy := sc.EnemyID != nil || sc.Enemy != nil
+		if hasCharacter == hasEnemy {
+			return apperrors.InvalidFormatError(fmt.Sprintf("replay snapshot combatant %s must be exactly one character or one enemy", sc.ID), nil)
+		}
+		if hasCharacter && (sc.CharacterID == nil || sc.Character == nil || *sc.CharacterID != sc.Character.ID) {
+			return apperrors.InvalidFormatError(fmt.Sprintf("replay snapshot combatant %s has character data that does not match characterId", sc.ID), nil)
+		}
+		if hasEnemy && (sc.EnemyID == nil || sc.Enemy == nil || *sc.EnemyID != sc.Enemy.ID) {
+			return apperrors.InvalidFormatError(fmt.Sprintf("replay snapshot combatant %s has enemy data that does not match enemyId", sc.ID), nil)
+		}
+		for _, charge := range sc.Deck {
+			if charge == nil {
+				return apperrors.InvalidFormatError(fmt.Sprintf("replay snapshot combatant %s has an empty deck slot", sc.ID), nil)
+			}
+		}
+	}
+	for i, action := range replay.Actions {
+		if action == nil {
+			return apperrors.InvalidFormatError(fmt.Sprintf("replay action #%d is empty", i), nil)
+		}
+	}
+	return nil
+}
+
+// toMatch สร้าง CombatMatch ใหม่จาก snapshot (ไม่ผูกกับ DB, ต้องผ่าน validate ก่อน)
+func (snap *MatchSnapshot) toMatch(replay *MatchReplay) *domain.CombatMatch {
+	match := &domain.CombatMatch{
+		ID:          replay.MatchID,
+		MatchType:   replay.MatchType,
+		StageID:     snap.StageID,
+		Status:      domain.MatchInProgress,
+		Modifiers:   snap.Modifiers,
+		TurnNumber:  snap.TurnNumber,
+		CurrentTurn: snap.CurrentTurn,
+		RandomSeed:  snap.Seed,
+	}
+	for _, sc := range snap.Combatants {
+		combatant := &domain.Combatant{
+			ID:             sc.ID,
+			MatchID:        replay.MatchID,
+			CharacterID:    sc.CharacterID,
+			Character:      sc.Character,
+			EnemyID:        sc.EnemyID,
+			Enemy:          sc.Enemy,
+			Team:           sc.Team,
+			IsOpponent:     sc.Opponent,
+			IsAIControlled: sc.AIControlled,
+			Level:          sc.Level,
+			MaxHP:          sc.MaxHP,
+			Initiative:     sc.Initiative,
+			CurrentHP:      sc.CurrentHP,
+			CurrentMP:      sc.CurrentMP,
+			CurrentAP:      sc.CurrentAP,
+			ActiveEffects:  sc.ActiveEffects,
+			GhostSpells:    sc.GhostSpells,
+			SealElements:   sc.SealElements,
+		}
+		for _, charge := range sc.Deck {
+			chargeCopy := *charge
+			combatant.Deck = append(combatant.Deck, &chargeCopy)
+		}
+		match.Combatants = append(match.Combatants, combatant)
+	}
+	return match
+}
+
+// actionToRequest แปลง action ที่บันทึกไว้กลับเป็น PerformActionRequest
+func actionToRequest(action *domain.CombatAction) PerformActionRequest {
+	req := PerformActionRequest{
+		ActionType: action.ActionType,
+		CastMode:   action.CastMode,
+		SpellID:    action.SpellID,
+	}
+	if action.TargetID != nil {
+		targetID := action.TargetID.String()
+		req.TargetID = &targetID
+	}
+	return req
+}
+
+// compareFinalStates เทียบสถานะสุดท้ายที่บันทึกไว้กับผลที่ simulate ได้
+func compareFinalStates(expected *ReplayFinalState, actual *ReplayFinalState) []string {
+	var mismatches []string
+	if expected.Status != actual.Status {
+		mismatches = append(mismatches, fmt.Sprintf("status: expected %s, got %s", expected.Status, actual.Status))
+	}
+	if expected.TurnNumber != actual.TurnNumber {
+		mismatches = append(mismatches, fmt.Sprintf("turn number: expected %d, got %d", expected.TurnNumber, actual.TurnNumber))
+	}
+
+	actualByID := make(map[uuid.UUID]*ReplayCombatantState, len(actual.Combatants))
+	for _, c := range actual.Combatants {
+		actualByID[c.ID] = c
+	}
+	for _, want := range expected.Combatants {
+		got, ok := actualByID[want.ID]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("combatant %s: missing from simulation", want.ID))
+			continue
+		}
+		if want.CurrentHP != got.CurrentHP {
+			mismatches = append(mismatches, fmt.Sprintf("combatant %s HP: expected %d, got %d", want.ID, want.CurrentHP, got.CurrentHP))
+		}
+		if want.CurrentMP != got.CurrentMP {
+			mismatches = append(mismatches, fmt.Sprintf("combatant %s MP: expected %d, got %d", want.ID, want.CurrentMP, got.CurrentMP))
+		}
+		if want.CurrentAP != got.CurrentAP {
+			mismatches = append(mismatches, fmt.Sprintf("combatant %s AP: expected %d, got %d", want.ID, want.CurrentAP, got.CurrentAP))
+		}
+		if want.HasFled != got.HasFled {
+			mismatches = append(mismatches, fmt.Sprintf("combatant %s fled: expected %t, got %t", want.ID, want.HasFled, got.HasFled))
+		}
+		if len(want.ActiveEffects) != 0 || len(got.ActiveEffects) != 0 {
+			if !reflect.DeepEqual(want.ActiveEffects, got.ActiveEffects) {
+				mismatches = append(mismatches, fmt.Sprintf("combatant %s active effects differ", want.ID))
+			}
+		}
+	}
+	return mismatches
+}
+
+// ==================== Replay Repository Overlays ====================
+
+// snapshotGameDataRepository ใช้ game config จาก snapshot แทนค่าปัจจุบัน
+// (ข้อมูลอื่นเช่น spell/effect ยังอ่านจาก repository จริง)
+type snapshotGameDataRepository struct {
+	game_data.GameDataRepository
+	configs map[string]string
+}
+
+func (r *snapshotGameDataRepository) GetGameConfigValue(key string) (string, error) {
+	value, ok := r.configs[key]
+	if !ok {
+		return "", fmt.Errorf("game config '%s' not found in replay snapshot", key)
+	}
+	return value, nil
+}
+
+func (r *snapshotGameDataRepository) FindAllGameConfigs() ([]domain.GameConfig, error) {
+	configs := make([]domain.GameConfig, 0, len(r.configs))
+	for key, value := range r.configs {
+		configs = append(configs, domain.GameConfig{Key: key, Value: value})
+	}
+	return configs, nil
+}
+
+// replayCharacterRepository กันไม่ให้ re-simulate ไปแก้ข้อมูลตัวละครจริง (เช่นให้ EXP ซ้ำตอนชนะ)
+type replayCharacterRepository struct {
+	character.CharacterRepository
+}
+
+func (r *replayCharacterRepository) FindByID(id uint) (*domain.Character, error) {
+	return nil, nil
+}
+
+func (r *replayCharacterRepository) UnlockSpells(characterID uint, spellIDs []uint) error {
+	return nil
+}
+
+// replayPveRepository กันไม่ให้ re-simulate ไปบันทึกการเคลียร์ด่านหรือให้รางวัลซ้ำ
+type replayPveRepository struct {
+	pve.PveRepository
+}
+
+func (r *replayPveRepository) FindStageProgress(characterID, stageID uint) (*domain.CharacterStageProgress, error) {
+	return nil, nil
+}
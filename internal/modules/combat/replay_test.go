@@ -0,0 +1,64 @@
+package combat
+
+import (
+	"encoding/json"
+	"errors"
+	"sage-of-elements-backend/pkg/apperrors"
+	"sage-of-elements-backend/pkg/applogger"
+	"testing"
+)
+
+func TestVerifyReplayRejectsMalformedFiles(t *testing.T) {
+	s := &combatService{appLogger: applogger.NewNopLogger()}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "no combatants", body: `{"version":1,"snapshot":{"combatants":[]}}`},
+		{name: "null combatant", body: `{"version":1,"snapshot":{"combatants":[null]}}`},
+		{name: "neither character nor enemy", body: `{"version":1,"snapshot":{"combatants":[{"id":"01a14eed-749c-7489-9ea3-f578dc3942ec"}]}}`},
+		{name: "both character and enemy", body: `{"version":1,"snapshot":{"combatants":[{"characterId":1,"character":{"id":1},"enemyId":2,"enemy":{"id":2}}]}}`},
+		{name: "character without data", body: `{"version":1,"snapshot":{"combatants":[{"characterId":1}]}}`},
+		{name: "enemy data with another id", body: `{"version":1,"snapshot":{"combatants":[{"enemyId":1,"enemy":{"id":2}}]}}`},
+		{name: "null deck slot", body: `{"version":1,"snapshot":{"combatants":[{"enemyId":1,"enemy":{"id":1},"deck":[null]}]}}`},
+		{name: "null action", body: `{"version":1,"snapshot":{"combatants":[{"enemyId":1,"enemy":{"id":1}}]},"actions":[null]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay := new(MatchReplay)
+			if err := json.Unmarshal([]byte(tt.body), replay); err != nil {
+				t.Fatalf("test body does not parse: %v", err)
+			}
+
+			result, err := s.VerifyReplay(replay)
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) || appErr.HTTPStatus >= 500 {
+				t.Fatalf("VerifyReplay() = %v, %v, want a client error", result, err)
+			}
+		})
+	}
+}
+
+func TestVerifyReplayRejectsForfeitByEnemy(t *testing.T) {
+	s := &combatService{appLogger: applogger.NewNopLogger()}
+	body := `{"version":1,
+		"snapshot":{"turnNumber":1,"currentTurn":"01a14eed-749c-7489-9ea3-f578dc3942eb",
+			"combatants":[
+				{"id":"01a14eed-749c-7489-9ea3-f578dc3942eb","characterId":1,"character":{"id":1},"team":"PLAYER","currentHp":10},
+				{"id":"01a14eed-749c-7489-9ea3-f578dc3942ec","enemyId":1,"enemy":{"id":1},"team":"ENEMY","currentHp":10}]},
+		"actions":[{"sequence":1,"combatantId":"01a14eed-749c-7489-9ea3-f578dc3942ec","actionType":"FORFEIT"}]}`
+	replay := new(MatchReplay)
+	if err := json.Unmarshal([]byte(body), replay); err != nil {
+		t.Fatalf("test body does not parse: %v", err)
+	}
+
+	result, err := s.VerifyReplay(replay)
+	if err != nil {
+		t.Fatalf("VerifyReplay() error = %v", err)
+	}
+	if result.Verified || result.ActionsReplayed != 0 || len(result.Mismatches) != 1 {
+		t.Errorf("verified = %t, replayed = %d, mismatches = %v, want the forfeit rejected as a mismatch",
+			result.Verified, result.ActionsReplayed, result.Mismatches)
+	}
+}
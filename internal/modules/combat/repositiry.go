@@ -1,16 +1,29 @@
 // file: internal/modules/combat/repository.go
 package combat
 
-import "sage-of-elements-backend/internal/domain"
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/pkg/apperrors"
+	"time"
+)
+
+// ErrMatchConflict คือ error จาก UpdateMatch เมื่อ match ถูกบันทึกโดย request อื่นหลังจากที่โหลดมา (Version ไม่ตรง)
+// ไม่มีอะไรถูกบันทึก → โหลด match ใหม่แล้วทำซ้ำได้ (ดู retryOnMatchConflict)
+var ErrMatchConflict = apperrors.New(409, "MATCH_CONFLICT", "the match was changed by another request, please retry")
 
 type CombatRepository interface {
 	CreateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error)
 	FindMatchByID(matchID string) (*domain.CombatMatch, error)
-	UpdateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error)
+	UpdateMatch(match *domain.CombatMatch) (*domain.CombatMatch, error)  // ErrMatchConflict ถ้า match.Version ไม่ใช่ค่าล่าสุด
+	FindActionsByMatchID(matchID string) ([]*domain.CombatAction, error) // action ของผู้เล่นเรียงตามลำดับ (สำหรับ replay)
+	FindEventsByMatchID(matchID string) ([]*domain.CombatEvent, error)   // event ที่บันทึกแล้วเรียงตามลำดับ (สำหรับสรุปผล)
 
 	// 🧹 Cleanup Methods - สำหรับจัดการ match ค้าง
 	FindStaleMatches(inactiveMinutes int) ([]*domain.CombatMatch, error)       // หา match ที่ไม่มีความเคลื่อนไหวนานเกินกำหนด
 	AbortStaleMatches(inactiveMinutes int) (int64, error)                      // Abort match ค้างทั้งหมด (return จำนวนที่ abort)
 	FindPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error)       // หา match ที่ผู้เล่นกำลังเล่นอยู่
 	AbortMatchByID(matchID string, reason string) (*domain.CombatMatch, error) // Abort match เฉพาะ ID
+
+	// ⏱️ Turn Timer
+	FindTimedOutMatches(now time.Time) ([]*domain.CombatMatch, error) // หา match ที่ TurnDeadline หรือ MatchDeadline เลย now ไปแล้ว
 }
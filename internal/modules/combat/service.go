@@ -4,12 +4,14 @@ package combat
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"sage-of-elements-backend/internal/domain"
 	"sage-of-elements-backend/internal/modules/character"
 	"sage-of-elements-backend/internal/modules/deck"
 	"sage-of-elements-backend/internal/modules/enemy"
 	"sage-of-elements-backend/internal/modules/game_data"
 	"sage-of-elements-backend/internal/modules/pve"
+	"sage-of-elements-backend/internal/modules/pvp"
 	"sage-of-elements-backend/pkg/apperrors"
 	"sage-of-elements-backend/pkg/applogger"
 	"strconv"
@@ -25,9 +27,29 @@ type CombatService interface {
 	ResolveSpell(elementID uint, masteryID uint, casterMainElementID uint) (*domain.Spell, error)
 
 	// 🧹 Cleanup Methods
-	CleanupStaleMatches(inactiveMinutes int) (int64, error)             // ทำความสะอาด match ค้าง (สำหรับ cron job)
-	AbortMatch(matchID string, reason string) error                     // Abort match เฉพาะ (สำหรับ forfeit/disconnect)
-	GetPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error) // ตรวจสอบว่าผู้เล่นกำลังเล่นอยู่หรือเปล่า
+	CleanupStaleMatches(inactiveMinutes int) (int64, error)                  // ทำความสะอาด match ค้าง (สำหรับ cron job)
+	AbortMatch(matchID string, reason string) error                          // Abort match เฉพาะ (สำหรับ forfeit/disconnect)
+	ForfeitMatch(playerID uint, matchID string) (*domain.CombatMatch, error) // ผู้เล่นยอมแพ้ (เช่น หลุดจาก PvP สด) → อีกฝ่ายชนะ
+	GetPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error)      // ตรวจสอบว่าผู้เล่นกำลังเล่นอยู่หรือเปล่า
+
+	// 🔁 Resume Methods (client ที่หลุดกลางเกมโหลด match กลับมาเล่นต่อ)
+	GetActiveMatch(playerID, characterID uint) (*domain.CombatMatch, error) // match ที่ตัวละครกำลังเล่นอยู่ (สถานะเต็ม)
+	GetMatch(playerID uint, matchID string) (*domain.CombatMatch, error)    // ดู match ที่ผู้เล่นมีตัวละครอยู่
+
+	// 🎞️ Replay Methods
+	GetMatchReplay(playerID uint, matchID string) (*MatchReplay, error) // Export replay ของ match ที่จบแล้ว
+	VerifyReplay(replay *MatchReplay) (*ReplayVerification, error)      // Re-simulate replay แล้วเทียบสถานะสุดท้าย
+
+	// ⏱️ Turn Timer
+	ProcessTimeouts() (int, error) // END_TURN แทนผู้เล่นที่หมดเวลา และจบ match ที่หมดเวลา (สำหรับ scheduler)
+
+	// ⚔️ PvP สด
+	GetLiveMatch(playerID uint, matchID string) (*domain.CombatMatch, error)       // ตรวจสิทธิ์ก่อนเปิด WebSocket ของ match
+	ReconnectLiveMatch(playerID uint, matchID string) (*domain.CombatMatch, error) // เชื่อมต่อ WebSocket สำเร็จ (ล้างสถานะหลุดถ้ามี)
+	DisconnectLiveMatch(playerID uint, matchID string) error                       // หลุดทุกการเชื่อมต่อ → เริ่มนับเวลาให้กลับมา
+
+	// 🏆 Ranked PvP
+	CreateRankedMatch(playerID, characterID, opponentCharacterID uint) (*domain.CombatMatch, error) // สร้างดวลสดที่จับคู่จากคิว (ผลแพ้ชนะมีผลกับคะแนน)
 }
 
 // --- Implementation ---
@@ -39,6 +61,8 @@ type combatService struct {
 	pveRepo       pve.PveRepository
 	gameDataRepo  game_data.GameDataRepository
 	deckRepo      deck.DeckRepository
+	pvpRepo       pvp.PvpRepository
+	liveHub       *LiveHub // nil ได้ (ไม่มี WebSocket เช่น simulator)
 }
 
 func NewCombatService(
@@ -49,6 +73,8 @@ func NewCombatService(
 	pveRepo pve.PveRepository,
 	gameDataRepo game_data.GameDataRepository,
 	deckRepo deck.DeckRepository,
+	pvpRepo pvp.PvpRepository,
+	liveHub *LiveHub,
 ) CombatService {
 	return &combatService{
 		appLogger:     appLogger,
@@ -58,11 +84,40 @@ func NewCombatService(
 		pveRepo:       pveRepo,
 		gameDataRepo:  gameDataRepo,
 		deckRepo:      deckRepo,
+		pvpRepo:       pvpRepo,
+		liveHub:       liveHub,
 	}
 }
 
 // CreateMatch คือ Logic การสร้างห้องต่อสู้สำหรับ "โหมดฝึกซ้อม"
 func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*domain.CombatMatch, error) {
+	return s._CreateMatch(playerID, req, false)
+}
+
+// CreateRankedMatch สร้างดวลสด Ranked ระหว่างสองตัวละครที่ matchmaking จับคู่ให้
+// ทั้งสองฝั่งใช้ Defense Deck ของตัวเอง (ไม่ได้ตั้งไว้ = ใช้ได้แค่ธาตุ T0)
+func (s *combatService) CreateRankedMatch(playerID, characterID, opponentCharacterID uint) (*domain.CombatMatch, error) {
+	req := CreateMatchRequest{
+		CharacterID: characterID,
+		MatchType:   string(domain.MatchTypePVP),
+		OpponentID:  &opponentCharacterID,
+		Live:        true,
+	}
+
+	defenseDeck, err := s.deckRepo.FindDefenseDeck(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to load defense deck", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to load player deck")
+	}
+	if defenseDeck != nil {
+		req.DeckID = &defenseDeck.ID
+	}
+
+	return s._CreateMatch(playerID, req, true)
+}
+
+// _CreateMatch สร้าง match ทุกประเภท (ranked = true เฉพาะที่มาจากคิว matchmaking)
+func (s *combatService) _CreateMatch(playerID uint, req CreateMatchRequest, ranked bool) (*domain.CombatMatch, error) {
 	// 1. ตรวจสอบความเป็นเจ้าของตัวละคร
 	playerChar, err := s.characterRepo.FindByID(req.CharacterID)
 	if err != nil || playerChar == nil || playerChar.PlayerID != playerID {
@@ -82,24 +137,17 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 			fmt.Sprintf("character already has an active match: %s", activeMatch.ID.String()))
 	}
 
-	// 3. ดึง "กฎ" การคำนวณ Stat ทั้งหมดมาจาก Cache!
-	hpBaseStr, _ := s.gameDataRepo.GetGameConfigValue("STAT_HP_BASE")
-	hpPerTalentStr, _ := s.gameDataRepo.GetGameConfigValue("STAT_HP_PER_TALENT_S")
-	initBaseStr, _ := s.gameDataRepo.GetGameConfigValue("STAT_INITIATIVE_BASE")
-	initPerTalentStr, _ := s.gameDataRepo.GetGameConfigValue("STAT_INITIATIVE_PER_TALENT_G")
-
-	hpBase, _ := strconv.Atoi(hpBaseStr)
-	hpPerTalent, _ := strconv.Atoi(hpPerTalentStr)
-	initBase, _ := strconv.Atoi(initBaseStr)
-	initPerTalent, _ := strconv.Atoi(initPerTalentStr)
+	// 3. คำนวณ Stat จากแต้มพรสวรรค์ (สูตรเดียวกับหน้าตัวละคร ดู character.CalculateDerivedStats)
+	playerStats := character.CalculateDerivedStats(playerChar, s.gameDataRepo)
 
 	// 4. สร้าง Combatant ของ "ผู้เล่น" (โดยใช้ "กฎ" ที่ดึงมา)
 	playerCombatantID, _ := uuid.NewV7()
 	playerCombatant := &domain.Combatant{
 		ID:          playerCombatantID,
 		CharacterID: &playerChar.ID,
-		Initiative:  initBase + (playerChar.TalentG * initPerTalent),
-		CurrentHP:   hpBase + (playerChar.TalentS * hpPerTalent),
+		Team:        domain.MatchSidePlayer,
+		Initiative:  playerStats.Initiative,
+		CurrentHP:   playerStats.MaxHP,
 		CurrentMP:   playerChar.CurrentMP,
 		CurrentAP:   0,
 	}
@@ -132,6 +180,10 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 	var combatants []*domain.Combatant
 	combatants = append(combatants, playerCombatant)
 
+	// เก็บข้อมูลตัวละคร/ศัตรูที่โหลดมาไว้สำหรับทำ replay snapshot
+	snapshotCharacters := map[uint]*domain.Character{playerChar.ID: playerChar}
+	snapshotEnemies := make(map[uint]*domain.Enemy)
+
 	switch req.MatchType {
 	case "TRAINING":
 		// โหมดฝึกซ้อม - เลือกศัตรูเอง
@@ -145,16 +197,8 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 				return nil, apperrors.NotFoundError(fmt.Sprintf("enemy with id %d not found", enemyInfo.EnemyID))
 			}
 
-			enemyCombatantID, _ := uuid.NewV7()
-			enemyCombatant := &domain.Combatant{
-				ID:         enemyCombatantID,
-				EnemyID:    &enemyData.ID,
-				Initiative: enemyData.Initiative,
-				CurrentHP:  enemyData.MaxHP,
-				CurrentMP:  9999,
-				CurrentAP:  0,
-			}
-			combatants = append(combatants, enemyCombatant)
+			combatants = append(combatants, s._NewEnemyCombatant(enemyData, nil, domain.MatchSideEnemy))
+			snapshotEnemies[enemyData.ID] = enemyData
 		}
 
 	case "STORY":
@@ -163,28 +207,49 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 			return nil, apperrors.InvalidFormatError("stage_id is required for STORY mode", nil)
 		}
 
-		// TODO: ต้องเพิ่ม method ใน PveRepository ก่อน
-		// stageData, err := s.pveRepo.FindStageByID(*req.StageID)
-		// stageEnemies, err := s.pveRepo.FindStageEnemiesByStageID(*req.StageID)
-		// for _, stageEnemy := range stageEnemies {
-		//     enemyData, err := s.enemyRepo.FindByID(stageEnemy.EnemyID)
-		//     enemyCombatantID, _ := uuid.NewV7()
-		//     enemyCombatant := &domain.Combatant{
-		//         ID:         enemyCombatantID,
-		//         EnemyID:    &enemyData.ID,
-		//         Initiative: enemyData.Initiative,
-		//         CurrentHP:  enemyData.MaxHP,
-		//         CurrentMP:  9999,
-		//         CurrentAP:  0,
-		//     }
-		//     combatants = append(combatants, enemyCombatant)
-		// }
-
-		s.appLogger.Warn("STORY mode not fully implemented yet", "stage_id", *req.StageID)
-		return nil, apperrors.New(501, "NOT_IMPLEMENTED", "STORY mode is not fully implemented yet")
+		stageData, err := s.pveRepo.FindStageByID(*req.StageID)
+		if err != nil {
+			s.appLogger.Error("Failed to load stage", err, "stage_id", *req.StageID)
+			return nil, apperrors.SystemError("failed to load stage")
+		}
+		if stageData == nil {
+			return nil, apperrors.NotFoundError(fmt.Sprintf("stage with id %d not found", *req.StageID))
+		}
+		if err := s._CheckStageUnlocked(playerChar, stageData.ID); err != nil {
+			return nil, err
+		}
+
+		stageEnemies, err := s.pveRepo.FindStageEnemiesByStageID(stageData.ID)
+		if err != nil {
+			s.appLogger.Error("Failed to load stage enemies", err, "stage_id", stageData.ID)
+			return nil, apperrors.SystemError("failed to load stage enemies")
+		}
+		if len(stageEnemies) == 0 {
+			return nil, apperrors.New(422, "STAGE_HAS_NO_ENEMIES", fmt.Sprintf("stage %d has no enemies configured", stageData.ID))
+		}
+
+		// สร้างศัตรูตามลำดับ Position (repository เรียงมาให้แล้ว)
+		for _, stageEnemy := range stageEnemies {
+			enemyData, err := s.enemyRepo.FindByID(stageEnemy.EnemyID)
+			if err != nil || enemyData == nil {
+				return nil, apperrors.NotFoundError(fmt.Sprintf("enemy with id %d not found", stageEnemy.EnemyID))
+			}
+
+			combatants = append(combatants, s._NewEnemyCombatant(enemyData, stageEnemy.LevelOverride, domain.MatchSideEnemy))
+			snapshotEnemies[enemyData.ID] = enemyData
+		}
+
+		s.appLogger.Info("STORY match created",
+			"player_char_id", req.CharacterID,
+			"stage_id", stageData.ID,
+			"enemy_count", len(stageEnemies),
+		)
 
 	case "PVP":
-		// PvP - ต่อสู้กับผู้เล่นอื่น
+		// PvP - ต่อสู้กับตัวละครของผู้เล่นอื่น (ใช้ Defense Deck ของเจ้าของ)
+		// - Live = false: async, AI เล่นแทนเจ้าของตัวละคร (ท้าผ่าน POST /combat ได้แบบนี้เท่านั้น)
+		// - Live = true: ดวลสด, เจ้าของตัวละครต่อ WebSocket แล้วส่ง action เอง
+		//   มาจาก CreateRankedMatch เท่านั้น (ทั้งสองฝ่ายเข้าคิวเอง = ยินยอมดวล)
 		if req.OpponentID == nil {
 			return nil, apperrors.InvalidFormatError("opponent_id is required for PVP mode", nil)
 		}
@@ -193,45 +258,80 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 		if err != nil || opponentChar == nil {
 			return nil, apperrors.NotFoundError(fmt.Sprintf("opponent character with id %d not found", *req.OpponentID))
 		}
+		if opponentChar.PlayerID == playerID {
+			return nil, apperrors.InvalidFormatError("cannot challenge your own character", nil)
+		}
+		if req.Live {
+			// ดวลสด: ฝ่ายตรงข้ามต้องว่าง (เล่นได้ทีละ match เหมือนผู้ท้า)
+			opponentActive, err := s.combatRepo.FindPlayerActiveMatch(opponentChar.ID)
+			if err != nil {
+				s.appLogger.Error("Failed to check opponent active match", err, "character_id", opponentChar.ID)
+				return nil, apperrors.SystemError("failed to check active match")
+			}
+			if opponentActive != nil {
+				return nil, apperrors.New(409, "OPPONENT_BUSY", "opponent character is already in another match")
+			}
+		}
 
 		// สร้าง Combatant ของฝ่ายตรงข้าม
+		opponentStats := character.CalculateDerivedStats(opponentChar, s.gameDataRepo)
 		opponentCombatantID, _ := uuid.NewV7()
 		opponentCombatant := &domain.Combatant{
-			ID:          opponentCombatantID,
-			CharacterID: &opponentChar.ID,
-			Initiative:  initBase + (opponentChar.TalentG * initPerTalent),
-			CurrentHP:   hpBase + (opponentChar.TalentS * hpPerTalent),
-			CurrentMP:   opponentChar.CurrentMP,
-			CurrentAP:   0,
+			ID:             opponentCombatantID,
+			CharacterID:    &opponentChar.ID,
+			Team:           domain.MatchSideEnemy,
+			IsOpponent:     true,
+			IsAIControlled: !req.Live,
+			Initiative:     opponentStats.Initiative,
+			CurrentHP:      opponentStats.MaxHP,
+			CurrentMP:      opponentChar.CurrentMP,
+			CurrentAP:      0,
 		}
 
-		// TODO: โหลด deck ของฝ่ายตรงข้าม
-		// if opponentChar.SelectedDeckID != nil { ... }
+		// โหลด Defense Deck ของฝ่ายตรงข้าม (ไม่ได้ตั้งไว้ = ใช้ได้แค่ธาตุ T0)
+		if err := s._LoadDefenseDeck(opponentCombatant); err != nil {
+			return nil, apperrors.SystemError("failed to load opponent deck")
+		}
+		if opponentCombatant.IsAIControlled {
+			if err := s._PrepareGhostSpells(opponentCombatant, opponentChar); err != nil {
+				s.appLogger.Error("Failed to resolve opponent spells", err, "character_id", opponentChar.ID)
+				return nil, apperrors.SystemError("failed to resolve opponent spells")
+			}
+		}
 
 		combatants = append(combatants, opponentCombatant)
+		snapshotCharacters[opponentChar.ID] = opponentChar
 
 		s.appLogger.Info("PVP match created",
 			"player_char_id", req.CharacterID,
 			"opponent_char_id", *req.OpponentID,
+			"live", req.Live,
+			"defense_deck_size", len(opponentCombatant.Deck),
 		)
 
 	default:
 		return nil, apperrors.InvalidFormatError("unsupported match type", nil)
 	}
 
-	// 7. ตัดสินลำดับเทิร์น และแจก AP เริ่มต้น
-	apPerTurnStr, _ := s.gameDataRepo.GetGameConfigValue("COMBAT_AP_PER_TURN")
-	apPerTurn, _ := strconv.Atoi(apPerTurnStr)
-	var firstTurnCombatant *domain.Combatant = playerCombatant
+	// 6.1 พันธมิตรฝั่งผู้ท้าชิง (co-op / NPC) ดู match_teams.go
+	allies, err := s._CreateAllyCombatants(playerID, req, snapshotCharacters, snapshotEnemies)
+	if err != nil {
+		return nil, err
+	}
+	combatants = append(combatants, allies...)
+
+	// ธาตุใน Dimensional Seal ของตัวละครทุกตัว ณ ตอนเริ่ม match (เงื่อนไข SELF_HAS_ELEMENT ไม่ต้องอ่าน DB ระหว่างต่อสู้)
 	for _, c := range combatants {
-		if c.Initiative > firstTurnCombatant.Initiative {
-			firstTurnCombatant = c
+		if c.CharacterID == nil {
+			continue
+		}
+		if err := s._LoadSealElements(c); err != nil {
+			s.appLogger.Error("Failed to load dimensional seal", err, "character_id", *c.CharacterID)
+			return nil, apperrors.SystemError("failed to load dimensional seal")
 		}
 	}
-	firstTurnCombatant.CurrentAP = apPerTurn
-	s.appLogger.Info("Granting starting AP", "combatant_id", firstTurnCombatant.ID, "ap", firstTurnCombatant.CurrentAP)
 
-	// 8. ประกอบร่างห้องต่อสู้
+	// 7. ประกอบร่างห้องต่อสู้
 	var modifiersJSON datatypes.JSON
 	if req.Modifiers != nil {
 		jsonBytes, err := json.Marshal(req.Modifiers)
@@ -242,18 +342,101 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 	}
 	matchID, _ := uuid.NewV7()
 	newMatch := &domain.CombatMatch{
-		ID:          matchID,
-		MatchType:   domain.MatchType(req.MatchType),
-		StageID:     req.StageID,
-		Status:      domain.MatchInProgress,
-		Modifiers:   modifiersJSON,
-		TurnNumber:  1,
-		CurrentTurn: firstTurnCombatant.ID,
-		Combatants:  combatants,
-	}
-
-	// 9. บันทึกลง Database
-	return s.combatRepo.CreateMatch(newMatch)
+		ID:         matchID,
+		MatchType:  domain.MatchType(req.MatchType),
+		StageID:    req.StageID,
+		Ranked:     ranked,
+		Status:     domain.MatchInProgress,
+		Modifiers:  modifiersJSON,
+		TurnNumber: 1,
+		Combatants: combatants,
+		RandomSeed: newMatchSeed(),
+	}
+
+	// 8. เทิร์นแรกมาจากคิว Initiative เดียวกับ endTurn (ศัตรู/AI เร็วกว่าก็ได้เล่นก่อน) และแจก AP เริ่มต้น
+	apPerTurnStr, _ := s.gameDataRepo.GetGameConfigValue("COMBAT_AP_PER_TURN")
+	apPerTurn, _ := strconv.Atoi(apPerTurnStr)
+	firstTurnCombatant := s._NextInTurnQueue(newMatch)
+	if firstTurnCombatant == nil {
+		return nil, apperrors.SystemError("no combatant can take the first turn")
+	}
+	newMatch.CurrentTurn = firstTurnCombatant.ID
+	firstTurnCombatant.CurrentAP = apPerTurn
+	s.appLogger.Info("Granting starting AP", "combatant_id", firstTurnCombatant.ID, "ap", firstTurnCombatant.CurrentAP)
+
+	s._StartMatchTimer(newMatch)
+	s._StartTurnTimer(newMatch, firstTurnCombatant)
+
+	// 9. เก็บ snapshot สถานะเริ่มต้นไว้สำหรับ replay
+	snapshot, err := s._BuildMatchSnapshot(newMatch, snapshotCharacters, snapshotEnemies)
+	if err != nil {
+		s.appLogger.Error("Failed to build match snapshot", err, "match_id", matchID)
+		return nil, apperrors.SystemError("failed to create match snapshot")
+	}
+	newMatch.Snapshot = snapshot
+
+	// 10. บันทึกลง Database
+	createdMatch, err := s.combatRepo.CreateMatch(newMatch)
+	if err != nil {
+		return nil, err
+	}
+	if isHumanControlled(firstTurnCombatant) {
+		return createdMatch, nil
+	}
+
+	// 11. ฝ่าย AI ได้เทิร์นแรก → เล่นให้จนถึงเทิร์นผู้เล่น (ยังไม่มี action ของผู้เล่นมาเรียก processAllAITurns)
+	return s._PlayOpeningAITurns(createdMatch.ID.String())
+}
+
+// _PlayOpeningAITurns โหลด match ที่เพิ่งสร้าง (ข้อมูลศัตรู/กฎ AI ครบ) แล้วให้ AI เล่นเทิร์นเปิดจนถึงเทิร์นผู้เล่น
+// replay ไม่ได้บันทึกเทิร์นเหล่านี้เป็น action → VerifyReplay เรียก processAllAITurns จาก snapshot แบบเดียวกัน
+func (s *combatService) _PlayOpeningAITurns(matchID string) (*domain.CombatMatch, error) {
+	var updatedMatch *domain.CombatMatch
+	err := retryOnMatchConflict(func() error {
+		match, err := s.combatRepo.FindMatchByID(matchID)
+		if err != nil {
+			s.appLogger.Error("Failed to reload match for opening AI turns", err, "match_id", matchID)
+			return apperrors.SystemError("failed to start match")
+		}
+		match, err = s.processAllAITurns(match)
+		if err != nil {
+			s.appLogger.Error("Failed to play opening AI turns", err, "match_id", matchID)
+			return apperrors.SystemError("failed to start match")
+		}
+		updatedMatch, err = s.combatRepo.UpdateMatch(match)
+		return err
+	})
+	return updatedMatch, err
+}
+
+// _NewEnemyCombatant สร้าง Combatant จากข้อมูลศัตรู 1 ตัว (team = ENEMY ปกติ, PLAYER = NPC พันธมิตร)
+// levelOverride (จาก StageEnemy) จะปรับ HP ตามส่วนต่างเลเวล: MaxHP × (1 + ENEMY_HP_GROWTH_PER_LEVEL × (level - Enemy.Level))
+func (s *combatService) _NewEnemyCombatant(enemyData *domain.Enemy, levelOverride *int, team domain.MatchSide) *domain.Combatant {
+	level := enemyData.Level
+	maxHP := enemyData.MaxHP
+	if levelOverride != nil && *levelOverride != enemyData.Level {
+		level = *levelOverride
+		growthStr, _ := s.gameDataRepo.GetGameConfigValue("ENEMY_HP_GROWTH_PER_LEVEL")
+		growth, _ := strconv.ParseFloat(growthStr, 64)
+		scale := 1 + growth*float64(level-enemyData.Level)
+		if scale < 0.1 {
+			scale = 0.1 // กันเลเวลต่ำเกินจน HP ติดลบ
+		}
+		maxHP = int(math.Round(float64(enemyData.MaxHP) * scale))
+	}
+
+	enemyCombatantID, _ := uuid.NewV7()
+	return &domain.Combatant{
+		ID:         enemyCombatantID,
+		EnemyID:    &enemyData.ID,
+		Team:       team,
+		Level:      level,
+		MaxHP:      maxHP,
+		Initiative: enemyData.Initiative,
+		CurrentHP:  maxHP,
+		CurrentMP:  9999,
+		CurrentAP:  0,
+	}
 }
 
 // PerformAction - ฟังก์ชันหลักในการประมวลผลการกระทำของผู้เล่นในการต่อสู้
@@ -286,7 +469,19 @@ func (s *combatService) CreateMatch(playerID uint, req CreateMatchRequest) (*dom
 //	Player Cast Spell → Enemy Dies → Check End → Game Over? Yes → Return
 //	Player Cast Spell → Enemy Survives → AI Turn 1 → AI Turn 2 → Back to Player → Return
 //	Player End Turn → AI Turn 1 → Check End → AI Turn 2 → Back to Player → Return
+//
+// 🔒 match ถูกบันทึกโดย request อื่นระหว่างประมวลผล (reconnect, scheduler, forfeit) → ทำใหม่ทั้งหมดจาก match ล่าสุด
 func (s *combatService) PerformAction(playerID uint, matchID string, req PerformActionRequest) (*PerformActionResponse, error) {
+	var response *PerformActionResponse
+	err := retryOnMatchConflict(func() (err error) {
+		response, err = s._PerformAction(playerID, matchID, req)
+		return err
+	})
+	return response, err
+}
+
+// _PerformAction ประมวลผล action 1 รอบตามขั้นตอนของ PerformAction
+func (s *combatService) _PerformAction(playerID uint, matchID string, req PerformActionRequest) (*PerformActionResponse, error) {
 
 	// ════════════════════════════════════════════════════════════════
 	// ขั้นตอนที่ 1: VALIDATION - โหลดและตรวจสอบ Match
@@ -310,37 +505,90 @@ func (s *combatService) PerformAction(playerID uint, matchID string, req Perform
 	// ════════════════════════════════════════════════════════════════
 	// หน้าที่: ยืนยันตัวตนและตรวจสอบว่าถึงเทิร์นของผู้เล่นหรือยัง
 	// Process:
-	//   1. หา combatant ของผู้เล่นจาก match.Combatants (CharacterID != nil)
-	//   2. ตรวจสอบว่า Character.PlayerID ตรงกับ playerID ที่ส่งมา
+	//   1. หา combatant ที่ playerID ควบคุมจาก match.Combatants (PvP สดมีผู้เล่น 2 คน)
+	//   2. ถ้าไม่เจอ = ไม่ใช่เจ้าของตัวละครใน match (หรือเป็นตัวละครที่ AI เล่นแทน)
 	//   3. ตรวจสอบว่า match.CurrentTurn เป็น ID ของ playerCombatant
 	// Error:   - "you are not part of this match" ถ้าไม่ใช่เจ้าของ character
 	//          - "NOT_YOUR_TURN" ถ้ายังไม่ถึงเทิร์น
 	// Note:    ป้องกันการ cheat โดยการส่ง request แทนคนอื่น
 	// ────────────────────────────────────────────────────────────────
-	playerCombatant := s.findPlayerCombatant(match)
-	if playerCombatant == nil || playerCombatant.Character.PlayerID != playerID {
+	playerCombatant := s.findControlledCombatant(match, playerID)
+	if playerCombatant == nil {
 		return nil, apperrors.PermissionDeniedError("you are not part of this match")
 	}
 	if match.CurrentTurn != playerCombatant.ID {
 		return nil, apperrors.New(400, "NOT_YOUR_TURN", "it's not your turn")
 	}
+	if err := s._CheckTurnNotExpired(match); err != nil {
+		return nil, err
+	}
 
 	// ════════════════════════════════════════════════════════════════
-	// ขั้นตอนที่ 3: ACTION EXECUTION - ประมวลผลการกระทำของผู้เล่น
+	// ขั้นตอนที่ 3-4: ACTION EXECUTION + AI PROCESSING
 	// ════════════════════════════════════════════════════════════════
-	// หน้าที่: ประมวลผล action ที่ผู้เล่นเลือก (switch-case ตาม ActionType)
+	// หน้าที่: บันทึก action ที่ยอมรับ (สำหรับ replay) แล้วประมวลผลผ่าน executePlayerAction()
+	// Note:    action ถูกบันทึกใน match.PendingActions และจะถูก save พร้อม match เท่านั้น
+	//          (ถ้า action ล้มเหลว match จะไม่ถูก save → action นี้จะไม่ถูกบันทึก)
+	// ────────────────────────────────────────────────────────────────
+	s.recordAction(match, playerCombatant, req)
+	match, err = s.executePlayerAction(match, playerCombatant, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// ════════════════════════════════════════════════════════════════
+	// ขั้นตอนที่ 5: PERSISTENCE - บันทึกผลลัพธ์และส่งคืน
+	// ════════════════════════════════════════════════════════════════
+	// หน้าที่: บันทึกสถานะล่าสุดของ match ลง database และส่งกลับหา client
+	// Process:
+	//   1. UpdateMatch() → บันทึก match, combatants, effects และ combat events ทั้งหมด
+	//   2. สร้าง PerformActionResponse ที่มี:
+	//      - UpdatedMatch: match ที่อัปเดตแล้ว (รวมข้อมูล AI turns)
+	//      - PerformedAction: request ที่ผู้เล่นส่งมา (เพื่อให้ client ตรวจสอบ)
+	//      - Events: combat events ที่เกิดขึ้นทั้งหมดใน action นี้ (เรียงตาม sequence)
+	//   3. Return response กลับไป
 	//
-	// Note: UpdatedAt จะถูกอัปเดตอัตโนมัติโดย GORM เมื่อเรียก UpdateMatch()
-	//       ใช้ UpdatedAt ในการตรวจจับ match ค้าง (stale detection)
+	// Note:    UpdatedAt จะถูกอัปเดตอัตโนมัติโดย GORM เมื่อเรียก UpdateMatch()
+	//          ใช้ UpdatedAt ในการตรวจจับ match ค้าง (stale detection)
 	//
-	// 3.1) ActionType = "END_TURN"
+	// Output:  PerformActionResponse - ข้อมูลการต่อสู้หลังประมวลผลเสร็จ
+	// Error:   error จาก database (connection, constraint violation, etc.)
+	// ────────────────────────────────────────────────────────────────
+	events := match.PendingEvents
+	loot := match.PendingLoot
+	updatedMatch, err := s.combatRepo.UpdateMatch(match)
+	if err != nil {
+		return nil, err
+	}
+	s._PublishMatchUpdate(updatedMatch, req, events)
+	return &PerformActionResponse{
+		UpdatedMatch:    updatedMatch,
+		PerformedAction: req,
+		Events:          events,
+		Loot:            loot,
+	}, nil
+}
+
+// executePlayerAction ประมวลผล action 1 ครั้งของผู้เล่น แล้วให้ AI เล่นต่อจนกลับมาเป็นเทิร์นผู้เล่น
+// (ไม่มีการอ่าน/เขียน DB → ใช้ร่วมกันระหว่าง PerformAction และ replay simulator)
+func (s *combatService) executePlayerAction(
+	match *domain.CombatMatch,
+	actor *domain.Combatant,
+	req PerformActionRequest,
+) (*domain.CombatMatch, error) {
+	// ════════════════════════════════════════════════════════════════
+	// ขั้นตอนที่ 1: ACTION EXECUTION - ประมวลผลการกระทำของผู้เล่น
+	// ════════════════════════════════════════════════════════════════
+	// หน้าที่: ประมวลผล action ที่ผู้เล่นเลือก (switch-case ตาม ActionType)
+	//
+	// 1.1) ActionType = "END_TURN"
 	//      ├─ endTurn(): เคลียร์ AP ปัจจุบัน, เลื่อน CurrentTurn ไปคนถัดไป
 	//      └─ startNewTurn(): แจก AP ใหม่, ลด duration ของ effects, regen resources
 	//
-	// 3.2) ActionType = "CAST_SPELL"
+	// 1.2) ActionType = "CAST_SPELL"
 	//      ├─ executeCastSpellV2(): ร่ายเวท (ตรวจสอบ MP/AP, หา target, คำนวณดาเมจ, apply effects)
 	//      ├─ checkMatchEndCondition(): ตรวจว่ามีทีมไหนตายหมดหรือยัง
-	//      └─ [EARLY EXIT] ถ้าเกมจบ → UpdateMatch → return ทันที (ไม่ต้องให้ AI เล่นต่อ)
+	//      └─ [EARLY EXIT] ถ้าเกมจบ → return ทันที (ไม่ต้องให้ AI เล่นต่อ)
 	//
 	// Output:  match ที่ถูกแก้ไขแล้ว (CurrentTurn, TurnNumber, Combatants stats)
 	// Error:   - actionErr จาก spell casting (ไม่พอ MP, target ไม่ถูกต้อง, etc.)
@@ -351,27 +599,19 @@ func (s *combatService) PerformAction(playerID uint, matchID string, req Perform
 
 	case "END_TURN":
 		// จบเทิร์นและเริ่มเทิร์นใหม่
-		s.appLogger.Info("Player ended their turn.", "match_id", matchID)
+		s.appLogger.Info("Player ended their turn.", "match_id", match.ID)
 		match = s.endTurn(match)                 // เลื่อน CurrentTurn ไปคนถัดไป
 		match, actionErr = s.startNewTurn(match) // แจก AP, ลด effect duration, regen
 
 	case "CAST_SPELL":
 		// ร่ายเวทโจมตีหรือฟื้นฟู
-		actionErr = s.executeCastSpellV2(playerCombatant, match, req)
+		actionErr = s.executeCastSpellV2(actor, match, req)
 
 		// ✅ ตรวจสอบจบเกมทันทีหลังร่ายเวท (เพราะอาจมีคนตายจนเกมจบ)
 		match = s.checkMatchEndCondition(match)
 		if match.Status != domain.MatchInProgress {
-			// เกมจบแล้ว (PLAYER_WIN หรือ PLAYER_LOSE)
-			// → บันทึกและ return ทันที (ข้ามขั้นตอน AI และ final save)
-			updatedMatch, err := s.combatRepo.UpdateMatch(match)
-			if err != nil {
-				return nil, err
-			}
-			return &PerformActionResponse{
-				UpdatedMatch:    updatedMatch,
-				PerformedAction: req,
-			}, nil
+			// เกมจบแล้ว (PLAYER_WIN หรือ PLAYER_LOSE) → ข้ามขั้นตอน AI
+			return match, nil
 		}
 
 	default:
@@ -384,7 +624,7 @@ func (s *combatService) PerformAction(playerID uint, matchID string, req Perform
 	}
 
 	// ════════════════════════════════════════════════════════════════
-	// ขั้นตอนที่ 4: AI PROCESSING - ให้ AI ทุกตัวเล่นต่อเนื่อง
+	// ขั้นตอนที่ 2: AI PROCESSING - ให้ AI ทุกตัวเล่นต่อเนื่อง
 	// ════════════════════════════════════════════════════════════════
 	// หน้าที่: ประมวลผลเทิร์นของ AI ทุกตัวจนกว่าจะกลับมาเป็นเทิร์นผู้เล่น
 	// Process:
@@ -405,33 +645,7 @@ func (s *combatService) PerformAction(playerID uint, matchID string, req Perform
 	// Output:  match ที่ AI เล่นเสร็จแล้ว (CurrentTurn กลับมาที่ผู้เล่น หรือเกมจบ)
 	// Error:   error จาก AI turn processing (spell casting, resource issue)
 	// ────────────────────────────────────────────────────────────────
-	match, err = s.processAllAITurns(match)
-	if err != nil {
-		return nil, err
-	}
-
-	// ════════════════════════════════════════════════════════════════
-	// ขั้นตอนที่ 5: PERSISTENCE - บันทึกผลลัพธ์และส่งคืน
-	// ════════════════════════════════════════════════════════════════
-	// หน้าที่: บันทึกสถานะล่าสุดของ match ลง database และส่งกลับหา client
-	// Process:
-	//   1. UpdateMatch() → บันทึก match, combatants, effects ทั้งหมด
-	//   2. สร้าง PerformActionResponse ที่มี:
-	//      - UpdatedMatch: match ที่อัปเดตแล้ว (รวมข้อมูล AI turns)
-	//      - PerformedAction: request ที่ผู้เล่นส่งมา (เพื่อให้ client ตรวจสอบ)
-	//   3. Return response กลับไป
-	//
-	// Output:  PerformActionResponse - ข้อมูลการต่อสู้หลังประมวลผลเสร็จ
-	// Error:   error จาก database (connection, constraint violation, etc.)
-	// ────────────────────────────────────────────────────────────────
-	updatedMatch, err := s.combatRepo.UpdateMatch(match)
-	if err != nil {
-		return nil, err
-	}
-	return &PerformActionResponse{
-		UpdatedMatch:    updatedMatch,
-		PerformedAction: req,
-	}, nil
+	return s.processAllAITurns(match)
 }
 
 // ==================== Spell Casting (New Refactored Version) ====================
@@ -519,8 +733,140 @@ func (s *combatService) AbortMatch(matchID string, reason string) error {
 	return nil
 }
 
+// ActionTypeForfeit คือ action ที่บันทึกเมื่อผู้เล่นยอมแพ้ (ให้ replay จบ match ได้เหมือนของจริง)
+const ActionTypeForfeit = "FORFEIT"
+
+// ForfeitMatch ให้ผู้เล่นยอมแพ้ (เช่น หลุดการเชื่อมต่อใน PvP สด) → ฝั่งของผู้เล่นแพ้ อีกฝั่งชนะและได้รางวัลตามปกติ
+// ต่างจาก AbortMatch ที่ระบบยกเลิก match โดยไม่มีผู้ชนะ
+func (s *combatService) ForfeitMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	var match *domain.CombatMatch
+	err := retryOnMatchConflict(func() (err error) {
+		match, err = s._ForfeitMatch(playerID, matchID)
+		return err
+	})
+	return match, err
+}
+
+// _ForfeitMatch ยอมแพ้ 1 รอบจาก match ล่าสุด (ชนกับ request อื่น → ForfeitMatch เรียกซ้ำ)
+func (s *combatService) _ForfeitMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	match, err := s.combatRepo.FindMatchByID(matchID)
+	if err != nil {
+		return nil, apperrors.NotFoundError("match not found")
+	}
+	if match.Status != domain.MatchInProgress {
+		return nil, apperrors.New(400, "MATCH_FINISHED", "this match has already finished")
+	}
+
+	actor := s.findControlledCombatant(match, playerID)
+	if actor == nil {
+		return nil, apperrors.PermissionDeniedError("you are not part of this match")
+	}
+
+	req := PerformActionRequest{ActionType: ActionTypeForfeit}
+	s.recordAction(match, actor, req)
+	s._EndMatchByForfeit(match, actor)
+
+	events := match.PendingEvents
+	updatedMatch, err := s.combatRepo.UpdateMatch(match)
+	if err != nil {
+		return nil, err
+	}
+	s._PublishMatchUpdate(updatedMatch, req, events)
+
+	s.appLogger.Info("Match forfeited",
+		"match_id", matchID,
+		"player_id", playerID,
+		"combatant_id", actor.ID,
+	)
+	return updatedMatch, nil
+}
+
+// _EndMatchByForfeit จบ match โดยให้ฝั่งของผู้ยอมแพ้เป็นฝ่ายแพ้ และลงโทษผู้ยอมแพ้ตามโหมด
+// (Ranked เสียคะแนนผ่าน _EndMatch อยู่แล้ว, EXP ที่หักดู _ApplyForfeitPenalty)
+func (s *combatService) _EndMatchByForfeit(match *domain.CombatMatch, actor *domain.Combatant) {
+	expPenalty := s._ApplyForfeitPenalty(match, actor)
+
+	forfeitEvent := newCombatEvent(domain.CombatEventForfeited, actor, nil, expPenalty)
+	forfeitEvent.Details = eventDetails(map[string]interface{}{"exp_penalty": expPenalty})
+	s.recordEvent(match, forfeitEvent)
+
+	forfeitedByEnemySide := isEnemySide(actor)
+	s._EndMatch(match, !forfeitedByEnemySide, forfeitedByEnemySide, domain.MatchEndForfeit)
+	if actor.CharacterID == nil {
+		return // ศัตรูไม่มีสรุปผลรายตัวละคร
+	}
+	if result := matchResultFor(match, *actor.CharacterID); result != nil {
+		result.ExpPenalty = expPenalty
+	}
+}
+
+// _ApplyForfeitPenalty หัก EXP ของผู้ยอมแพ้ตาม FORFEIT_EXP_PENALTY_<MATCH_TYPE> (ไม่ต่ำกว่า 0) คืนจำนวนที่หักจริง
+// ตัวละครถูกบันทึกพร้อมการจบ match ใน UpdateMatch (save ไม่สำเร็จ = ไม่ถูกหัก → ยอมแพ้ใหม่ก็หักครั้งเดียว)
+func (s *combatService) _ApplyForfeitPenalty(match *domain.CombatMatch, actor *domain.Combatant) int {
+	if actor.CharacterID == nil {
+		return 0
+	}
+	penaltyStr, _ := s.gameDataRepo.GetGameConfigValue("FORFEIT_EXP_PENALTY_" + string(match.MatchType))
+	penalty, err := strconv.Atoi(penaltyStr)
+	if err != nil || penalty <= 0 {
+		return 0
+	}
+
+	character := s._PendingCharacter(match, *actor.CharacterID)
+	if character == nil {
+		return 0
+	}
+	if penalty > character.Exp {
+		penalty = character.Exp
+	}
+	if penalty == 0 {
+		return 0
+	}
+	character.Exp -= penalty
+	s.appLogger.Info("applied forfeit penalty",
+		"match_id", match.ID,
+		"character_id", character.ID,
+		"exp_penalty", penalty,
+	)
+	return penalty
+}
+
 // GetPlayerActiveMatch ตรวจสอบว่าผู้เล่นมี match ที่กำลังเล่นอยู่หรือไม่
 // ใช้ก่อนสร้าง match ใหม่ เพื่อป้องกันการเปิดหลายห้องพร้อมกัน
 func (s *combatService) GetPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error) {
 	return s.combatRepo.FindPlayerActiveMatch(characterID)
 }
+
+// GetActiveMatch คืน match ที่ตัวละครของผู้เล่นกำลังเล่นอยู่ พร้อมสถานะเต็ม (ให้ client ที่หลุดกลับมาเล่นต่อได้)
+func (s *combatService) GetActiveMatch(playerID, characterID uint) (*domain.CombatMatch, error) {
+	char, err := s.characterRepo.FindByID(characterID)
+	if err != nil || char == nil || char.PlayerID != playerID {
+		return nil, apperrors.PermissionDeniedError("character not found or you are not the owner")
+	}
+
+	activeMatch, err := s.combatRepo.FindPlayerActiveMatch(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to check active match", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to check active match")
+	}
+	if activeMatch == nil {
+		return nil, apperrors.NotFoundError("character has no active match")
+	}
+
+	// FindPlayerActiveMatch โหลดข้อมูลไม่ครบ (ไม่มี Deck/Abilities) → โหลดใหม่ทั้ง match
+	return s.GetMatch(playerID, activeMatch.ID.String())
+}
+
+// GetMatch คืนสถานะของ match ที่ผู้เล่นมีตัวละครอยู่ (รวมฝั่งที่ถูกท้าใน PvP async)
+func (s *combatService) GetMatch(playerID uint, matchID string) (*domain.CombatMatch, error) {
+	match, err := s.combatRepo.FindMatchByID(matchID)
+	if err != nil {
+		return nil, apperrors.NotFoundError("match not found")
+	}
+	for _, c := range match.Combatants {
+		if c.Character != nil && c.Character.PlayerID == playerID {
+			return match, nil
+		}
+	}
+	return nil, apperrors.PermissionDeniedError("you are not part of this match")
+}
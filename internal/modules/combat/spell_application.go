@@ -4,7 +4,6 @@ package combat
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"sage-of-elements-backend/internal/domain"
 
 	"github.com/gofrs/uuid"
@@ -17,8 +16,9 @@ type AppliedEffect struct {
 	TargetID    uuid.UUID   `json:"target_id"`
 	FinalValue  float64     `json:"final_value"`
 	Evaded      bool        `json:"evaded"`
-	Absorbed    float64     `json:"absorbed"`     // shield absorption
-	ActualValue float64     `json:"actual_value"` // ค่าที่เกิดขึ้นจริงหลัง shield/defense
+	Absorbed    float64     `json:"absorbed"`              // shield absorption
+	ActualValue float64     `json:"actual_value"`          // ค่าที่เกิดขึ้นจริงหลัง shield/defense
+	Retaliation float64     `json:"retaliation,omitempty"` // ดาเมจที่เป้าหมายสะท้อนกลับหาผู้ร่าย (BUFF_RETALIATION)
 	Details     interface{} `json:"details,omitempty"`
 }
 
@@ -30,55 +30,71 @@ type EffectApplicationResult struct {
 	TotalAbsorbed  float64         `json:"total_absorbed"`
 	EffectsApplied int             `json:"effects_applied"`
 	EffectsEvaded  int             `json:"effects_evaded"`
+	EffectsSkipped int             `json:"effects_skipped"` // effect ที่เงื่อนไข (ConditionType) ไม่ผ่าน
 	Errors         []string        `json:"errors,omitempty"`
 }
 
 // ApplyCalculatedEffects เป็น Step 4 ของการร่ายเวท
-// รับผิดชอบ apply effect แต่ละตัวพร้อมบันทึกผลลัพธ์
+// รับผิดชอบ apply effect แต่ละตัวกับเป้าหมาย 1 ตัวพร้อมบันทึกผลลัพธ์ (เวทหมู่เรียกครั้งละเป้า)
 func (s *combatService) ApplyCalculatedEffects(
+	match *domain.CombatMatch,
 	caster *domain.Combatant,
-	target *domain.Combatant,
+	spellTarget *SpellTarget,
 	spell *domain.Spell,
 	initialValues EffectValueMap,
 	modifierCtx *ModifierContext,
 ) (*EffectApplicationResult, error) {
 
+	target := spellTarget.Combatant
 	s.appLogger.Info("💥 STEP 4: Applying calculated effects",
 		"spell_id", spell.ID,
+		"target_id", target.ID,
 		"effect_count", len(initialValues),
 		"combined_modifier", modifierCtx.CombinedMod,
+		"damage_mod", spellTarget.DamageMod,
 	)
 
 	result := &EffectApplicationResult{
 		AppliedEffects: make([]AppliedEffect, 0),
 	}
 
-	// Loop แต่ละ effect
-	for effectID, initialValue := range initialValues {
-		// หา spellEffect data
-		var spellEffect *domain.SpellEffect
-		for _, se := range spell.Effects {
-			if se.EffectID == effectID {
-				spellEffect = se
-				break
-			}
+	// Loop แต่ละ effect ตามลำดับใน spell.Effects
+	// ⚠️ ห้าม range บน initialValues (map) ตรงๆ เพราะลำดับไม่แน่นอน → ผลสุ่ม (evasion) จะ replay ไม่ได้
+	appliedIDs := make(map[uint]bool, len(initialValues))
+	for _, spellEffect := range spell.Effects {
+		effectID := spellEffect.EffectID
+		initialValue, ok := initialValues[effectID]
+		if !ok || appliedIDs[effectID] {
+			continue
 		}
 
-		if spellEffect == nil {
-			errMsg := fmt.Sprintf("SpellEffect not found for ID %d", effectID)
-			s.appLogger.Warn(errMsg)
-			result.Errors = append(result.Errors, errMsg)
+		// ตรวจเงื่อนไขของ effect (ไม่ผ่าน = ข้าม แต่ SpellEffect แถวอื่นที่ใช้ effect เดียวกันยังมีสิทธิ์)
+		if !s._EvaluateEffectCondition(match, caster, target, spell, spellEffect) {
+			s.appLogger.Debug("Effect condition not met, skipping",
+				"effect_id", effectID,
+				"condition", spellEffect.ConditionType,
+			)
+			result.EffectsSkipped++
 			continue
 		}
-
-		// คำนวณค่าสุดท้าย
-		finalValue := initialValue * modifierCtx.CombinedMod
+		appliedIDs[effectID] = true
 
 		// หา final target (สำหรับ SYNERGY_BUFF ต้องเปลี่ยนเป็น caster)
+		// เวทหมู่: effect ที่ย้ายไปลงผู้ร่ายทำงานครั้งเดียวตอนเป้าหลัก
 		finalTarget := s._DetermineEffectTarget(caster, target, spell, effectID)
+		if finalTarget != target && !spellTarget.Primary {
+			continue
+		}
+
+		// คำนวณค่าสุดท้าย (ดาเมจของเป้ารองในเวทหมู่ถูกลดตาม DamageMod)
+		finalValue := initialValue * modifierCtx.CombinedMod
+		if spellTarget.DamageMod != 1.0 && s._IsDamageEffect(effectID) {
+			finalValue *= spellTarget.DamageMod
+		}
 
 		// Apply effect
 		appliedEffect, err := s._ApplySpecificEffect(
+			match,
 			caster,
 			finalTarget,
 			spell,
@@ -117,6 +133,7 @@ func (s *combatService) ApplyCalculatedEffects(
 		"total_damage", result.TotalDamage,
 		"total_healing", result.TotalHealing,
 		"evaded", result.EffectsEvaded,
+		"skipped", result.EffectsSkipped,
 	)
 
 	return result, nil
@@ -152,8 +169,15 @@ func (s *combatService) _DetermineEffectTarget(
 	}
 }
 
+// _IsDamageEffect เช็คว่า effect เป็นประเภท DAMAGE หรือไม่
+func (s *combatService) _IsDamageEffect(effectID uint) bool {
+	effectInfo, err := s.gameDataRepo.FindEffectByID(effectID)
+	return err == nil && effectInfo != nil && effectInfo.Type == domain.EffectTypeDamage
+}
+
 // _ApplySpecificEffect แยกประเภทของ effect แล้วเรียก sub-function ที่เหมาะสม
 func (s *combatService) _ApplySpecificEffect(
+	match *domain.CombatMatch,
 	caster *domain.Combatant,
 	target *domain.Combatant,
 	spell *domain.Spell,
@@ -177,7 +201,7 @@ func (s *combatService) _ApplySpecificEffect(
 	// Switch ตาม effect type
 	switch effectInfo.Type {
 	case domain.EffectTypeDamage:
-		return s.__ApplyDamageEffect(caster, target, spell, finalValue)
+		return s.__ApplyDamageEffect(match, caster, target, spell, finalValue)
 
 	case domain.EffectTypeHeal:
 		return s.__ApplyHealEffect(target, finalValue)
@@ -192,7 +216,7 @@ func (s *combatService) _ApplySpecificEffect(
 		duration := s._CalculateDurationBonus(caster, baseDuration)
 		return s.__ApplyBuffEffect(target, effectID, finalValue, duration)
 
-	case domain.EffectTypeDebuff:
+	case domain.EffectTypeDebuff, domain.EffectTypeDebuffCC:
 		baseDuration := int(spellEffect.DurationInTurns)
 		duration := s._CalculateDurationBonus(caster, baseDuration)
 		return s.__ApplyDebuffEffect(caster, target, effectID, finalValue, duration, spellEffect)
@@ -218,6 +242,7 @@ func (s *combatService) _ApplySpecificEffect(
 
 // __ApplyDamageEffect ทำ damage พร้อมเช็ค evasion, shield, defense
 func (s *combatService) __ApplyDamageEffect(
+	match *domain.CombatMatch,
 	caster *domain.Combatant,
 	target *domain.Combatant,
 	spell *domain.Spell,
@@ -235,7 +260,7 @@ func (s *combatService) __ApplyDamageEffect(
 	}
 
 	// 1. Check Evasion
-	if s._CheckEvasion(target) {
+	if s._CheckEvasion(match, target) {
 		result.Evaded = true
 		s.appLogger.Info("Attack EVADED!", "caster", caster.ID, "target", target.ID)
 		return result, nil
@@ -260,6 +285,11 @@ func (s *combatService) __ApplyDamageEffect(
 
 	result.ActualValue = float64(oldHP - target.CurrentHP)
 
+	// 6. Retaliation: การโจมตีที่โดน (HP หรือ Shield) ของคนอื่นโดนสะท้อนกลับ
+	if (result.ActualValue > 0 || absorbed > 0) && caster.ID != target.ID {
+		result.Retaliation = s._ApplyRetaliation(caster, target)
+	}
+
 	s.appLogger.Info("Damage applied",
 		"target_id", target.ID,
 		"raw_damage", damage,
@@ -268,11 +298,35 @@ func (s *combatService) __ApplyDamageEffect(
 		"final_damage", finalDamage,
 		"hp_before", oldHP,
 		"hp_after", target.CurrentHP,
+		"retaliation", result.Retaliation,
 	)
 
 	return result, nil
 }
 
+// _ApplyRetaliation สะท้อนดาเมจจาก BUFF_RETALIATION (2203) ของเป้าหมายกลับหาผู้ร่าย คืนดาเมจที่ลงจริง
+func (s *combatService) _ApplyRetaliation(caster *domain.Combatant, target *domain.Combatant) float64 {
+	if len(target.ActiveEffects) == 0 {
+		return 0
+	}
+	var activeEffects []domain.ActiveEffect
+	if err := json.Unmarshal(target.ActiveEffects, &activeEffects); err != nil {
+		return 0
+	}
+	for _, effect := range activeEffects {
+		if effect.EffectID != 2203 || effect.Value <= 0 { // BUFF_RETALIATION
+			continue
+		}
+		hpBefore := caster.CurrentHP
+		caster.CurrentHP -= effect.Value
+		if caster.CurrentHP < 0 {
+			caster.CurrentHP = 0
+		}
+		return float64(hpBefore - caster.CurrentHP)
+	}
+	return 0
+}
+
 // __ApplyHealEffect ฟื้นฟู HP
 func (s *combatService) __ApplyHealEffect(
 	target *domain.Combatant,
@@ -408,6 +462,9 @@ func (s *combatService) __ApplyDebuffEffect(
 	}
 
 	s._AddActiveEffect(target, newEffect)
+	if effectID == 4101 { // DEBUFF_SLOW: ลำดับเทิร์นในรอบนี้เปลี่ยนทันที
+		s.recalculateStats(target)
+	}
 
 	s.appLogger.Info("Debuff applied",
 		"target_id", target.ID,
@@ -510,7 +567,7 @@ func (s *combatService) __ApplyMpDamageEffect(
 // ==================== Sub-Helper Functions ====================
 
 // _CheckEvasion ตรวจสอบว่า target หลบได้หรือไม่
-func (s *combatService) _CheckEvasion(target *domain.Combatant) bool {
+func (s *combatService) _CheckEvasion(match *domain.CombatMatch, target *domain.Combatant) bool {
 	if target.ActiveEffects == nil {
 		return false
 	}
@@ -525,7 +582,7 @@ func (s *combatService) _CheckEvasion(target *domain.Combatant) bool {
 		if effect.EffectID == 2201 { // BUFF_EVASION
 			evasionChance := effect.Value
 			if evasionChance > 0 {
-				roll := rand.Intn(100)
+				roll := s.rollIntn(match, 100)
 				s.appLogger.Info("Evasion check", "chance", evasionChance, "roll", roll)
 				return roll < evasionChance
 			}
@@ -3,7 +3,6 @@ package combat
 
 import (
 	"fmt"
-	"math/rand"
 	"sage-of-elements-backend/internal/domain"
 )
 
@@ -307,8 +306,8 @@ func (s *combatService) _GetPowerModifier(castingMode string) float64 {
 // _ShouldTriggerMultiCast ตรวจสอบว่าควร trigger Multi-Cast หรือไม่
 // โดยใช้ Talent G และ match type เพื่อคำนวณโอกาส
 func (s *combatService) _ShouldTriggerMultiCast(
+	match *domain.CombatMatch,
 	caster *domain.Combatant,
-	matchType string,
 ) (bool, float64) {
 	// ถ้าไม่มี Character (เป็น Enemy) ไม่สามารถใช้ Multi-Cast ได้
 	if caster.Character == nil {
@@ -333,10 +332,10 @@ func (s *combatService) _ShouldTriggerMultiCast(
 
 	// ดึง Cap ตาม Match Type
 	var capConfigKey string
-	switch matchType {
-	case "PVP":
+	switch match.MatchType {
+	case domain.MatchTypePVP:
 		capConfigKey = "TALENT_G_MULTICAST_CAP_PVP"
-	case "STORY":
+	case domain.MatchTypeStory:
 		capConfigKey = "TALENT_G_MULTICAST_CAP_STORY"
 	default: // TRAINING
 		capConfigKey = "TALENT_G_MULTICAST_CAP_TRAINING"
@@ -356,7 +355,7 @@ func (s *combatService) _ShouldTriggerMultiCast(
 	}
 
 	// สุ่ม (0-100)
-	roll := rand.Float64() * 100
+	roll := s.rollFloat64(match) * 100
 	triggered := roll < finalChance
 
 	s.appLogger.Debug("Multi-Cast chance calculated",
@@ -13,9 +13,11 @@ import (
 // Flow:
 // 1. PrepareAndValidateCast → ตรวจสอบ + หักทรัพยากร
 // 2. CalculateInitialEffectValues → คำนวณค่าพื้นฐาน
-// 3. CalculateCombinedModifiers → คำนวณ modifier
-// 4. ApplyCalculatedEffects → ประยุกต์ effect
+// 3. CalculateCombinedModifiers → คำนวณ modifier (แยกต่อเป้าหมาย)
+// 4. ApplyCalculatedEffects → ประยุกต์ effect (แยกต่อเป้าหมาย, เวทหมู่มีหลายเป้า ดู spell_targeting.go)
 // 5. SaveCombatState → บันทึกสถานะ (auto-saved ใน match update)
+//
+// ทุกขั้นตอนจะบันทึก CombatEvent (CAST, DAMAGE, EVADED, MULTI_CAST ฯลฯ) ลง match.PendingEvents
 func (s *combatService) ExecuteSpellCast(
 	match *domain.CombatMatch,
 	caster *domain.Combatant,
@@ -39,6 +41,24 @@ func (s *combatService) ExecuteSpellCast(
 		return err
 	}
 
+	castEvent := newCombatEvent(domain.CombatEventCast, prepResult.Caster, prepResult.Target, 0)
+	castEvent.SpellID = &prepResult.Spell.ID
+	castDetails := map[string]interface{}{
+		"casting_mode": castingMode,
+		"ap_cost":      prepResult.FinalAPCost,
+		"mp_cost":      prepResult.FinalMPCost,
+	}
+	if len(prepResult.Targets) > 1 {
+		castDetails["target_ids"] = spellTargetIDs(prepResult.Targets)
+	}
+	// MXP ของศาสตร์ถูกคิดตอนร่ายสำเร็จ แล้วบันทึกเข้าตัวละครตอนจบ match (ดู mastery_progress.go)
+	if mxp := s._CalculateCastMxp(match, prepResult.Caster, castingMode); mxp > 0 {
+		castDetails["mastery_id"] = prepResult.Spell.MasteryID
+		castDetails["mxp"] = mxp
+	}
+	castEvent.Details = eventDetails(castDetails)
+	s.recordEvent(match, castEvent)
+
 	// ==================== STEP 2: Calculate Initial Values ====================
 	initialValues, err := s.CalculateInitialEffectValues(prepResult.Spell, prepResult.Caster)
 	if err != nil {
@@ -46,31 +66,14 @@ func (s *combatService) ExecuteSpellCast(
 		return err
 	}
 
-	// ==================== STEP 3: Calculate Modifiers ====================
-	// NOTE: คำนวณ modifier ครั้งเดียวสำหรับทุก effect (เพื่อความเร็ว)
-	// ถ้ามี effect ที่ต้องการ modifier แยก ให้ปรับใน Step 4
-	modifierCtx, err := s.CalculateCombinedModifiers(
-		prepResult.Caster,
-		prepResult.Target,
-		prepResult.Spell,
-		prepResult.PowerModifier,
-		0, // effect ID 0 = ใช้ modifier ทั่วไป
-	)
-	if err != nil {
-		s.appLogger.Error("STEP 3 failed: Modifier calculation error", err)
-		return err
-	}
+	// ประวัติการร่าย (LAST_SPELL_WAS / COMBO_COUNT_IS) อัปเดตหลังร่ายเสร็จ รวม Multi-Cast ด้วย
+	// เงื่อนไขของ effect ในการร่ายครั้งนี้จึงเห็นประวัติก่อนหน้าเสมอ
+	defer s._RecordSpellHistory(prepResult.Caster, prepResult.Spell)
 
-	// ==================== STEP 4: Apply Effects ====================
-	applicationResult, err := s.ApplyCalculatedEffects(
-		prepResult.Caster,
-		prepResult.Target,
-		prepResult.Spell,
-		initialValues,
-		modifierCtx,
-	)
+	// ==================== STEP 3-4: Modifiers & Apply Effects (ต่อเป้าหมาย) ====================
+	applicationResult, err := s._ApplySpellToTargets(match, prepResult, initialValues)
 	if err != nil {
-		s.appLogger.Error("STEP 4 failed: Effect application error", err)
+		s.appLogger.Error("STEP 3-4 failed: Effect application error", err)
 		return err
 	}
 
@@ -95,7 +98,7 @@ func (s *combatService) ExecuteSpellCast(
 	)
 
 	// ==================== STEP 6: Check Multi-Cast (Improvisation - Talent G) ====================
-	triggered, chance := s._ShouldTriggerMultiCast(prepResult.Caster, string(match.MatchType))
+	triggered, chance := s._ShouldTriggerMultiCast(match, prepResult.Caster)
 	if triggered {
 		s.appLogger.Info("🎲 MULTI-CAST TRIGGERED!",
 			"caster_id", prepResult.Caster.ID,
@@ -103,6 +106,11 @@ func (s *combatService) ExecuteSpellCast(
 			"spell_id", spellID,
 		)
 
+		multiCastEvent := newCombatEvent(domain.CombatEventMultiCast, prepResult.Caster, prepResult.Target, 0)
+		multiCastEvent.SpellID = &prepResult.Spell.ID
+		multiCastEvent.Details = eventDetails(map[string]interface{}{"chance": chance})
+		s.recordEvent(match, multiCastEvent)
+
 		// ร่ายซ้ำโดยใช้ค่าเดิมทั้งหมด (แต่ไม่หัก AP/MP อีก)
 		// ⚠️ Important: ต้อง recalculate ทุกอย่างเพราะ target อาจมีสถานะเปลี่ยน
 		multicastInitialValues, err := s.CalculateInitialEffectValues(prepResult.Spell, prepResult.Caster)
@@ -112,25 +120,7 @@ func (s *combatService) ExecuteSpellCast(
 			return nil
 		}
 
-		multicastModifierCtx, err := s.CalculateCombinedModifiers(
-			prepResult.Caster,
-			prepResult.Target,
-			prepResult.Spell,
-			prepResult.PowerModifier,
-			0,
-		)
-		if err != nil {
-			s.appLogger.Warn("Multi-Cast: Failed to calculate modifiers", "error", err)
-			return nil
-		}
-
-		multicastResult, err := s.ApplyCalculatedEffects(
-			prepResult.Caster,
-			prepResult.Target,
-			prepResult.Spell,
-			multicastInitialValues,
-			multicastModifierCtx,
-		)
+		multicastResult, err := s._ApplySpellToTargets(match, prepResult, multicastInitialValues)
 		if err != nil {
 			s.appLogger.Warn("Multi-Cast: Failed to apply effects", "error", err)
 			return nil
@@ -145,3 +135,51 @@ func (s *combatService) ExecuteSpellCast(
 
 	return nil
 }
+
+// _ApplySpellToTargets คิด modifier (STEP 3) แล้ว apply effect (STEP 4) กับทุกเป้าหมายของการร่าย
+// event ของแต่ละเป้าถูกบันทึกต่อกันตามลำดับเป้า และคืนผลรวมของทุกเป้า
+func (s *combatService) _ApplySpellToTargets(
+	match *domain.CombatMatch,
+	prepResult *SpellPreparationResult,
+	initialValues EffectValueMap,
+) (*EffectApplicationResult, error) {
+	total := &EffectApplicationResult{
+		AppliedEffects: make([]AppliedEffect, 0),
+	}
+	for _, spellTarget := range prepResult.Targets {
+		// NOTE: คำนวณ modifier ครั้งเดียวต่อเป้า สำหรับทุก effect (ธาตุ/บัฟของแต่ละเป้าต่างกัน)
+		modifierCtx, err := s.CalculateCombinedModifiers(
+			prepResult.Caster,
+			spellTarget.Combatant,
+			prepResult.Spell,
+			prepResult.PowerModifier,
+			0, // effect ID 0 = ใช้ modifier ทั่วไป
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.ApplyCalculatedEffects(
+			match,
+			prepResult.Caster,
+			spellTarget,
+			prepResult.Spell,
+			initialValues,
+			modifierCtx,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s._RecordApplicationEvents(match, prepResult.Caster, prepResult.Spell, result)
+
+		total.AppliedEffects = append(total.AppliedEffects, result.AppliedEffects...)
+		total.TotalDamage += result.TotalDamage
+		total.TotalHealing += result.TotalHealing
+		total.TotalAbsorbed += result.TotalAbsorbed
+		total.EffectsApplied += result.EffectsApplied
+		total.EffectsEvaded += result.EffectsEvaded
+		total.EffectsSkipped += result.EffectsSkipped
+		total.Errors = append(total.Errors, result.Errors...)
+	}
+	return total, nil
+}
@@ -0,0 +1,196 @@
+// file: internal/modules/combat/spell_conditions.go
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"sort"
+)
+
+// ==================== Spell Effect Conditions ====================
+// ไฟล์นี้ตรวจ ConditionType/ConditionDetails ของ SpellEffect ก่อน apply (Step 4 ของการร่ายเวท)
+// - เงื่อนไขไม่ผ่าน = ข้าม effect นั้น (ไม่ใช่ error) ส่วน effect อื่นของเวทยังทำงานตามปกติ
+// - เงื่อนไข TARGET_* ดูเป้าหมายที่ผู้ร่ายเลือก (ไม่ใช่ target หลัง redirect ของ SYNERGY_BUFF)
+// - LAST_SPELL_WAS / COMBO_COUNT_IS ดูประวัติก่อนการร่ายครั้งนี้ (อัปเดตหลังร่ายเสร็จใน _RecordSpellHistory)
+// - SELF_HAS_ELEMENT ดูธาตุใน Dimensional Seal ที่เก็บไว้ตอนสร้าง match (ไม่อ่าน DB ระหว่างต่อสู้ → replay ได้ผลเดิม)
+// - รูปแบบ ConditionDetails ดู domain.SpellEffectCondition (ตรวจตอน seed แล้วใน seeddata.ValidateSpells)
+
+// _EvaluateEffectCondition คืน true ถ้า effect นี้ควรถูก apply
+func (s *combatService) _EvaluateEffectCondition(
+	match *domain.CombatMatch,
+	caster *domain.Combatant,
+	target *domain.Combatant,
+	spell *domain.Spell,
+	spellEffect *domain.SpellEffect,
+) bool {
+	if !spellEffect.HasCondition() {
+		return true
+	}
+
+	condition, err := spellEffect.ParseCondition()
+	if err != nil {
+		s.appLogger.Warn("Invalid spell effect condition details, skipping effect",
+			"spell_id", spell.ID,
+			"effect_id", spellEffect.EffectID,
+			"condition", spellEffect.ConditionType,
+			"error", err,
+		)
+		return false
+	}
+
+	switch spellEffect.ConditionType {
+	case domain.ConditionTypeSelfHPBelow:
+		return s._CheckHPBelow(caster, condition.Value)
+	case domain.ConditionTypeSelfHPAbove:
+		return s._CheckHPAbove(caster, condition.Value)
+	case domain.ConditionTypeTargetHPBelow:
+		return s._CheckHPBelow(target, condition.Value)
+	case domain.ConditionTypeTargetHPAbove:
+		return s._CheckHPAbove(target, condition.Value)
+
+	case domain.ConditionTypeSelfAPIs:
+		return float64(caster.CurrentAP) >= condition.Value
+
+	case domain.ConditionTypeSelfHasBuff:
+		return s._CheckHasEffect(caster, condition.EffectID, domain.EffectType.IsBuff)
+	case domain.ConditionTypeTargetHasDebuff:
+		return s._CheckHasEffect(target, condition.EffectID, domain.EffectType.IsDebuff)
+
+	case domain.ConditionTypeSelfHasElement:
+		return hasSealElement(caster, condition.ElementID)
+
+	case domain.ConditionTypeTargetIsGuarded:
+		defenseUp := uint(2204) // BUFF_DEFENSE_UP
+		return s._CheckHasShield(target) || s._CheckHasEffect(target, &defenseUp, domain.EffectType.IsBuff)
+	case domain.ConditionTypeTargetHasShield:
+		return s._CheckHasShield(target)
+	case domain.ConditionTypeTargetIsStaggered:
+		return s._CheckHasEffect(target, nil, domain.EffectType.IsCrowdControl)
+
+	case domain.ConditionTypeElementalResonance:
+		return s._CheckElementalResonance(caster, spell)
+
+	case domain.ConditionTypeTurnCountIs:
+		switch condition.Parity {
+		case "EVEN":
+			return match.TurnNumber%2 == 0
+		case "ODD":
+			return match.TurnNumber%2 == 1
+		}
+		return match.TurnNumber == int(condition.Value)
+
+	case domain.ConditionTypeLastSpellWas:
+		return caster.LastSpellID != nil && *caster.LastSpellID == condition.SpellID
+
+	case domain.ConditionTypeComboCountIs:
+		return float64(caster.ComboCount) == condition.Value
+
+	case domain.ConditionTypeRandomChance:
+		return s.rollFloat64(match) < condition.Chance
+	}
+
+	s.appLogger.Warn("Unknown spell effect condition, skipping effect",
+		"spell_id", spell.ID,
+		"effect_id", spellEffect.EffectID,
+		"condition", spellEffect.ConditionType,
+	)
+	return false
+}
+
+// _CheckElementalResonance เช็คว่าธาตุของเวท (T0) หรือธาตุในสูตรของเวท (T1+) ตรงกับพรสวรรค์ของผู้ร่าย
+func (s *combatService) _CheckElementalResonance(caster *domain.Combatant, spell *domain.Spell) bool {
+	talentElements := talentElementIDs(caster)
+	if len(talentElements) == 0 {
+		return false
+	}
+	if talentElements[spell.ElementID] {
+		return true
+	}
+	if spell.ElementID <= 4 {
+		return false
+	}
+
+	recipe, err := s.gameDataRepo.FindRecipeByOutputElementID(spell.ElementID)
+	if err != nil || recipe == nil {
+		return false
+	}
+	for _, ing := range recipe.Ingredients {
+		if talentElements[ing.InputElementID] {
+			return true
+		}
+	}
+	return false
+}
+
+// talentElementIDs คือธาตุ T0 ของพรสวรรค์ที่สูงที่สุดของตัวละคร (1=S, 2=L, 3=G, 4=P; เสมอกันนับทุกธาตุ)
+// ศัตรูไม่มีแต้มพรสวรรค์ → ใช้ธาตุของศัตรูแทน
+func talentElementIDs(combatant *domain.Combatant) map[uint]bool {
+	switch {
+	case combatant == nil:
+		return nil
+	case combatant.Character != nil:
+		char := combatant.Character
+		talents := map[uint]int{1: char.TalentS, 2: char.TalentL, 3: char.TalentG, 4: char.TalentP}
+		highest := 0
+		for _, talent := range talents {
+			highest = max(highest, talent)
+		}
+		if highest <= 0 {
+			return nil
+		}
+		elements := make(map[uint]bool)
+		for elementID, talent := range talents {
+			if talent == highest {
+				elements[elementID] = true
+			}
+		}
+		return elements
+	case combatant.Enemy != nil && combatant.Enemy.ElementID != 0:
+		return map[uint]bool{combatant.Enemy.ElementID: true}
+	}
+	return nil
+}
+
+// hasSealElement เช็คว่า combatant มีธาตุนี้ใน Dimensional Seal ตอนเริ่ม match (ศัตรูไม่มีคลัง = false)
+func hasSealElement(combatant *domain.Combatant, elementID uint) bool {
+	if len(combatant.SealElements) == 0 {
+		return false
+	}
+	var elementIDs []uint
+	if err := json.Unmarshal(combatant.SealElements, &elementIDs); err != nil {
+		return false
+	}
+	for _, id := range elementIDs {
+		if id == elementID {
+			return true
+		}
+	}
+	return false
+}
+
+// _LoadSealElements เก็บธาตุที่ตัวละครมีใน Dimensional Seal (จำนวน > 0) ลง Combatant.SealElements (เรียงตาม ID)
+func (s *combatService) _LoadSealElements(combatant *domain.Combatant) error {
+	inventory, err := s.characterRepo.FindInventoryByCharacterID(*combatant.CharacterID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uint]bool)
+	elementIDs := []uint{}
+	for _, item := range inventory {
+		if item.Quantity > 0 && !seen[item.ElementID] {
+			seen[item.ElementID] = true
+			elementIDs = append(elementIDs, item.ElementID)
+		}
+	}
+	sort.Slice(elementIDs, func(i, j int) bool { return elementIDs[i] < elementIDs[j] })
+
+	combatant.SealElements, err = json.Marshal(elementIDs)
+	return err
+}
+
+// _RecordSpellHistory จำเวทที่เพิ่งร่ายสำเร็จและนับคอมโบของเทิร์นนี้ (คอมโบรีเซ็ตใน endTurn)
+func (s *combatService) _RecordSpellHistory(caster *domain.Combatant, spell *domain.Spell) {
+	spellID := spell.ID
+	caster.LastSpellID = &spellID
+	caster.ComboCount++
+}
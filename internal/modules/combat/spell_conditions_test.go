@@ -0,0 +1,86 @@
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+)
+
+func TestEvaluateEffectCondition(t *testing.T) {
+	// ธาตุ 7 หลอมจาก 1 + 3 (ใช้กับ ELEMENTAL_RESONANCE ของเวท T1)
+	s := newTestCombatService(nil)
+	s.gameDataRepo = fakeGameData{recipes: map[uint]*domain.Recipe{
+		7: {OutputElementID: 7, Ingredients: []*domain.RecipeIngredient{{InputElementID: 1}, {InputElementID: 3}}},
+	}}
+	lastSpell := uint(9)
+
+	tests := []struct {
+		name      string
+		condition domain.ConditionType
+		details   string
+		turn      int
+		spell     domain.Spell
+		caster    func(c *domain.Combatant)
+		target    *domain.Combatant
+		want      bool
+	}{
+		{name: "no condition", condition: domain.ConditionTypeNone, want: true},
+		{name: "self hp below", condition: domain.ConditionTypeSelfHPBelow, details: `{"value":0.5}`, caster: func(c *domain.Combatant) { c.CurrentHP = 40 }, want: true},
+		{name: "target hp above", condition: domain.ConditionTypeTargetHPAbove, details: `{"value":0.5}`, target: newTestEnemy(40, 100)},
+		{name: "ap at least", condition: domain.ConditionTypeSelfAPIs, details: `{"value":3}`, caster: func(c *domain.Combatant) { c.CurrentAP = 3 }, want: true},
+		{name: "ap below", condition: domain.ConditionTypeSelfAPIs, details: `{"value":3}`, caster: func(c *domain.Combatant) { c.CurrentAP = 2 }},
+		{name: "element in the seal", condition: domain.ConditionTypeSelfHasElement, details: `{"element_id":5}`, caster: withSeal(1, 5), want: true},
+		{name: "element not in the seal", condition: domain.ConditionTypeSelfHasElement, details: `{"element_id":5}`, caster: withSeal(1, 2)},
+		{name: "no seal", condition: domain.ConditionTypeSelfHasElement, details: `{"element_id":5}`},
+		{name: "resonance with the highest talent", condition: domain.ConditionTypeElementalResonance, spell: domain.Spell{ElementID: 1}, caster: withTalents(3, 1, 0, 0), want: true},
+		{name: "resonance with a tied talent", condition: domain.ConditionTypeElementalResonance, spell: domain.Spell{ElementID: 2}, caster: withTalents(3, 3, 0, 0), want: true},
+		{name: "no resonance with a lower talent", condition: domain.ConditionTypeElementalResonance, spell: domain.Spell{ElementID: 2}, caster: withTalents(3, 1, 0, 0)},
+		{name: "resonance through the recipe", condition: domain.ConditionTypeElementalResonance, spell: domain.Spell{ElementID: 7}, caster: withTalents(0, 1, 4, 0), want: true},
+		{name: "no resonance without talents", condition: domain.ConditionTypeElementalResonance, spell: domain.Spell{ElementID: 1}, caster: withTalents(0, 0, 0, 0)},
+		{name: "guarded by defense up", condition: domain.ConditionTypeTargetIsGuarded, target: withEffects(newTestEnemy(100, 100), domain.ActiveEffect{EffectID: 2204, Value: 50, TurnsRemaining: 1}), want: true},
+		{name: "guarded by shield", condition: domain.ConditionTypeTargetIsGuarded, target: withEffects(newTestEnemy(100, 100), domain.ActiveEffect{EffectID: 1102, Value: 5, TurnsRemaining: 1}), want: true},
+		{name: "not guarded", condition: domain.ConditionTypeTargetIsGuarded, target: withEffects(newTestEnemy(100, 100), domain.ActiveEffect{EffectID: 4102, Value: 10, TurnsRemaining: 1})},
+		{name: "even turn", condition: domain.ConditionTypeTurnCountIs, details: `{"parity":"EVEN"}`, turn: 4, want: true},
+		{name: "exact turn", condition: domain.ConditionTypeTurnCountIs, details: `{"value":3}`, turn: 4},
+		{name: "last spell was", condition: domain.ConditionTypeLastSpellWas, details: `{"spell_id":9}`, caster: func(c *domain.Combatant) { c.LastSpellID = &lastSpell }, want: true},
+		{name: "first spell of the match", condition: domain.ConditionTypeLastSpellWas, details: `{"spell_id":9}`},
+		{name: "combo count equals", condition: domain.ConditionTypeComboCountIs, details: `{"value":2}`, caster: func(c *domain.Combatant) { c.ComboCount = 2 }, want: true},
+		{name: "combo count above", condition: domain.ConditionTypeComboCountIs, details: `{"value":2}`, caster: func(c *domain.Combatant) { c.ComboCount = 3 }},
+		{name: "certain chance", condition: domain.ConditionTypeRandomChance, details: `{"chance":1}`, want: true},
+		{name: "zero chance", condition: domain.ConditionTypeRandomChance, details: `{"chance":0}`},
+		{name: "malformed details", condition: domain.ConditionTypeSelfHPBelow, details: `{"threshold":0.5}`},
+		{name: "unknown condition", condition: "MOON_IS_FULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caster := newTestCharacter(100, 100)
+			if tt.caster != nil {
+				tt.caster(caster)
+			}
+			if tt.target == nil {
+				tt.target = newTestEnemy(100, 100)
+			}
+			effect := &domain.SpellEffect{EffectID: 1101, ConditionType: tt.condition, ConditionDetails: tt.details}
+			match := &domain.CombatMatch{TurnNumber: tt.turn, RandomSeed: 3}
+
+			if got := s._EvaluateEffectCondition(match, caster, tt.target, &tt.spell, effect); got != tt.want {
+				t.Errorf("_EvaluateEffectCondition() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// --- Helpers ---
+
+func withSeal(elementIDs ...uint) func(c *domain.Combatant) {
+	return func(c *domain.Combatant) {
+		c.SealElements, _ = json.Marshal(elementIDs)
+	}
+}
+
+func withTalents(s, l, g, p int) func(c *domain.Combatant) {
+	return func(c *domain.Combatant) {
+		c.Character.TalentS, c.Character.TalentL, c.Character.TalentG, c.Character.TalentP = s, l, g, p
+	}
+}
@@ -4,6 +4,7 @@ package combat
 import (
 	"fmt"
 	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
 	"sage-of-elements-backend/pkg/apperrors"
 	"strconv"
 
@@ -15,6 +16,7 @@ type SpellPreparationResult struct {
 	Spell           *domain.Spell
 	Caster          *domain.Combatant
 	Target          *domain.Combatant
+	Targets         []*SpellTarget // ทุกเป้าหมายที่เวทลง (เป้าหลักอยู่ตัวแรก, เวทเป้าเดี่ยวมีตัวเดียว)
 	FinalAPCost     int
 	FinalMPCost     int
 	PowerModifier   float64
@@ -43,6 +45,9 @@ func (s *combatService) PrepareAndValidateCast(
 	if err != nil {
 		return nil, err
 	}
+	if err := s._ValidateSpellUnlocked(caster, spell); err != nil {
+		return nil, err
+	}
 
 	// 1.2 Find Target
 	target := s._FindTarget(match, targetID)
@@ -51,10 +56,12 @@ func (s *combatService) PrepareAndValidateCast(
 	}
 
 	// 1.3 Validate Targeting Rules
-	if err := s._ValidateTargeting(spell, caster.ID, targetID, target); err != nil {
+	if err := s._ValidateTargeting(spell, caster, targetID, target); err != nil {
 		return nil, err
 	}
 
+	targets := s._ResolveSpellTargets(match, caster, spell, target)
+
 	// 1.4 Calculate Final Cost & Power Modifier
 	finalAP, finalMP, powerMod, err := s._CalculateFinalCost(spell.APCost, spell.MPCost, castingMode)
 	if err != nil {
@@ -78,12 +85,14 @@ func (s *combatService) PrepareAndValidateCast(
 		"final_mp_cost", finalMP,
 		"power_modifier", powerMod,
 		"consumed_charges", consumedCharges,
+		"target_count", len(targets),
 	)
 
 	return &SpellPreparationResult{
 		Spell:           spell,
 		Caster:          caster,
 		Target:          target,
+		Targets:         targets,
 		FinalAPCost:     finalAP,
 		FinalMPCost:     finalMP,
 		PowerModifier:   powerMod,
@@ -107,20 +116,66 @@ func (s *combatService) _FetchSpellData(spellID uint) (*domain.Spell, error) {
 	return spell, nil
 }
 
+// _ValidateSpellUnlocked ตรวจว่าตัวละครปลดล็อกเวทนี้แล้ว ทั้งที่ผู้เล่นคุมเองและที่ AI เล่นแทน (ศัตรูไม่ต้องตรวจ)
+// ตัวละครที่ AI เล่นแทนใช้รายการใน Combatant.GhostSpells แทนการอ่านการปลดล็อกจาก DB
+// replay ได้ตัวละคร nil จาก replayCharacterRepository จึงข้ามไป (action ถูกตรวจไปแล้วตอนเล่นจริง)
+func (s *combatService) _ValidateSpellUnlocked(caster *domain.Combatant, spell *domain.Spell) error {
+	if caster.CharacterID == nil || !character.HasUnlockRequirements(spell) {
+		return nil
+	}
+	// AI เล่นแทน: ตรวจกับรายการเวทที่ resolve ไว้ตอนสร้าง match (ไม่อ่าน/เขียน DB ระหว่างต่อสู้)
+	if spellIDs, ok := ghostSpellIDs(caster); ok {
+		for _, spellID := range spellIDs {
+			if spellID == spell.ID {
+				return nil
+			}
+		}
+		return apperrors.NewWithDetails(403, "SPELL_LOCKED", fmt.Sprintf("spell %s is locked for this character", spell.Name),
+			map[string]interface{}{"spell_id": spell.ID})
+	}
+	char, err := s.characterRepo.FindByID(*caster.CharacterID)
+	if err != nil {
+		s.appLogger.Error("Failed to find caster character for spell unlock check", err, "character_id", *caster.CharacterID)
+		return apperrors.SystemError("failed to check spell unlock")
+	}
+	if char == nil {
+		return nil
+	}
+
+	missing, err := character.CheckSpellUnlock(char, spell, s.characterRepo)
+	if err != nil {
+		s.appLogger.Error("Failed to check spell unlock", err, "character_id", char.ID, "spell_id", spell.ID)
+		return apperrors.SystemError("failed to check spell unlock")
+	}
+	if len(missing) > 0 {
+		return apperrors.NewWithDetails(403, "SPELL_LOCKED", fmt.Sprintf("spell %s is locked for this character", spell.Name),
+			map[string]interface{}{
+				"spell_id":             spell.ID,
+				"requirements":         character.NewSpellRequirements(spell),
+				"missing_requirements": missing,
+			})
+	}
+	return nil
+}
+
 // _FindTarget หา combatant ตาม UUID
 func (s *combatService) _FindTarget(match *domain.CombatMatch, targetID uuid.UUID) *domain.Combatant {
-	return s.findCombatantByID(match, targetID)
+	target := s.findCombatantByID(match, targetID)
+	if target == nil || target.HasFled {
+		return nil // ศัตรูที่หนีไปแล้วไม่อยู่ใน match ให้เล็งอีก
+	}
+	return target
 }
 
 // _ValidateTargeting ตรวจสอบว่า target ตรงกับ spell.TargetType หรือไม่
 func (s *combatService) _ValidateTargeting(
 	spell *domain.Spell,
-	casterID uuid.UUID,
+	caster *domain.Combatant,
 	targetID uuid.UUID,
 	target *domain.Combatant,
 ) error {
 	isValidTarget := true
-	casterIDStr := casterID.String()
+	casterIDStr := caster.ID.String()
 	targetIDStr := targetID.String()
 
 	switch spell.TargetType {
@@ -149,7 +204,7 @@ func (s *combatService) _ValidateTargeting(
 	case domain.TargetTypeAlly:
 		// Target ต้องเป็น self หรือพันธมิตร
 		if targetIDStr != casterIDStr {
-			isActuallyAlly := target.CharacterID != nil
+			isActuallyAlly := isEnemySide(target) == isEnemySide(caster)
 			if !isActuallyAlly {
 				isValidTarget = false
 				s.appLogger.Warn("Invalid target for ALLY spell (target is not an ally)",
@@ -160,6 +215,19 @@ func (s *combatService) _ValidateTargeting(
 			}
 		}
 
+	case domain.TargetTypeEnemyAOE, domain.TargetTypeAllEnemies,
+		domain.TargetTypeAllyAOE, domain.TargetTypeAllAllies:
+		// เวทหมู่: เป้าหลักต้องอยู่ฝ่ายที่เวทลง (เป้ารองหาจากฝ่ายเดียวกันใน _ResolveSpellTargets)
+		if (isEnemySide(target) == isEnemySide(caster)) != spell.TargetType.TargetsAllies() {
+			isValidTarget = false
+			s.appLogger.Warn("Invalid primary target for multi-target spell",
+				"spell", spell.Name,
+				"target_type", spell.TargetType,
+				"caster", casterIDStr,
+				"target", targetIDStr,
+			)
+		}
+
 	default:
 		s.appLogger.Warn("Unknown or unhandled TargetType in validation",
 			"spell", spell.Name,
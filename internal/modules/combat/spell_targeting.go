@@ -0,0 +1,58 @@
+// file: internal/modules/combat/spell_targeting.go
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+)
+
+// ==================== Multi-Target Resolution ====================
+// ไฟล์นี้แปลง spell.TargetType เป็นรายการเป้าหมายของการร่าย 1 ครั้ง
+// - SELF / ENEMY / ALLY: เป้าเดียวตามที่เลือก (เหมือนเดิม)
+// - ENEMY_AOE / ALLY_AOE: เป้าหลักที่เลือก + ทุกตัวที่ยังอยู่ในฝ่ายเดียวกัน (เป้ารองโดนดาเมจ × SPELL_AOE_SECONDARY_DAMAGE_MOD)
+// - ALL_ENEMIES / ALL_ALLIES: ทุกตัวที่ยังอยู่ในฝ่ายนั้นโดนเท่ากัน (เป้าที่เลือกใช้เป็นเป้าหลักของ CAST event)
+// แต่ละเป้าคิด modifier (ธาตุ/บัฟ), เงื่อนไข, evasion, shield และ retaliation แยกกัน
+// ลำดับเป้า: เป้าหลักก่อน แล้วตามลำดับใน match.Combatants (ลำดับคงที่ → replay ได้ผลสุ่มเท่าเดิม)
+
+// SpellTarget คือเป้าหมาย 1 ตัวของการร่าย
+type SpellTarget struct {
+	Combatant *domain.Combatant
+	Primary   bool    // เป้าหลัก: effect ที่ย้ายไปลงผู้ร่าย (SYNERGY_BUFF) ทำงานเฉพาะกับเป้านี้
+	DamageMod float64 // ตัวคูณดาเมจเฉพาะเป้านี้ (1.0 = เต็ม)
+}
+
+// _ResolveSpellTargets หาเป้าหมายทั้งหมดของเวท (target ผ่าน _ValidateTargeting มาแล้ว)
+func (s *combatService) _ResolveSpellTargets(
+	match *domain.CombatMatch,
+	caster *domain.Combatant,
+	spell *domain.Spell,
+	target *domain.Combatant,
+) []*SpellTarget {
+	targets := []*SpellTarget{{Combatant: target, Primary: true, DamageMod: 1.0}}
+	if !spell.TargetType.IsMultiTarget() {
+		return targets
+	}
+
+	secondaryMod := 1.0
+	if spell.TargetType.HasFalloff() {
+		secondaryMod = s._GetConfigFloat("SPELL_AOE_SECONDARY_DAMAGE_MOD", 1.0)
+	}
+
+	// เป้ารอง = ฝ่ายเดียวกับเป้าหลัก (_ValidateTargeting ตรวจแล้วว่าเป้าหลักอยู่ฝ่ายที่เวทลง)
+	targetEnemySide := isEnemySide(target)
+	for _, c := range s.findAliveCombatants(match) {
+		if c.ID == target.ID || isEnemySide(c) != targetEnemySide {
+			continue
+		}
+		targets = append(targets, &SpellTarget{Combatant: c, DamageMod: secondaryMod})
+	}
+	return targets
+}
+
+// spellTargetIDs คือ ID ของเป้าหมายทั้งหมด (ใช้ใน details ของ CAST event)
+func spellTargetIDs(targets []*SpellTarget) []string {
+	ids := make([]string, 0, len(targets))
+	for _, t := range targets {
+		ids = append(ids, t.Combatant.ID.String())
+	}
+	return ids
+}
@@ -0,0 +1,124 @@
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestResolveSpellTargets(t *testing.T) {
+	s := newTestCombatService(map[string]string{"SPELL_AOE_SECONDARY_DAMAGE_MOD": "0.5"})
+	caster, friend := newTestCharacter(100, 100), newTestCharacter(100, 100)
+	front, dead, fled, back := newTestEnemy(100, 100), newTestEnemy(0, 100), newTestEnemy(100, 100), newTestEnemy(100, 100)
+	fled.HasFled = true
+	match := &domain.CombatMatch{Combatants: []*domain.Combatant{caster, front, dead, friend, fled, back}}
+	names := map[uuid.UUID]string{caster.ID: "caster", friend.ID: "friend", front.ID: "front", dead.ID: "dead", fled.ID: "fled", back.ID: "back"}
+
+	type want struct {
+		name      string
+		damageMod float64
+	}
+	tests := []struct {
+		targetType domain.TargetType
+		target     *domain.Combatant
+		want       []want
+	}{
+		{targetType: domain.TargetTypeEnemy, target: back, want: []want{{"back", 1}}},
+		{targetType: domain.TargetTypeEnemyAOE, target: back, want: []want{{"back", 1}, {"front", 0.5}}},
+		{targetType: domain.TargetTypeAllEnemies, target: back, want: []want{{"back", 1}, {"front", 1}}},
+		{targetType: domain.TargetTypeAllyAOE, target: caster, want: []want{{"caster", 1}, {"friend", 0.5}}},
+		{targetType: domain.TargetTypeAllAllies, target: friend, want: []want{{"friend", 1}, {"caster", 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.targetType), func(t *testing.T) {
+			spell := &domain.Spell{TargetType: tt.targetType}
+
+			targets := s._ResolveSpellTargets(match, caster, spell, tt.target)
+			if len(targets) != len(tt.want) {
+				t.Fatalf("got %d targets %v, want %v", len(targets), spellTargetIDs(targets), tt.want)
+			}
+			for i, w := range tt.want {
+				got := targets[i]
+				if names[got.Combatant.ID] != w.name || got.DamageMod != w.damageMod || got.Primary != (i == 0) {
+					t.Errorf("target %d = %s (mod %.2f, primary %t), want %s (mod %.2f, primary %t)",
+						i, names[got.Combatant.ID], got.DamageMod, got.Primary, w.name, w.damageMod, i == 0)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyCalculatedEffectsAoEFalloffAndRetaliation(t *testing.T) {
+	// เวทหมู่ดาเมจ 40: เป้าหลักโดนเต็ม, เป้ารองโดน × 0.5 และเป้าที่มี RETALIATION สะท้อนกลับแยกกันทีละเป้า
+	tests := []struct {
+		name          string
+		frontEffects  []domain.ActiveEffect
+		backEffects   []domain.ActiveEffect
+		casterHP      int
+		wantFrontHP   int
+		wantBackHP    int
+		wantCasterHP  int
+		wantReflected []float64 // ดาเมจสะท้อนของ [front, back]
+	}{
+		{name: "no retaliation", casterHP: 50, wantFrontHP: 60, wantBackHP: 80, wantCasterHP: 50, wantReflected: []float64{0, 0}},
+		{
+			name:          "each target reflects on its own",
+			frontEffects:  []domain.ActiveEffect{{EffectID: 2203, Value: 5, TurnsRemaining: 2}},
+			backEffects:   []domain.ActiveEffect{{EffectID: 2203, Value: 7, TurnsRemaining: 2}},
+			casterHP:      50,
+			wantFrontHP:   60,
+			wantBackHP:    80,
+			wantCasterHP:  38,
+			wantReflected: []float64{5, 7},
+		},
+		{
+			name:          "hit absorbed by a shield still reflects",
+			backEffects:   []domain.ActiveEffect{{EffectID: 1102, Value: 30, TurnsRemaining: 2}, {EffectID: 2203, Value: 7, TurnsRemaining: 2}},
+			casterHP:      50,
+			wantFrontHP:   60,
+			wantBackHP:    100,
+			wantCasterHP:  43,
+			wantReflected: []float64{0, 7},
+		},
+		{
+			name:          "reflection stops at zero hp",
+			frontEffects:  []domain.ActiveEffect{{EffectID: 2203, Value: 7, TurnsRemaining: 2}},
+			casterHP:      4,
+			wantFrontHP:   60,
+			wantBackHP:    80,
+			wantCasterHP:  0,
+			wantReflected: []float64{4, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestCombatService(map[string]string{"SPELL_AOE_SECONDARY_DAMAGE_MOD": "0.5"})
+			caster := newTestCharacter(tt.casterHP, 100)
+			front := withEffects(newTestEnemy(100, 100), tt.frontEffects...)
+			back := withEffects(newTestEnemy(100, 100), tt.backEffects...)
+			match := &domain.CombatMatch{Combatants: []*domain.Combatant{caster, front, back}}
+			spell := &domain.Spell{ID: 1, TargetType: domain.TargetTypeEnemyAOE, Effects: []*domain.SpellEffect{{EffectID: 1101}}}
+
+			targets := s._ResolveSpellTargets(match, caster, spell, front)
+			for i, target := range targets {
+				result, err := s.ApplyCalculatedEffects(match, caster, target, spell, EffectValueMap{1101: 40}, &ModifierContext{CombinedMod: 1.0})
+				if err != nil {
+					t.Fatalf("ApplyCalculatedEffects() error = %v", err)
+				}
+				if len(result.AppliedEffects) != 1 || result.AppliedEffects[0].Retaliation != tt.wantReflected[i] {
+					t.Errorf("target %d applied = %+v, want one damage effect reflecting %.0f", i, result.AppliedEffects, tt.wantReflected[i])
+				}
+			}
+
+			if front.CurrentHP != tt.wantFrontHP || back.CurrentHP != tt.wantBackHP {
+				t.Errorf("front/back hp = %d/%d, want %d/%d", front.CurrentHP, back.CurrentHP, tt.wantFrontHP, tt.wantBackHP)
+			}
+			if caster.CurrentHP != tt.wantCasterHP {
+				t.Errorf("caster hp = %d, want %d", caster.CurrentHP, tt.wantCasterHP)
+			}
+		})
+	}
+}
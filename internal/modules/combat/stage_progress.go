@@ -0,0 +1,128 @@
+// file: internal/modules/combat/stage_progress.go
+package combat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/pve"
+	"sage-of-elements-backend/pkg/apperrors"
+	"time"
+)
+
+// ==================== Stage Progression ====================
+// ไฟล์นี้เชื่อมโหมด STORY เข้ากับความคืบหน้าของตัวละคร
+// - CreateMatch: ห้ามเข้าด่านที่ยังล็อกอยู่ (กฎอยู่ใน pve.ResolveStageStatuses)
+// - _EndMatch (ชนะ): บันทึก CharacterStageProgress และให้ FirstClearRewards ครั้งเดียว
+//   ความคืบหน้า, EXP และธาตุที่ได้ถูกเก็บไว้ใน match แล้วเขียนพร้อมการจบ match ใน UpdateMatch
+//   (save ไม่สำเร็จ = ไม่มีอะไรถูกเขียน → จบ match ใหม่ได้รางวัลครั้งเดียวเหมือนเดิม)
+
+// _CheckStageUnlocked ตรวจว่าตัวละครเข้าด่านนี้ได้หรือยัง
+func (s *combatService) _CheckStageUnlocked(char *domain.Character, stageID uint) error {
+	realms, err := s.pveRepo.FindAllActiveRealms()
+	if err != nil {
+		s.appLogger.Error("Failed to load realms", err)
+		return apperrors.SystemError("failed to check stage progression")
+	}
+	progress, err := s.pveRepo.FindStageProgressByCharacterID(char.ID)
+	if err != nil {
+		s.appLogger.Error("Failed to load stage progress", err, "character_id", char.ID)
+		return apperrors.SystemError("failed to check stage progression")
+	}
+
+	status, ok := pve.ResolveStageStatuses(realms, progress, char)[stageID]
+	if !ok || status == pve.StageStatusLocked {
+		return apperrors.New(403, "STAGE_LOCKED",
+			fmt.Sprintf("stage %d is locked, clear the previous stage first", stageID))
+	}
+	return nil
+}
+
+// _RecordStageClear บันทึกการเคลียร์ด่าน STORY และให้รางวัลเคลียร์ครั้งแรก
+func (s *combatService) _RecordStageClear(match *domain.CombatMatch, characterID uint) {
+	if match.MatchType != domain.MatchTypeStory || match.StageID == nil {
+		return
+	}
+	stageID := *match.StageID
+
+	progress, err := s.pveRepo.FindStageProgress(characterID, stageID)
+	if err != nil {
+		s.appLogger.Error("failed to load stage progress", err, "character_id", characterID, "stage_id", stageID)
+		return
+	}
+	now := time.Now()
+	if progress == nil {
+		progress = &domain.CharacterStageProgress{
+			CharacterID:   characterID,
+			StageID:       stageID,
+			ClearedAt:     now,
+			BestTurnCount: match.TurnNumber,
+		}
+	}
+	progress.ClearCount++
+	progress.LastClearedAt = now
+	if match.TurnNumber < progress.BestTurnCount {
+		progress.BestTurnCount = match.TurnNumber
+	}
+
+	// รางวัลเคลียร์ครั้งแรก (flag ถูกบันทึกใน transaction เดียวกับรางวัล จึงไม่ได้ซ้ำ)
+	var rewards *domain.StageRewards
+	if !progress.RewardsClaimed {
+		rewards = s._GrantFirstClearRewards(match, characterID, stageID)
+		progress.RewardsClaimed = true
+	}
+	match.PendingStageProgress = append(match.PendingStageProgress, progress)
+
+	clearEvent := newCombatEvent(domain.CombatEventStageCleared, nil, nil, 0)
+	details := map[string]interface{}{
+		"stage_id":    stageID,
+		"first_clear": progress.ClearCount == 1,
+		"clear_count": progress.ClearCount,
+	}
+	if rewards != nil {
+		details["rewards"] = rewards
+	}
+	clearEvent.Details = eventDetails(details)
+	s.recordEvent(match, clearEvent)
+
+	s.appLogger.Info("🏁 Stage cleared",
+		"character_id", characterID,
+		"stage_id", stageID,
+		"clear_count", progress.ClearCount,
+		"best_turn_count", progress.BestTurnCount,
+	)
+}
+
+// _GrantFirstClearRewards ให้ EXP และธาตุตาม Stage.FirstClearRewards (คืน nil ถ้าด่านไม่มีรางวัล)
+// ธาตุเข้าคลังผ่าน match.PendingLoot แบบเดียวกับของดรอปจากศัตรู
+func (s *combatService) _GrantFirstClearRewards(match *domain.CombatMatch, characterID uint, stageID uint) *domain.StageRewards {
+	stage, err := s.pveRepo.FindStageByID(stageID)
+	if err != nil || stage == nil || len(stage.FirstClearRewards) == 0 {
+		return nil
+	}
+	var rewards domain.StageRewards
+	if err := json.Unmarshal(stage.FirstClearRewards, &rewards); err != nil {
+		s.appLogger.Error("invalid first clear rewards", err, "stage_id", stageID)
+		return nil
+	}
+
+	if rewards.Exp > 0 && !s._GrantExp(match, characterID, rewards.Exp) {
+		s.appLogger.Warn("first clear exp not granted", "character_id", characterID, "stage_id", stageID)
+	}
+
+	items := make(map[uint]int, len(rewards.Elements))
+	for _, element := range rewards.Elements {
+		if element.Quantity <= 0 {
+			continue
+		}
+		items[element.ElementID] += element.Quantity
+		match.PendingLoot = append(match.PendingLoot, &domain.LootDrop{
+			CharacterID: characterID,
+			ElementID:   element.ElementID,
+			Quantity:    element.Quantity,
+		})
+	}
+	s._AddResultLoot(match, characterID, items)
+
+	return &rewards
+}
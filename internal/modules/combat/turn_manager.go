@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math"
 	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
 	"sage-of-elements-backend/pkg/apperrors"
 	"strconv"
 	"time"
@@ -12,7 +13,7 @@ import (
 
 // ==================== Turn Manager ====================
 // ไฟล์นี้จัดการ turn-based combat flow:
-// - การหมุนเทิร์น (turn rotation)
+// - การหมุนเทิร์น (turn queue ตาม Initiative ปัจจุบัน ดู _NextInTurnQueue)
 // - การเริ่มเทิร์นใหม่ (resource regeneration, effect processing)
 // - การตรวจสอบเงื่อนไขจบเกม (win/lose conditions)
 
@@ -47,21 +48,29 @@ func (s *combatService) endTurn(match *domain.CombatMatch) *domain.CombatMatch {
 	// เก็บ ID ของคนที่เพิ่งจบเทิร์น
 	endedTurnCombatantID := match.CurrentTurn
 
-	// คำนวณ index ถัดไป (wrap around)
-	nextIndex := (currentIndex + 1) % len(match.Combatants)
-	nextCombatant := match.Combatants[nextIndex]
+	s.recordEvent(match, newCombatEvent(domain.CombatEventTurnEnded, match.Combatants[currentIndex], nil, 0))
 
-	// อัปเดต match state
-	match.CurrentTurn = nextCombatant.ID
+	// คอมโบนับเฉพาะเวทที่ร่ายภายในเทิร์นเดียวกัน (COMBO_COUNT_IS)
+	match.Combatants[currentIndex].ComboCount = 0
+	match.Combatants[currentIndex].LastActedRound = match.TurnNumber
 
-	// เพิ่มรอบถ้าวนกลับมาคนแรก
-	if nextIndex == 0 {
+	// หาคนถัดไปในคิว ถ้าทุกคนเล่นครบรอบแล้วให้เริ่มรอบใหม่
+	nextCombatant := s._NextInTurnQueue(match)
+	if nextCombatant == nil {
 		match.TurnNumber++
 		s.appLogger.Info("🔄 New round started",
 			"match_id", match.ID,
 			"round_number", match.TurnNumber,
 		)
+		nextCombatant = s._NextInTurnQueue(match)
 	}
+	if nextCombatant == nil {
+		// ไม่เหลือใครสู้ได้ (checkMatchEndCondition จะจบ match) → คงเทิร์นไว้ที่คนเดิม
+		nextCombatant = match.Combatants[currentIndex]
+	}
+
+	// อัปเดต match state
+	match.CurrentTurn = nextCombatant.ID
 
 	s.appLogger.Info("✅ Turn ended",
 		"ended_by", endedTurnCombatantID,
@@ -72,6 +81,21 @@ func (s *combatService) endTurn(match *domain.CombatMatch) *domain.CombatMatch {
 	return match
 }
 
+// _NextInTurnQueue คือ combatant ที่ยังสู้อยู่และยังไม่ได้เล่นในรอบนี้ ที่มี Initiative ปัจจุบันสูงสุด
+// (Initiative เปลี่ยนระหว่างรอบได้จาก DEBUFF_SLOW, เท่ากัน = ตามลำดับใน match.Combatants, nil = เล่นครบรอบแล้ว)
+func (s *combatService) _NextInTurnQueue(match *domain.CombatMatch) *domain.Combatant {
+	var next *domain.Combatant
+	for _, c := range s.findAliveCombatants(match) {
+		if c.LastActedRound >= match.TurnNumber {
+			continue
+		}
+		if next == nil || c.Initiative > next.Initiative {
+			next = c
+		}
+	}
+	return next
+}
+
 // ==================== Turn Initialization ====================
 
 // startNewTurn เริ่มเทิร์นใหม่และประมวลผลทุกอย่างที่ต้องทำต้นเทิร์น
@@ -88,7 +112,7 @@ func (s *combatService) startNewTurn(match *domain.CombatMatch) (*domain.CombatM
 	)
 
 	// 1. ประมวลผล effects (ticks และ expiry)
-	s._ProcessTurnEffects(currentCombatant)
+	s._ProcessTurnEffects(match, currentCombatant)
 
 	// 2. คำนวณ stats ใหม่
 	s.recalculateStats(currentCombatant)
@@ -101,6 +125,17 @@ func (s *combatService) startNewTurn(match *domain.CombatMatch) (*domain.CombatM
 		s._RegeneratePlayerMP(currentCombatant)
 	}
 
+	turnEvent := newCombatEvent(domain.CombatEventTurnStarted, currentCombatant, nil, 0)
+	turnEvent.Details = eventDetails(map[string]interface{}{
+		"ap": currentCombatant.CurrentAP,
+		"mp": currentCombatant.CurrentMP,
+		"hp": currentCombatant.CurrentHP,
+	})
+	s.recordEvent(match, turnEvent)
+
+	// 5. เริ่มจับเวลาเทิร์น (เฉพาะผู้เล่น)
+	s._StartTurnTimer(match, currentCombatant)
+
 	s.appLogger.Info("✅ New turn ready",
 		"combatant_id", currentCombatant.ID,
 		"ap", currentCombatant.CurrentAP,
@@ -113,11 +148,11 @@ func (s *combatService) startNewTurn(match *domain.CombatMatch) (*domain.CombatM
 // ==================== Turn Start Helpers ====================
 
 // _ProcessTurnEffects ประมวลผล active effects ต้นเทิร์น
-func (s *combatService) _ProcessTurnEffects(combatant *domain.Combatant) {
+func (s *combatService) _ProcessTurnEffects(match *domain.CombatMatch, combatant *domain.Combatant) {
 	s.appLogger.Debug("⚡ Processing effects",
 		"combatant_id", combatant.ID,
 	)
-	s.processEffectTicksAndExpiry(combatant)
+	s.processEffectTicksAndExpiry(match, combatant)
 }
 
 // _RegenerateAP เพิ่ม AP ต้นเทิร์น (มี cap)
@@ -216,9 +251,13 @@ func (s *combatService) checkMatchEndCondition(match *domain.CombatMatch) *domai
 	playerDefeated := s._IsTeamDefeated(playerTeam)
 	enemyDefeated := s._IsTeamDefeated(enemyTeam)
 
-	// ถ้ามีทีมแพ้ ให้จบเกม
+	// ถ้ามีทีมแพ้ ให้จบเกม (ศัตรูที่เหลือหนีไปหมด = จบแบบ FLED)
 	if playerDefeated || enemyDefeated {
-		s._EndMatch(match, playerDefeated, enemyDefeated)
+		reason := domain.MatchEndDefeat
+		if !playerDefeated && s._AnyFled(enemyTeam) {
+			reason = domain.MatchEndFled
+		}
+		s._EndMatch(match, playerDefeated, enemyDefeated, reason)
 	}
 
 	return match
@@ -226,7 +265,7 @@ func (s *combatService) checkMatchEndCondition(match *domain.CombatMatch) *domai
 
 // ==================== Match End Helpers ====================
 
-// _SeparateTeams แยก combatants เป็น player team และ enemy team
+// _SeparateTeams แยก combatants ตาม Combatant.Team (PLAYER = ผู้ท้าชิงและพันธมิตร, ENEMY = อีกฝั่ง)
 func (s *combatService) _SeparateTeams(match *domain.CombatMatch) ([]*domain.Combatant, []*domain.Combatant) {
 	playerTeam := s.findPlayerCombatants(match)
 	enemyTeam := s.findEnemyCombatants(match)
@@ -239,7 +278,7 @@ func (s *combatService) _SeparateTeams(match *domain.CombatMatch) ([]*domain.Com
 	return playerTeam, enemyTeam
 }
 
-// _IsTeamDefeated เช็คว่าทีมนี้แพ้หรือยัง (HP ของทุกคนเป็น 0)
+// _IsTeamDefeated เช็คว่าทีมนี้แพ้หรือยัง (ทุกคน HP เป็น 0 หรือหนีไปแล้ว)
 func (s *combatService) _IsTeamDefeated(team []*domain.Combatant) bool {
 	// สร้าง temporary match เพื่อใช้ findAliveCombatants
 	tempMatch := &domain.CombatMatch{Combatants: team}
@@ -247,53 +286,154 @@ func (s *combatService) _IsTeamDefeated(team []*domain.Combatant) bool {
 	return len(aliveMembers) == 0 // ถ้าไม่มีคนเหลือ = แพ้
 }
 
+// _AnyFled เช็คว่ามีสมาชิกในทีมหนีออกจาก match หรือไม่
+func (s *combatService) _AnyFled(team []*domain.Combatant) bool {
+	for _, c := range team {
+		if c.HasFled {
+			return true
+		}
+	}
+	return false
+}
+
 // _EndMatch จบเกมและบันทึกผลลัพธ์
-func (s *combatService) _EndMatch(match *domain.CombatMatch, playerDefeated bool, enemyDefeated bool) {
+func (s *combatService) _EndMatch(match *domain.CombatMatch, playerDefeated bool, enemyDefeated bool, reason domain.MatchEndReason) {
 	now := time.Now()
 	match.Status = domain.MatchFinished
 	match.FinishedAt = &now
+	match.TurnDeadline = nil
+
+	winnerSide := domain.MatchSidePlayer
+	if playerDefeated {
+		winnerSide = domain.MatchSideEnemy
+	}
+	s._BuildMatchResults(match, winnerSide, reason)
+
+	result := "PLAYER_WIN"
+	if playerDefeated {
+		result = "PLAYER_LOSE"
+	}
+	endEvent := newCombatEvent(domain.CombatEventMatchEnded, nil, nil, 0)
+	endEvent.Details = eventDetails(map[string]interface{}{"result": result})
+	s.recordEvent(match, endEvent)
 
 	if playerDefeated {
 		s.appLogger.Info("💀 Match ended: Player defeated",
 			"match_id", match.ID,
 		)
+
+		// PvP สด: ฝ่ายถูกท้าเป็นผู้เล่นจริง → ได้รางวัลในฐานะผู้ชนะ
+		for _, combatant := range s.findEnemyCombatants(match) {
+			if isHumanControlled(combatant) {
+				s._GrantVictoryExp(match, *combatant.CharacterID)
+			}
+		}
 	} else if enemyDefeated {
 		s.appLogger.Info("🎉 Match ended: Player wins!",
 			"match_id", match.ID,
 		)
 
-		// ⭐️ เพิ่ม EXP ให้ผู้เล่นเมื่อชนะ
-		// หา player combatant จาก combatants list
-		for _, combatant := range match.Combatants {
-			if combatant.CharacterID != nil {
-				// นี่คือ player combatant
-				expAmount := s._CalculateExpReward(match.MatchType)
-				if expAmount > 0 {
-					characterID := *combatant.CharacterID
-
-					// อัปเดต EXP โดยตรงผ่าน repo
-					character, err := s.characterRepo.FindByID(characterID)
-					if err == nil && character != nil {
-						character.Exp += expAmount
-						_, err = s.characterRepo.Save(character)
-						if err != nil {
-							s.appLogger.Error("failed to grant exp after victory", err,
-								"character_id", characterID,
-								"exp_amount", expAmount,
-							)
-						} else {
-							s.appLogger.Info("granted exp after victory",
-								"character_id", characterID,
-								"exp_amount", expAmount,
-								"new_total", character.Exp,
-							)
-						}
-					}
-				}
-				break
-			}
+		// ⭐️ เพิ่ม EXP ให้ผู้เล่นเมื่อชนะ (ตัวละครที่ AI ควบคุมใน PvP และพันธมิตร co-op/NPC ซึ่ง AI เล่นแทนเสมอ ไม่ได้รางวัล)
+		player := s.findPlayerCombatant(match)
+		if player != nil {
+			s._RecordStageClear(match, *player.CharacterID)
+			s._GrantVictoryExp(match, *player.CharacterID)
+			s._RollEnemyLoot(match, player)
 		}
 	}
+
+	if playerDefeated || enemyDefeated {
+		s._UpdatePvpRatings(match, playerDefeated)
+	}
+	s._GrantMasteryExp(match)
+}
+
+// _GrantVictoryExp เพิ่ม EXP ให้ตัวละครที่ชนะตาม Match Type (ลดลงตามสัดส่วนศัตรูที่หนีไป)
+func (s *combatService) _GrantVictoryExp(match *domain.CombatMatch, characterID uint) {
+	expAmount := int(math.Round(float64(s._CalculateExpReward(match.MatchType)) * s._FledEnemyExpModifier(match)))
+	if expAmount <= 0 {
+		return
+	}
+	if s._GrantExp(match, characterID, expAmount) {
+		s.appLogger.Info("granted exp after victory",
+			"character_id", characterID,
+			"exp_amount", expAmount,
+		)
+	}
+}
+
+// _FledEnemyExpModifier คือสัดส่วน EXP ที่ได้จริงเมื่อมีศัตรูหนี
+// ศัตรูที่ถูกกำจัดนับเต็ม 1 ส่วน ตัวที่หนีนับ EXP_FLED_ENEMY_MOD ส่วน (ไม่มีศัตรู AI ใน match = 1.0)
+func (s *combatService) _FledEnemyExpModifier(match *domain.CombatMatch) float64 {
+	fledMod := s._GetConfigFloat("EXP_FLED_ENEMY_MOD", 0.5)
+	total, share := 0, 0.0
+	for _, c := range s.findEnemyCombatants(match) {
+		if c.EnemyID == nil {
+			continue
+		}
+		total++
+		if c.HasFled {
+			share += fledMod
+		} else {
+			share++
+		}
+	}
+	if total == 0 {
+		return 1.0
+	}
+	return share / float64(total)
+}
+
+// _GrantExp เพิ่ม EXP ผ่านสูตรเลเวล (ขึ้นเลเวล/ได้แต้มพรสวรรค์) แล้วบันทึกลงสรุปผลของ match
+// ตัวละครถูกบันทึกพร้อม match ใน UpdateMatch (ดู _PendingCharacter) คืน false ถ้าโหลดตัวละครไม่ได้
+func (s *combatService) _GrantExp(match *domain.CombatMatch, characterID uint, expAmount int) bool {
+	char := s._PendingCharacter(match, characterID)
+	if char == nil {
+		return false
+	}
+	levelUp := character.ApplyExp(char, expAmount, character.LoadProgressionConfig(s.gameDataRepo))
+
+	if result := matchResultFor(match, characterID); result != nil {
+		result.ExpGained += expAmount
+		if result.LevelBefore == 0 {
+			result.LevelBefore = levelUp.LevelBefore
+		}
+		result.LevelAfter = levelUp.LevelAfter
+		result.TalentPointsGained += levelUp.TalentPointsGained
+	}
+	if levelUp.LevelsGained > 0 {
+		event := newCombatEvent(domain.CombatEventLevelUp, s.findCharacterCombatant(match, characterID), nil, levelUp.LevelAfter)
+		event.Details = eventDetails(levelUp)
+		s.recordEvent(match, event)
+
+		s.appLogger.Info("character leveled up",
+			"character_id", characterID,
+			"level_before", levelUp.LevelBefore,
+			"level_after", levelUp.LevelAfter,
+			"talent_points_gained", levelUp.TalentPointsGained,
+		)
+	}
+	return true
+}
+
+// _PendingCharacter คืนตัวละครที่รอบันทึกพร้อม match (โหลดครั้งแรกแล้วเก็บไว้ใน match.PendingCharacters)
+// รางวัลหลายก้อนของตัวละครเดียวกันใน action เดียว (เช่น เคลียร์ครั้งแรก + ชนะ) จึงสะสมบนข้อมูลชุดเดียวกัน
+func (s *combatService) _PendingCharacter(match *domain.CombatMatch, characterID uint) *domain.Character {
+	for _, char := range match.PendingCharacters {
+		if char.ID == characterID {
+			return char
+		}
+	}
+	char, err := s.characterRepo.FindByID(characterID)
+	if err != nil {
+		s.appLogger.Error("failed to load character for progression", err, "character_id", characterID)
+		return nil
+	}
+	if char == nil {
+		return nil
+	}
+	match.PendingCharacters = append(match.PendingCharacters, char)
+	return char
 }
 
 // _CalculateExpReward คำนวณ EXP ที่ได้รับตาม Match Type
@@ -0,0 +1,130 @@
+package combat
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/pkg/applogger"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestTurnQueueFollowsInitiative(t *testing.T) {
+	s := newTurnQueueService()
+	match, names := newTurnQueueMatch(map[string]int{"A": 10, "B": 30, "C": 20}, "A", "B", "C")
+
+	assertTurnOrder(t, s, match, names, []string{"B", "C", "A"})
+	if match.TurnNumber != 1 {
+		t.Fatalf("turn number = %d before the round ends, want 1", match.TurnNumber)
+	}
+
+	// คนสุดท้ายจบเทิร์น → เริ่มรอบใหม่ และคนที่เร็วที่สุดได้เล่นก่อนอีกครั้ง
+	s.endTurn(match)
+	if match.TurnNumber != 2 {
+		t.Errorf("turn number = %d after everyone acted, want 2", match.TurnNumber)
+	}
+	if got := names[match.CurrentTurn]; got != "B" {
+		t.Errorf("first turn of round 2 = %s, want B", got)
+	}
+}
+
+func TestTurnQueueResortsAfterSlowMidRound(t *testing.T) {
+	s := newTurnQueueService()
+	match, names := newTurnQueueMatch(map[string]int{"A": 30, "B": 20, "C": 10}, "A", "B", "C")
+	a, b := match.Combatants[0], match.Combatants[1]
+
+	// A ร่าย DEBUFF_SLOW ใส่ B ในเทิร์นของตัวเอง → B (20-15 = 5) ตกไปหลัง C ในรอบเดียวกัน
+	s.applyDebuffSlow(a, b, map[string]interface{}{"value": -15.0, "duration": 2.0})
+	if b.Initiative != 5 {
+		t.Fatalf("slowed initiative = %d, want 5", b.Initiative)
+	}
+
+	assertTurnOrder(t, s, match, names, []string{"A", "C", "B"})
+	if match.TurnNumber != 1 {
+		t.Errorf("turn number = %d, want the slowed combatant to still act in round 1", match.TurnNumber)
+	}
+}
+
+func TestTurnQueueSkipsDeadAndFled(t *testing.T) {
+	s := newTurnQueueService()
+	match, names := newTurnQueueMatch(map[string]int{"A": 30, "B": 20, "C": 10, "D": 5}, "A", "B", "C", "D")
+	match.Combatants[1].CurrentHP = 0 // B ตายแล้ว
+	match.Combatants[2].HasFled = true
+
+	assertTurnOrder(t, s, match, names, []string{"A", "D"})
+	s.endTurn(match)
+	if match.TurnNumber != 2 || names[match.CurrentTurn] != "A" {
+		t.Errorf("after round 1: turn %d belongs to %s, want turn 2 for A", match.TurnNumber, names[match.CurrentTurn])
+	}
+
+	// ตายระหว่างรอบ (ยังไม่ได้เล่น) → ถูกข้ามทันที
+	match.Combatants[3].CurrentHP = 0
+	s.endTurn(match)
+	if match.TurnNumber != 3 || names[match.CurrentTurn] != "A" {
+		t.Errorf("with only A alive: turn %d belongs to %s, want turn 3 for A", match.TurnNumber, names[match.CurrentTurn])
+	}
+}
+
+func TestTurnQueueTieBreaksOnCombatantOrder(t *testing.T) {
+	s := newTurnQueueService()
+	match, names := newTurnQueueMatch(map[string]int{"A": 20, "B": 20, "C": 20}, "C", "A", "B")
+
+	assertTurnOrder(t, s, match, names, []string{"C", "A", "B"})
+	s.endTurn(match)
+	if got := names[match.CurrentTurn]; got != "C" {
+		t.Errorf("first turn of round 2 = %s, want C (first in match.Combatants)", got)
+	}
+}
+
+func TestTurnQueueKeepsTurnWhenNobodyCanAct(t *testing.T) {
+	s := newTurnQueueService()
+	match, names := newTurnQueueMatch(map[string]int{"A": 30, "B": 20}, "A", "B")
+	for _, c := range match.Combatants {
+		c.CurrentHP = 0
+	}
+
+	s.endTurn(match)
+	if got := names[match.CurrentTurn]; got != "A" {
+		t.Errorf("current turn = %s, want it to stay with A", got)
+	}
+}
+
+// --- Helpers ---
+
+func newTurnQueueService() *combatService {
+	return &combatService{appLogger: applogger.NewNopLogger()}
+}
+
+// newTurnQueueMatch สร้าง match รอบที่ 1 จากศัตรูตามลำดับ order (ชื่อ → Initiative) และให้เทิร์นแรกเป็นของคนที่เร็วที่สุด
+func newTurnQueueMatch(initiatives map[string]int, order ...string) (*domain.CombatMatch, map[uuid.UUID]string) {
+	match := &domain.CombatMatch{ID: uuid.Must(uuid.NewV7()), TurnNumber: 1}
+	names := make(map[uuid.UUID]string)
+	for i, name := range order {
+		enemyID := uint(i + 1)
+		c := &domain.Combatant{
+			ID:         uuid.Must(uuid.NewV7()),
+			EnemyID:    &enemyID,
+			Enemy:      &domain.Enemy{ID: enemyID, Initiative: initiatives[name]},
+			Team:       domain.MatchSideEnemy,
+			Initiative: initiatives[name],
+			CurrentHP:  10,
+		}
+		match.Combatants = append(match.Combatants, c)
+		names[c.ID] = name
+	}
+	s := newTurnQueueService()
+	match.CurrentTurn = s._NextInTurnQueue(match).ID
+	return match, names
+}
+
+// assertTurnOrder ตรวจว่าเทิร์นถัดไปเป็นไปตาม want (want[0] = เจ้าของเทิร์นปัจจุบัน) โดยเรียก endTurn ระหว่างแต่ละคน
+func assertTurnOrder(t *testing.T, s *combatService, match *domain.CombatMatch, names map[uuid.UUID]string, want []string) {
+	t.Helper()
+	for i, name := range want {
+		if i > 0 {
+			s.endTurn(match)
+		}
+		if got := names[match.CurrentTurn]; got != name {
+			t.Fatalf("turn %d of the round = %s, want %s (order %v)", i+1, got, name, want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// file: internal/modules/combat/turn_timer.go
+package combat
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// ==================== Turn Timer ====================
+// ไฟล์นี้รับผิดชอบการจับเวลาในสนามรบ
+// - เทิร์นของผู้เล่นมีเส้นตาย (TurnDeadline) = เวลาเริ่มเทิร์น + COMBAT_TURN_TIMEOUT
+// - ทั้ง match มีเส้นตาย (MatchDeadline) = เวลาสร้าง match + COMBAT_MATCH_TIMEOUT
+// - ProcessTimeouts (เรียกจาก background scheduler) จะ END_TURN แทนผู้เล่นที่หมดเวลา
+//   และจบ match ที่หมดเวลาทั้งหมดเป็น "แพ้"
+// - MatchModifiers.DisableTimer = true → ไม่จับเวลาเลย (เช่น โหมดฝึกซ้อม)
+// - ผู้เล่น PvP สดที่หลุดการเชื่อมต่อเกิน Combatant.DisconnectDeadline → ยอมแพ้แทน (ไม่ขึ้นกับ DisableTimer)
+
+// ActionTypeMatchTimeout คือ action ที่ระบบบันทึกเมื่อ match หมดเวลา (ให้ replay จบ match ได้เหมือนของจริง)
+const ActionTypeMatchTimeout = "MATCH_TIMEOUT"
+
+// ProcessTimeouts จัดการ match ที่เทิร์นหรือทั้ง match หมดเวลา หรือผู้เล่นหลุดเกินเวลาแล้ว คืนจำนวน match ที่ถูกจัดการ
+// ควรเรียกจาก scheduler ทุกไม่กี่วินาที
+func (s *combatService) ProcessTimeouts() (int, error) {
+	now := time.Now()
+	expired, err := s.combatRepo.FindTimedOutMatches(now)
+	if err != nil {
+		s.appLogger.Error("Failed to find timed out matches", err)
+		return 0, err
+	}
+
+	processed := 0
+	for _, m := range expired {
+		var handled bool
+		err := retryOnMatchConflict(func() (err error) {
+			handled, err = s._ResolveTimeout(m.ID.String(), now)
+			return err
+		})
+		if err != nil {
+			// match นี้พัง ไม่ควรทำให้ match อื่นค้างไปด้วย
+			s.appLogger.Error("Failed to resolve match timeout", err, "match_id", m.ID)
+			continue
+		}
+		if handled {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// _ResolveTimeout โหลด match ใหม่ (อาจมี action เข้ามาระหว่างนั้น) แล้วจัดการตามเส้นตายที่หมด
+// บันทึกชนกับ action ของผู้เล่น → ProcessTimeouts เรียกซ้ำ (ผู้เล่นส่ง action ทันแล้ว = เส้นตายใหม่ยังไม่หมด)
+func (s *combatService) _ResolveTimeout(matchID string, now time.Time) (bool, error) {
+	match, err := s.combatRepo.FindMatchByID(matchID)
+	if err != nil {
+		return false, err
+	}
+	if match.Status != domain.MatchInProgress {
+		return false, nil
+	}
+
+	var req PerformActionRequest
+	switch {
+	case isDeadlinePassed(match.MatchDeadline, now):
+		// ทั้ง match หมดเวลา → ผู้เล่นแพ้
+		req = PerformActionRequest{ActionType: ActionTypeMatchTimeout}
+		if actor := s.findCombatantByID(match, match.CurrentTurn); actor != nil {
+			s.recordAction(match, actor, req)
+		}
+		s._EndMatchByTimeout(match)
+		s.appLogger.Warn("Match timed out, player loses", "match_id", match.ID)
+
+	case s.findDisconnectedCombatant(match, now) != nil:
+		// ผู้เล่นหลุดแล้วไม่กลับมาทันเวลา → ยอมแพ้แทน (อีกฝ่ายชนะ)
+		actor := s.findDisconnectedCombatant(match, now)
+		req = PerformActionRequest{ActionType: ActionTypeForfeit}
+		s.recordAction(match, actor, req)
+		s._EndMatchByForfeit(match, actor)
+		s.appLogger.Warn("Player did not reconnect to live match, forfeited", "match_id", match.ID, "combatant_id", actor.ID)
+
+	case isDeadlinePassed(match.TurnDeadline, now):
+		// เทิร์นผู้เล่นหมดเวลา → END_TURN แทน แล้วให้ AI เล่นต่อ
+		actor := s.findCombatantByID(match, match.CurrentTurn)
+		if actor == nil || !isHumanControlled(actor) {
+			return false, nil
+		}
+		req = PerformActionRequest{ActionType: "END_TURN"}
+		s.recordAction(match, actor, req)
+		match, err = s.executePlayerAction(match, actor, req)
+		if err != nil {
+			return false, err
+		}
+		s.appLogger.Info("Player turn timed out, auto END_TURN", "match_id", match.ID, "combatant_id", actor.ID)
+
+	default:
+		return false, nil
+	}
+
+	events := match.PendingEvents
+	updatedMatch, err := s.combatRepo.UpdateMatch(match)
+	if err != nil {
+		return false, err
+	}
+	s._PublishMatchUpdate(updatedMatch, req, events)
+	return true, nil
+}
+
+// _CheckTurnNotExpired ปฏิเสธ action ที่มาหลังหมดเวลา (scheduler จะ END_TURN ให้ในรอบถัดไป)
+func (s *combatService) _CheckTurnNotExpired(match *domain.CombatMatch) error {
+	now := time.Now()
+	if isDeadlinePassed(match.MatchDeadline, now) {
+		return apperrors.New(409, "MATCH_TIMED_OUT", "this match has run out of time")
+	}
+	if isDeadlinePassed(match.TurnDeadline, now) {
+		return apperrors.New(409, "TURN_TIMED_OUT", "your turn has run out of time")
+	}
+	return nil
+}
+
+// _EndMatchByTimeout จบ match เพราะหมดเวลา (นับเป็นผู้เล่นแพ้)
+func (s *combatService) _EndMatchByTimeout(match *domain.CombatMatch) {
+	now := time.Now()
+	match.Status = domain.MatchFinished
+	match.FinishedAt = &now
+	match.TurnDeadline = nil
+	s._BuildMatchResults(match, domain.MatchSideEnemy, domain.MatchEndTimeout)
+
+	endEvent := newCombatEvent(domain.CombatEventMatchEnded, nil, nil, 0)
+	endEvent.Details = eventDetails(map[string]interface{}{"result": "PLAYER_LOSE", "reason": ActionTypeMatchTimeout})
+	s.recordEvent(match, endEvent)
+
+	s._UpdatePvpRatings(match, true)
+	s._GrantMasteryExp(match)
+}
+
+// _StartMatchTimer ตั้งเส้นตายของทั้ง match (เรียกตอน CreateMatch)
+func (s *combatService) _StartMatchTimer(match *domain.CombatMatch) {
+	if s._IsTimerDisabled(match) {
+		match.MatchDeadline = nil
+		return
+	}
+	deadline := time.Now().Add(s._GetConfigSeconds("COMBAT_MATCH_TIMEOUT", 1800))
+	match.MatchDeadline = &deadline
+}
+
+// _StartTurnTimer ตั้งเส้นตายของเทิร์นที่เพิ่งเริ่ม (เฉพาะผู้เล่น, AI เล่นจบในคำขอเดียวอยู่แล้ว)
+func (s *combatService) _StartTurnTimer(match *domain.CombatMatch, combatant *domain.Combatant) {
+	if combatant == nil || !isHumanControlled(combatant) || s._IsTimerDisabled(match) {
+		match.TurnDeadline = nil
+		return
+	}
+	deadline := time.Now().Add(s._GetConfigSeconds("COMBAT_TURN_TIMEOUT", 60))
+	match.TurnDeadline = &deadline
+}
+
+// _IsTimerDisabled อ่าน MatchModifiers.DisableTimer ของ match
+func (s *combatService) _IsTimerDisabled(match *domain.CombatMatch) bool {
+	if len(match.Modifiers) == 0 {
+		return false
+	}
+	var modifiers domain.MatchModifiers
+	if err := json.Unmarshal(match.Modifiers, &modifiers); err != nil {
+		s.appLogger.Warn("Failed to parse match modifiers", "match_id", match.ID, "error", err)
+		return false
+	}
+	return modifiers.DisableTimer
+}
+
+// _GetConfigSeconds ดึงค่า config หน่วยวินาที (ถ้าไม่มีหรือไม่ถูกต้องให้ใช้ค่า default)
+func (s *combatService) _GetConfigSeconds(key string, defaultSeconds int) time.Duration {
+	valueStr, _ := s.gameDataRepo.GetGameConfigValue(key)
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value <= 0 {
+		value = defaultSeconds
+	}
+	return time.Duration(value) * time.Second
+}
+
+func isDeadlinePassed(deadline *time.Time, now time.Time) bool {
+	return deadline != nil && now.After(*deadline)
+}
@@ -48,6 +48,7 @@ func (h *deckHandler) RegisterProtectedRoutes(router fiber.Router) {
 	router.Get("/", h.GetDecks)
 	router.Put("/:id", h.UpdateDeck)
 	router.Delete("/:id", h.DeleteDeck)
+	router.Put("/:id/defense", h.SetDefenseDeck)
 }
 
 func (h *deckHandler) CreateDeck(c *fiber.Ctx) error {
@@ -131,3 +132,18 @@ func (h *deckHandler) DeleteDeck(c *fiber.Ctx) error {
 	// 4. ส่ง Response 204 No Content (มาตรฐานสากลสำหรับการลบสำเร็จ)
 	return appresponse.NoContent(c)
 }
+
+func (h *deckHandler) SetDefenseDeck(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	deckIDStr := c.Params("id")
+	deckID, err := strconv.ParseUint(deckIDStr, 10, 32)
+	if err != nil {
+		return apperrors.InvalidFormatError("Invalid deck ID format", nil)
+	}
+
+	defenseDeck, err := h.service.SetDefenseDeck(claims.UserID, uint(deckID))
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Defense deck set successfully", defenseDeck, nil)
+}
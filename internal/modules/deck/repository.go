@@ -22,4 +22,10 @@ type DeckRepository interface {
 	// นับจำนวน Deck ของตัวละคร
 	CountByCharacterID(characterID uint) (int64, error)
 	Delete(deckID uint) error
+
+	// ตั้ง Deck นี้เป็น Defense Deck (และยกเลิก Deck อื่นของตัวละครเดียวกัน)
+	SetDefenseDeck(characterID, deckID uint) (*domain.Deck, error)
+
+	// ค้นหา Defense Deck ของตัวละคร (คืน nil ถ้ายังไม่ได้ตั้ง)
+	FindDefenseDeck(characterID uint) (*domain.Deck, error)
 }
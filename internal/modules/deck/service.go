@@ -16,6 +16,7 @@ type DeckService interface {
 	GetDecksByCharacterID(playerID, characterID uint) ([]domain.Deck, error)
 	UpdateDeck(playerID, deckID uint, req UpdateDeckRequest) (*domain.Deck, error)
 	DeleteDeck(playerID, deckID uint) error
+	SetDefenseDeck(playerID, deckID uint) (*domain.Deck, error)
 }
 
 // --- Implementation (การทำงานจริง) ---
@@ -141,3 +142,19 @@ func (s *deckService) DeleteDeck(playerID, deckID uint) error {
 	// 2. สั่งให้ Repository ลบ
 	return s.deckRepo.Delete(deckID)
 }
+
+// SetDefenseDeck ตั้ง Deck ที่จะถูกใช้ตอนผู้เล่นอื่นท้าชิง PvP แบบ async (AI เล่นแทนเจ้าของ)
+func (s *deckService) SetDefenseDeck(playerID, deckID uint) (*domain.Deck, error) {
+	// 1. ตรวจสอบความเป็นเจ้าของ Deck
+	deck, err := s.deckRepo.FindByID(deckID)
+	if err != nil || deck == nil {
+		return nil, apperrors.NotFoundError("deck not found")
+	}
+	char, _ := s.characterRepo.FindByID(deck.CharacterID)
+	if char == nil || char.PlayerID != playerID {
+		return nil, apperrors.PermissionDeniedError("you do not have permission to edit this deck")
+	}
+
+	// 2. สั่งให้ Repository ตั้งค่า (Deck อื่นของตัวละครจะถูกยกเลิกอัตโนมัติ)
+	return s.deckRepo.SetDefenseDeck(deck.CharacterID, deckID)
+}
@@ -1,6 +1,7 @@
 package fusion
 
 import (
+	"database/sql"
 	"sage-of-elements-backend/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,3 +18,10 @@ type FusionRepository interface {
 	// บันทึกการค้นพบใหม่ (ต้องทำใน Transaction)
 	LogDiscovery(tx *gorm.DB, characterID uint, recipeID uint) error
 }
+
+// Transactor คือ "สัญญา" สำหรับเปิด Transaction
+// - *gorm.DB implement interface นี้อยู่แล้ว (ใช้กับ postgres)
+// - storage แบบอื่น (เช่น memory) ส่ง tx เป็น nil ให้ repository ของตัวเองได้
+type Transactor interface {
+	Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
+}
@@ -28,7 +28,7 @@ type FusionService interface {
 // --- Service Implementation ---
 type fusionService struct {
 	appLogger     applogger.Logger
-	db            *gorm.DB
+	db            Transactor
 	fusionRepo    FusionRepository
 	characterRepo character.CharacterRepository
 	gameDataRepo  game_data.GameDataRepository
@@ -37,7 +37,7 @@ type fusionService struct {
 // (ใช้ชื่อ NewFusionService และคืนค่าเป็น FusionService)
 func NewFusionService(
 	appLogger applogger.Logger,
-	db *gorm.DB,
+	db Transactor,
 	fusionRepo FusionRepository,
 	characterRepo character.CharacterRepository,
 	gameDataRepo game_data.GameDataRepository,
@@ -74,7 +74,11 @@ func (s *fusionService) CraftElement(playerID, characterID uint, ingredients []I
 	for _, ing := range ingredients {
 		ingredientMap[ing.ElementID] += ing.Quantity
 	}
-	recipe, err := s.fusionRepo.FindRecipeByIngredients(s.db, ingredientMap)
+	var recipe *domain.Recipe
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		recipe, err = s.fusionRepo.FindRecipeByIngredients(tx, ingredientMap)
+		return err
+	})
 	if err != nil {
 		s.appLogger.Error("error finding recipe", err)
 		return nil, apperrors.SystemError("error finding recipe")
@@ -1,6 +1,8 @@
 package pve
 
 import (
+	"sage-of-elements-backend/pkg/appauth"
+	"sage-of-elements-backend/pkg/apperrors"
 	"sage-of-elements-backend/pkg/appresponse"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,7 +21,25 @@ func (h *PveHandler) RegisterProtectedRoutes(router fiber.Router) {
 	router.Get("/realms", h.GetRealms)
 }
 
+// GetRealms คืน Realm ทั้งหมด ถ้าส่ง ?character_id= มาด้วยจะแนบสถานะ LOCKED/UNLOCKED/CLEARED ของแต่ละด่าน
 func (h *PveHandler) GetRealms(c *fiber.Ctx) error {
+	if c.Query("character_id") != "" {
+		characterID := c.QueryInt("character_id", 0)
+		if characterID <= 0 {
+			return apperrors.InvalidFormatError("character_id must be a positive integer", nil)
+		}
+		claims, ok := c.Locals("user_claims").(*appauth.Claims)
+		if !ok || claims == nil {
+			return apperrors.UnauthorizedError("Invalid token claims")
+		}
+
+		realms, err := h.pveService.GetRealmsProgress(claims.UserID, uint(characterID))
+		if err != nil {
+			return err
+		}
+		return appresponse.Success(c, fiber.StatusOK, "Realms retrieved successfully", realms, nil)
+	}
+
 	realms, err := h.pveService.GetAllActiveRealms()
 	if err != nil {
 		return err
@@ -0,0 +1,71 @@
+// file: internal/modules/pve/progression.go
+package pve
+
+import (
+	"encoding/json"
+	"sage-of-elements-backend/internal/domain"
+)
+
+// ==================== Stage Progression ====================
+// กฎการปลดล็อกด่าน (ใช้ร่วมกันระหว่าง GET /pve/realms และ combat.CreateMatch):
+// - ด่านแรกของ Realm จะปลดล็อกเมื่อผ่าน Realm.UnlockCondition (ไม่มีเงื่อนไข = เปิดเสมอ)
+// - ด่านอื่นต้องเคลียร์ด่านก่อนหน้าก่อน (ด่านก่อนหน้าของด่านแรกในบท = ด่านสุดท้ายของบทก่อน)
+
+// StageStatus คือสถานะของด่านสำหรับตัวละคร 1 ตัว
+type StageStatus string
+
+const (
+	StageStatusLocked   StageStatus = "LOCKED"   // ยังเล่นไม่ได้
+	StageStatusUnlocked StageStatus = "UNLOCKED" // เล่นได้แต่ยังไม่เคยเคลียร์
+	StageStatusCleared  StageStatus = "CLEARED"  // เคลียร์แล้ว (เล่นซ้ำได้)
+)
+
+// ResolveStageStatuses คำนวณสถานะของทุกด่านใน realms (ต้องเรียง Chapters/Stages มาแล้ว)
+func ResolveStageStatuses(
+	realms []domain.Realm,
+	progress []domain.CharacterStageProgress,
+	character *domain.Character,
+) map[uint]StageStatus {
+	cleared := make(map[uint]bool, len(progress))
+	for _, p := range progress {
+		cleared[p.StageID] = true
+	}
+
+	statuses := make(map[uint]StageStatus)
+	for _, realm := range realms {
+		// ด่านแรกของ realm ขึ้นกับเงื่อนไขของ realm, ด่านถัดไปขึ้นกับด่านก่อนหน้า
+		previousCleared := isRealmUnlocked(realm, cleared, character)
+		for _, chapter := range realm.Chapters {
+			for _, stage := range chapter.Stages {
+				switch {
+				case cleared[stage.ID]:
+					statuses[stage.ID] = StageStatusCleared
+				case previousCleared:
+					statuses[stage.ID] = StageStatusUnlocked
+				default:
+					statuses[stage.ID] = StageStatusLocked
+				}
+				previousCleared = cleared[stage.ID]
+			}
+		}
+	}
+	return statuses
+}
+
+// isRealmUnlocked ตรวจ Realm.UnlockCondition (JSON ที่อ่านไม่ได้ถือว่ายังล็อก)
+func isRealmUnlocked(realm domain.Realm, cleared map[uint]bool, character *domain.Character) bool {
+	if len(realm.UnlockCondition) == 0 || string(realm.UnlockCondition) == "null" {
+		return true
+	}
+	var condition domain.RealmUnlockCondition
+	if err := json.Unmarshal(realm.UnlockCondition, &condition); err != nil {
+		return false
+	}
+	if condition.RequiredStageID != nil && !cleared[*condition.RequiredStageID] {
+		return false
+	}
+	if condition.MinLevel > 0 && (character == nil || character.Level < condition.MinLevel) {
+		return false
+	}
+	return true
+}
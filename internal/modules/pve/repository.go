@@ -5,4 +5,10 @@ import "sage-of-elements-backend/internal/domain"
 
 type PveRepository interface {
 	FindAllActiveRealms() ([]domain.Realm, error)
+	FindStageByID(stageID uint) (*domain.Stage, error)                   // คืน nil ถ้าไม่พบ
+	FindStageEnemiesByStageID(stageID uint) ([]domain.StageEnemy, error) // เรียงตาม Position
+
+	// --- Stage Progress (ความคืบหน้าของตัวละคร: เขียนพร้อมการจบ match ใน CombatRepository.UpdateMatch) ---
+	FindStageProgressByCharacterID(characterID uint) ([]domain.CharacterStageProgress, error)
+	FindStageProgress(characterID, stageID uint) (*domain.CharacterStageProgress, error) // คืน nil ถ้ายังไม่เคยเคลียร์
 }
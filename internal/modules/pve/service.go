@@ -1,19 +1,90 @@
 package pve
 
-import "sage-of-elements-backend/internal/domain"
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/pkg/apperrors"
+)
+
+// --- DTOs (ข้อมูล Realm พร้อมความคืบหน้าของตัวละคร) ---
+type RealmProgress struct {
+	domain.Realm
+	IsUnlocked bool
+	Chapters   []ChapterProgress
+}
+
+type ChapterProgress struct {
+	domain.Chapter
+	Stages []StageProgress
+}
+
+type StageProgress struct {
+	domain.Stage
+	Status   StageStatus
+	Progress *domain.CharacterStageProgress `json:",omitempty"`
+}
 
 type PveService interface {
 	GetAllActiveRealms() ([]domain.Realm, error)
+	GetRealmsProgress(playerID, characterID uint) ([]RealmProgress, error)
 }
 
 type pveService struct {
-	pveRepo PveRepository
+	pveRepo       PveRepository
+	characterRepo character.CharacterRepository
 }
 
-func NewPveService(pveRepo PveRepository) PveService {
-	return &pveService{pveRepo: pveRepo}
+func NewPveService(pveRepo PveRepository, characterRepo character.CharacterRepository) PveService {
+	return &pveService{pveRepo: pveRepo, characterRepo: characterRepo}
 }
 
 func (s *pveService) GetAllActiveRealms() ([]domain.Realm, error) {
 	return s.pveRepo.FindAllActiveRealms()
 }
+
+// GetRealmsProgress คืน Realm ทั้งหมดพร้อมสถานะ LOCKED/UNLOCKED/CLEARED ของแต่ละด่านสำหรับตัวละครนี้
+func (s *pveService) GetRealmsProgress(playerID, characterID uint) ([]RealmProgress, error) {
+	char, err := s.characterRepo.FindByID(characterID)
+	if err != nil || char == nil {
+		return nil, apperrors.NotFoundError("character not found")
+	}
+	if char.PlayerID != playerID {
+		return nil, apperrors.PermissionDeniedError("you are not the owner of this character")
+	}
+
+	realms, err := s.pveRepo.FindAllActiveRealms()
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.pveRepo.FindStageProgressByCharacterID(characterID)
+	if err != nil {
+		return nil, err
+	}
+	progressByStage := make(map[uint]*domain.CharacterStageProgress, len(progress))
+	for i := range progress {
+		progressByStage[progress[i].StageID] = &progress[i]
+	}
+	statuses := ResolveStageStatuses(realms, progress, char)
+
+	result := make([]RealmProgress, 0, len(realms))
+	for _, realm := range realms {
+		realmProgress := RealmProgress{Realm: realm}
+		for _, chapter := range realm.Chapters {
+			chapterProgress := ChapterProgress{Chapter: chapter}
+			for _, stage := range chapter.Stages {
+				status := statuses[stage.ID]
+				if status != StageStatusLocked {
+					realmProgress.IsUnlocked = true
+				}
+				chapterProgress.Stages = append(chapterProgress.Stages, StageProgress{
+					Stage:    stage,
+					Status:   status,
+					Progress: progressByStage[stage.ID],
+				})
+			}
+			realmProgress.Chapters = append(realmProgress.Chapters, chapterProgress)
+		}
+		result = append(result, realmProgress)
+	}
+	return result, nil
+}
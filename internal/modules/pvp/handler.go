@@ -0,0 +1,112 @@
+// file: internal/modules/pvp/handler.go
+package pvp
+
+import (
+	"sage-of-elements-backend/pkg/appauth"
+	"sage-of-elements-backend/pkg/apperrors"
+	"sage-of-elements-backend/pkg/appresponse"
+	"sage-of-elements-backend/pkg/appvalidator"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// --- DTOs ---
+
+type JoinQueueRequest struct {
+	CharacterID uint `json:"character_id" validate:"required"`
+}
+
+// --- Handler ---
+
+type pvpHandler struct {
+	validator *validator.Validate
+	service   PvpService
+}
+
+func NewPvpHandler(validator *validator.Validate, service PvpService) *pvpHandler {
+	return &pvpHandler{
+		validator: validator,
+		service:   service,
+	}
+}
+
+func (h *pvpHandler) RegisterProtectedRoutes(router fiber.Router) {
+	router.Post("/queue", h.JoinQueue)
+	router.Get("/queue", h.GetQueueStatus)
+	router.Delete("/queue", h.LeaveQueue)
+	router.Get("/rating", h.GetRating)
+}
+
+func (h *pvpHandler) JoinQueue(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	req := new(JoinQueueRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return apperrors.InvalidFormatError("Cannot parse JSON", nil)
+	}
+
+	if validationResult := appvalidator.Validate(h.validator, req); !validationResult.IsValid {
+		return apperrors.ValidationError("Validation failed", validationResult.Errors)
+	}
+
+	status, err := h.service.JoinQueue(claims.UserID, req.CharacterID)
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Joined ranked queue", status, nil)
+}
+
+func (h *pvpHandler) GetQueueStatus(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	charID, err := parseCharacterIDQuery(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := h.service.GetQueueStatus(claims.UserID, charID)
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Queue status retrieved successfully", status, nil)
+}
+
+func (h *pvpHandler) LeaveQueue(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	charID, err := parseCharacterIDQuery(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.LeaveQueue(claims.UserID, charID); err != nil {
+		return err
+	}
+	return appresponse.NoContent(c)
+}
+
+func (h *pvpHandler) GetRating(c *fiber.Ctx) error {
+	claims := c.Locals("user_claims").(*appauth.Claims)
+	charID, err := parseCharacterIDQuery(c)
+	if err != nil {
+		return err
+	}
+
+	rating, err := h.service.GetRating(claims.UserID, charID)
+	if err != nil {
+		return err
+	}
+	return appresponse.Success(c, fiber.StatusOK, "Rating retrieved successfully", rating, nil)
+}
+
+func parseCharacterIDQuery(c *fiber.Ctx) (uint, error) {
+	charIDStr := c.Query("character_id")
+	if charIDStr == "" {
+		return 0, apperrors.InvalidFormatError("Missing character_id query parameter", nil)
+	}
+	charID, err := strconv.ParseUint(charIDStr, 10, 32)
+	if err != nil {
+		return 0, apperrors.InvalidFormatError("Invalid character_id format", nil)
+	}
+	return uint(charID), nil
+}
@@ -0,0 +1,152 @@
+// file: internal/modules/pvp/rating.go
+package pvp
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"strconv"
+)
+
+// ==================== Rating (Glicko-2) ====================
+// ไฟล์นี้รวมสูตรคำนวณคะแนน Ranked PvP (ใช้ร่วมกันระหว่าง pvp service และ combat._EndMatch)
+// - 1 match = 1 rating period (อัปเดตทันทีหลังจบ match)
+// - ค่าเริ่มต้นและ tau อ่านจาก game_configs (PVP_RATING_DEFAULT, PVP_RATING_DEVIATION_DEFAULT, PVP_VOLATILITY_DEFAULT, PVP_GLICKO_TAU)
+// อ้างอิง: Mark Glickman, "Example of the Glicko-2 system"
+
+const (
+	glickoScale        = 173.7178
+	glickoBaseRating   = 1500.0
+	glickoEpsilon      = 0.000001
+	minRatingDeviation = 30.0  // กัน RD เล็กจนคะแนนแทบไม่ขยับ
+	maxRatingDeviation = 350.0 // RD ของผู้เล่นใหม่
+)
+
+// RankTier คือชั้นแรงค์ที่แสดงให้ผู้เล่นเห็น
+type RankTier string
+
+const (
+	RankBronze   RankTier = "BRONZE"
+	RankSilver   RankTier = "SILVER"
+	RankGold     RankTier = "GOLD"
+	RankPlatinum RankTier = "PLATINUM"
+	RankDiamond  RankTier = "DIAMOND"
+	RankMaster   RankTier = "MASTER"
+)
+
+// RankTierForRating แปลงคะแนนเป็นชั้นแรงค์
+func RankTierForRating(rating float64) RankTier {
+	switch {
+	case rating >= 2000:
+		return RankMaster
+	case rating >= 1800:
+		return RankDiamond
+	case rating >= 1600:
+		return RankPlatinum
+	case rating >= 1400:
+		return RankGold
+	case rating >= 1200:
+		return RankSilver
+	default:
+		return RankBronze
+	}
+}
+
+// NewDefaultRating สร้างคะแนนเริ่มต้นของตัวละครที่ยังไม่เคยเล่น Ranked (ยังไม่บันทึกลง DB)
+func NewDefaultRating(characterID uint, gameDataRepo game_data.GameDataRepository) *domain.CharacterPvpRating {
+	return &domain.CharacterPvpRating{
+		CharacterID:     characterID,
+		Rating:          configFloat(gameDataRepo, "PVP_RATING_DEFAULT", glickoBaseRating),
+		RatingDeviation: configFloat(gameDataRepo, "PVP_RATING_DEVIATION_DEFAULT", maxRatingDeviation),
+		Volatility:      configFloat(gameDataRepo, "PVP_VOLATILITY_DEFAULT", 0.06),
+	}
+}
+
+// ApplyMatchResult อัปเดตคะแนนของ player หลังเจอ opponent 1 match (won = player ชนะ)
+// opponent ต้องเป็นค่าก่อนอัปเดต (เรียกทั้งสองฝั่งด้วยค่าเดิมของกันและกัน)
+func ApplyMatchResult(player, opponent domain.CharacterPvpRating, won bool, tau float64) domain.CharacterPvpRating {
+	score := 0.0
+	if won {
+		score = 1.0
+	}
+
+	// 1. แปลงเป็นสเกล Glicko-2
+	mu := (player.Rating - glickoBaseRating) / glickoScale
+	phi := player.RatingDeviation / glickoScale
+	muJ := (opponent.Rating - glickoBaseRating) / glickoScale
+	phiJ := opponent.RatingDeviation / glickoScale
+
+	// 2. ค่าคาดหวังและความแปรปรวน
+	g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+	expected := 1 / (1 + math.Exp(-g*(mu-muJ)))
+	v := 1 / (g * g * expected * (1 - expected))
+	delta := v * g * (score - expected)
+
+	// 3. หา volatility ใหม่ (Illinois algorithm)
+	sigma := newVolatility(phi, player.Volatility, v, delta, tau)
+
+	// 4. RD และคะแนนใหม่
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*g*(score-expected)
+
+	updated := player
+	updated.Rating = glickoScale*newMu + glickoBaseRating
+	updated.RatingDeviation = math.Min(maxRatingDeviation, math.Max(minRatingDeviation, glickoScale*newPhi))
+	updated.Volatility = sigma
+	if won {
+		updated.Wins++
+	} else {
+		updated.Losses++
+	}
+	return updated
+}
+
+func newVolatility(phi, sigma, v, delta, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi*phi - v - ex)
+		den := 2 * math.Pow(phi*phi+v+ex, 2)
+		return num/den - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// GlickoTau อ่านค่า tau (ยิ่งน้อย volatility ยิ่งเปลี่ยนช้า)
+func GlickoTau(gameDataRepo game_data.GameDataRepository) float64 {
+	return configFloat(gameDataRepo, "PVP_GLICKO_TAU", 0.5)
+}
+
+func configFloat(gameDataRepo game_data.GameDataRepository, key string, defaultValue float64) float64 {
+	valueStr, _ := gameDataRepo.GetGameConfigValue(key)
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package pvp
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"testing"
+)
+
+func TestApplyMatchResultMatchesGlickoSteps(t *testing.T) {
+	// ผู้เล่นและคู่แรกจาก "Example of the Glicko-2 system" (tau = 0.5) แต่ rating period มี match เดียว
+	// สเกล Glicko-2: mu = 0, phi = 1.1513, muJ = -0.5756, phiJ = 0.1727 → g = 0.9955, E = 0.6395, v = 4.3767
+	player := domain.CharacterPvpRating{Rating: 1500, RatingDeviation: 200, Volatility: 0.06}
+	opponent := domain.CharacterPvpRating{Rating: 1400, RatingDeviation: 30, Volatility: 0.06}
+
+	updated := ApplyMatchResult(player, opponent, true, 0.5)
+
+	if !approxEqual(updated.Rating, 1563.56, 0.01) {
+		t.Errorf("rating = %.4f, want 1563.56", updated.Rating)
+	}
+	if !approxEqual(updated.RatingDeviation, 175.40, 0.01) {
+		t.Errorf("rating deviation = %.4f, want 175.40", updated.RatingDeviation)
+	}
+	if !approxEqual(updated.Volatility, 0.06, 0.00001) {
+		t.Errorf("volatility = %.6f, want 0.06", updated.Volatility)
+	}
+	if updated.Wins != 1 || updated.Losses != 0 {
+		t.Errorf("wins/losses = %d/%d, want 1/0", updated.Wins, updated.Losses)
+	}
+}
+
+func TestApplyMatchResult(t *testing.T) {
+	fresh := domain.CharacterPvpRating{Rating: 1500, RatingDeviation: 350, Volatility: 0.06}
+	veteran := domain.CharacterPvpRating{Rating: 1500, RatingDeviation: 50, Volatility: 0.06, Wins: 10, Losses: 4}
+
+	winner := ApplyMatchResult(fresh, fresh, true, 0.5)
+	loser := ApplyMatchResult(fresh, fresh, false, 0.5)
+	if winner.Rating <= 1500 || loser.Rating >= 1500 {
+		t.Fatalf("winner = %.2f, loser = %.2f, want winner above and loser below 1500", winner.Rating, loser.Rating)
+	}
+	if !approxEqual(winner.Rating-1500, 1500-loser.Rating, 1e-9) {
+		t.Errorf("equal players should move by the same amount: +%.4f vs -%.4f", winner.Rating-1500, 1500-loser.Rating)
+	}
+	if winner.Wins != 1 || winner.Losses != 0 || loser.Wins != 0 || loser.Losses != 1 {
+		t.Errorf("winner %d/%d, loser %d/%d, want 1/0 and 0/1", winner.Wins, winner.Losses, loser.Wins, loser.Losses)
+	}
+	if winner.RatingDeviation >= fresh.RatingDeviation {
+		t.Errorf("rating deviation = %.2f, want it to shrink after a match", winner.RatingDeviation)
+	}
+
+	// RD ต่ำ = มั่นใจในคะแนนแล้ว → ขยับน้อยกว่าผู้เล่นใหม่
+	settled := ApplyMatchResult(veteran, fresh, true, 0.5)
+	if settled.Rating-1500 >= winner.Rating-1500 {
+		t.Errorf("veteran gained %.2f, want less than the new player's %.2f", settled.Rating-1500, winner.Rating-1500)
+	}
+	if settled.Wins != 11 || settled.Losses != 4 {
+		t.Errorf("veteran wins/losses = %d/%d, want 11/4", settled.Wins, settled.Losses)
+	}
+	if settled.RatingDeviation < minRatingDeviation {
+		t.Errorf("rating deviation = %.2f, want at least %.0f", settled.RatingDeviation, minRatingDeviation)
+	}
+}
+
+func TestRankTierForRating(t *testing.T) {
+	tests := []struct {
+		rating float64
+		want   RankTier
+	}{
+		{rating: 0, want: RankBronze},
+		{rating: 1199.99, want: RankBronze},
+		{rating: 1200, want: RankSilver},
+		{rating: 1500, want: RankGold},
+		{rating: 1600, want: RankPlatinum},
+		{rating: 1800, want: RankDiamond},
+		{rating: 2400, want: RankMaster},
+	}
+	for _, tt := range tests {
+		if got := RankTierForRating(tt.rating); got != tt.want {
+			t.Errorf("RankTierForRating(%.2f) = %s, want %s", tt.rating, got, tt.want)
+		}
+	}
+}
+
+func approxEqual(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
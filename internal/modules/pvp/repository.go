@@ -0,0 +1,32 @@
+// file: internal/modules/pvp/repository.go
+package pvp
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"time"
+)
+
+type PvpRepository interface {
+	FindRatingByCharacterID(characterID uint) (*domain.CharacterPvpRating, error)     // คืน nil ถ้ายังไม่เคยเล่น Ranked
+	FindRatingHistory(characterID uint, limit int) ([]domain.PvpRatingHistory, error) // ล่าสุดก่อน
+}
+
+// QueueEntry คือตัวละคร 1 ตัวที่รอจับคู่อยู่ในคิว
+type QueueEntry struct {
+	CharacterID uint      `json:"character_id"`
+	PlayerID    uint      `json:"player_id"`
+	Rating      float64   `json:"rating"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// MatchmakingQueue คือคิวรอจับคู่ Ranked (Redis ในโหมด postgres, หน่วยความจำในโหมด memory)
+type MatchmakingQueue interface {
+	Enqueue(entry QueueEntry) error             // เขียนทับถ้ามีอยู่แล้ว
+	Remove(characterID uint) error              // ไม่มีในคิว = ไม่ error
+	Find(characterID uint) (*QueueEntry, error) // คืน nil ถ้าไม่อยู่ในคิว
+	FindAll() ([]QueueEntry, error)             // ไม่รับประกันลำดับ
+	ClaimPair(first, second uint) (bool, error) // เอาทั้งคู่ออกจากคิวแบบ atomic (false = มีตัวใดตัวหนึ่งหลุดคิวไปแล้ว)
+	SetMatched(characterID uint, matchID string, ttl time.Duration) error
+	FindMatched(characterID uint) (string, error) // match ที่เพิ่งจับคู่ได้ ("" = ไม่มี)
+	ClearMatched(characterID uint) error
+}
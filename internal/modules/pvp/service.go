@@ -0,0 +1,338 @@
+// file: internal/modules/pvp/service.go
+package pvp
+
+import (
+	"math"
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/character"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/pkg/apperrors"
+	"sage-of-elements-backend/pkg/applogger"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// สถานะของตัวละครในระบบ matchmaking
+const (
+	QueueStatusIdle      = "IDLE"      // ไม่ได้อยู่ในคิว
+	QueueStatusSearching = "SEARCHING" // กำลังหาคู่
+	QueueStatusMatched   = "MATCHED"   // จับคู่แล้ว (ต่อ WebSocket ของ MatchID ได้เลย)
+)
+
+// matchedResultTTL คือเวลาที่เก็บผลการจับคู่ไว้ให้ client มาถาม
+const matchedResultTTL = 10 * time.Minute
+
+// ratingHistoryLimit คือจำนวนประวัติที่ส่งกลับใน GET /pvp/rating
+const ratingHistoryLimit = 20
+
+// --- DTOs ---
+type QueueStatus struct {
+	Status         string   `json:"status"`
+	MatchID        *string  `json:"match_id,omitempty"`
+	Rating         float64  `json:"rating"`
+	WaitingSeconds int      `json:"waiting_seconds,omitempty"`
+	SearchWindow   *float64 `json:"search_window,omitempty"` // ช่วงคะแนนที่ยอมรับได้ตอนนี้ (กว้างขึ้นตามเวลารอ)
+}
+
+type RatingSummary struct {
+	CharacterID     uint                      `json:"character_id"`
+	Rating          float64                   `json:"rating"`
+	RatingDeviation float64                   `json:"rating_deviation"`
+	Tier            RankTier                  `json:"tier"`
+	Wins            int                       `json:"wins"`
+	Losses          int                       `json:"losses"`
+	History         []domain.PvpRatingHistory `json:"history"`
+}
+
+// MatchCreator คือส่วนของ combat.CombatService ที่ matchmaking ต้องใช้ (แยกไว้เพื่อไม่ให้ import วนกัน)
+type MatchCreator interface {
+	CreateRankedMatch(playerID, characterID, opponentCharacterID uint) (*domain.CombatMatch, error)
+	GetPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error)
+}
+
+type PvpService interface {
+	JoinQueue(playerID, characterID uint) (*QueueStatus, error)
+	LeaveQueue(playerID, characterID uint) error
+	GetQueueStatus(playerID, characterID uint) (*QueueStatus, error)
+	ProcessQueue() (int, error) // จับคู่ทุกคนในคิวที่คะแนนใกล้กันพอ (สำหรับ scheduler) คืนจำนวน match ที่สร้าง
+	GetRating(playerID, characterID uint) (*RatingSummary, error)
+}
+
+type pvpService struct {
+	appLogger     applogger.Logger
+	pvpRepo       PvpRepository
+	queue         MatchmakingQueue
+	characterRepo character.CharacterRepository
+	gameDataRepo  game_data.GameDataRepository
+	matchCreator  MatchCreator
+}
+
+func NewPvpService(
+	appLogger applogger.Logger,
+	pvpRepo PvpRepository,
+	queue MatchmakingQueue,
+	characterRepo character.CharacterRepository,
+	gameDataRepo game_data.GameDataRepository,
+	matchCreator MatchCreator,
+) PvpService {
+	return &pvpService{
+		appLogger:     appLogger,
+		pvpRepo:       pvpRepo,
+		queue:         queue,
+		characterRepo: characterRepo,
+		gameDataRepo:  gameDataRepo,
+		matchCreator:  matchCreator,
+	}
+}
+
+// JoinQueue นำตัวละครเข้าคิว Ranked (ถ้าอยู่ในคิวแล้วจะคืนสถานะเดิม ไม่เริ่มนับเวลาใหม่)
+func (s *pvpService) JoinQueue(playerID, characterID uint) (*QueueStatus, error) {
+	if err := s._CheckOwnership(playerID, characterID); err != nil {
+		return nil, err
+	}
+
+	activeMatch, err := s.matchCreator.GetPlayerActiveMatch(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to check active match", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to check active match")
+	}
+	if activeMatch != nil {
+		return nil, apperrors.New(409, "MATCH_ALREADY_ACTIVE",
+			"character already has an active match: "+activeMatch.ID.String())
+	}
+
+	existing, err := s.queue.Find(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to read matchmaking queue", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to join matchmaking queue")
+	}
+	if existing == nil {
+		rating, err := s._GetOrDefaultRating(characterID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.queue.ClearMatched(characterID); err != nil {
+			s.appLogger.Warn("Failed to clear previous match result", "character_id", characterID, "error", err)
+		}
+		if err := s.queue.Enqueue(QueueEntry{
+			CharacterID: characterID,
+			PlayerID:    playerID,
+			Rating:      rating.Rating,
+			JoinedAt:    time.Now(),
+		}); err != nil {
+			s.appLogger.Error("Failed to enqueue character", err, "character_id", characterID)
+			return nil, apperrors.SystemError("failed to join matchmaking queue")
+		}
+		s.appLogger.Info("Character joined ranked queue", "character_id", characterID, "rating", rating.Rating)
+	}
+
+	return s.GetQueueStatus(playerID, characterID)
+}
+
+// LeaveQueue เอาตัวละครออกจากคิว (ไม่อยู่ในคิวก็ถือว่าสำเร็จ)
+func (s *pvpService) LeaveQueue(playerID, characterID uint) error {
+	if err := s._CheckOwnership(playerID, characterID); err != nil {
+		return err
+	}
+	if err := s.queue.Remove(characterID); err != nil {
+		s.appLogger.Error("Failed to leave matchmaking queue", err, "character_id", characterID)
+		return apperrors.SystemError("failed to leave matchmaking queue")
+	}
+	return nil
+}
+
+// GetQueueStatus บอกว่าตัวละครกำลังหาคู่ จับคู่ได้แล้ว หรือไม่ได้อยู่ในคิว
+func (s *pvpService) GetQueueStatus(playerID, characterID uint) (*QueueStatus, error) {
+	if err := s._CheckOwnership(playerID, characterID); err != nil {
+		return nil, err
+	}
+
+	matchID, err := s.queue.FindMatched(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to read match result", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to read matchmaking status")
+	}
+	entry, err := s.queue.Find(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to read matchmaking queue", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to read matchmaking status")
+	}
+
+	switch {
+	case matchID != "":
+		rating, err := s._GetOrDefaultRating(characterID)
+		if err != nil {
+			return nil, err
+		}
+		return &QueueStatus{Status: QueueStatusMatched, MatchID: &matchID, Rating: rating.Rating}, nil
+	case entry != nil:
+		now := time.Now()
+		window := s._SearchWindow(*entry, now)
+		return &QueueStatus{
+			Status:         QueueStatusSearching,
+			Rating:         entry.Rating,
+			WaitingSeconds: int(now.Sub(entry.JoinedAt).Seconds()),
+			SearchWindow:   &window,
+		}, nil
+	default:
+		rating, err := s._GetOrDefaultRating(characterID)
+		if err != nil {
+			return nil, err
+		}
+		return &QueueStatus{Status: QueueStatusIdle, Rating: rating.Rating}, nil
+	}
+}
+
+// ProcessQueue จับคู่ตัวละครในคิว: คนที่รอนานที่สุดได้เลือกก่อน และเลือกคู่ที่คะแนนใกล้ที่สุด
+// คู่ที่จับได้ต้องมีส่วนต่างคะแนนไม่เกิน search window ของทั้งสองฝั่ง (window กว้างขึ้นตามเวลารอ)
+func (s *pvpService) ProcessQueue() (int, error) {
+	entries, err := s.queue.FindAll()
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].JoinedAt.Before(entries[j].JoinedAt) })
+
+	now := time.Now()
+	taken := make(map[uint]bool, len(entries))
+	created := 0
+	for i, first := range entries {
+		if taken[first.CharacterID] {
+			continue
+		}
+
+		// 1. หาคู่ที่คะแนนใกล้ที่สุดในหน้าต่างของทั้งสองฝั่ง
+		var opponent *QueueEntry
+		bestDiff := math.MaxFloat64
+		firstWindow := s._SearchWindow(first, now)
+		for j := i + 1; j < len(entries); j++ {
+			candidate := entries[j]
+			if taken[candidate.CharacterID] || candidate.PlayerID == first.PlayerID {
+				continue
+			}
+			diff := math.Abs(first.Rating - candidate.Rating)
+			if diff > firstWindow || diff > s._SearchWindow(candidate, now) {
+				continue
+			}
+			if diff < bestDiff {
+				opponent, bestDiff = &entries[j], diff
+			}
+		}
+		if opponent == nil {
+			continue
+		}
+
+		// 2. เอาทั้งคู่ออกจากคิว (กันอีก instance จับคู่ซ้ำ)
+		claimed, err := s.queue.ClaimPair(first.CharacterID, opponent.CharacterID)
+		if err != nil {
+			return created, err
+		}
+		taken[first.CharacterID] = true
+		taken[opponent.CharacterID] = true
+		if !claimed {
+			continue
+		}
+
+		// 3. สร้าง match (คนที่รอนานกว่าเป็นผู้ท้าชิง)
+		match, err := s.matchCreator.CreateRankedMatch(first.PlayerID, first.CharacterID, opponent.CharacterID)
+		if err != nil {
+			// ทั้งคู่หลุดคิวไปแล้ว → client จะเห็น IDLE และเข้าคิวใหม่ได้
+			s.appLogger.Error("Failed to create ranked match", err,
+				"character_id", first.CharacterID,
+				"opponent_character_id", opponent.CharacterID,
+			)
+			continue
+		}
+		for _, characterID := range []uint{first.CharacterID, opponent.CharacterID} {
+			if err := s.queue.SetMatched(characterID, match.ID.String(), matchedResultTTL); err != nil {
+				s.appLogger.Error("Failed to store match result", err, "character_id", characterID, "match_id", match.ID)
+			}
+		}
+		created++
+
+		s.appLogger.Info("Ranked match created",
+			"match_id", match.ID,
+			"character_id", first.CharacterID,
+			"opponent_character_id", opponent.CharacterID,
+			"rating_diff", bestDiff,
+		)
+	}
+	return created, nil
+}
+
+// GetRating คืนคะแนน ชั้นแรงค์ และประวัติล่าสุดของตัวละคร
+func (s *pvpService) GetRating(playerID, characterID uint) (*RatingSummary, error) {
+	if err := s._CheckOwnership(playerID, characterID); err != nil {
+		return nil, err
+	}
+
+	rating, err := s._GetOrDefaultRating(characterID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.pvpRepo.FindRatingHistory(characterID, ratingHistoryLimit)
+	if err != nil {
+		s.appLogger.Error("Failed to load rating history", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to load rating history")
+	}
+	if history == nil {
+		history = []domain.PvpRatingHistory{}
+	}
+
+	return &RatingSummary{
+		CharacterID:     characterID,
+		Rating:          rating.Rating,
+		RatingDeviation: rating.RatingDeviation,
+		Tier:            RankTierForRating(rating.Rating),
+		Wins:            rating.Wins,
+		Losses:          rating.Losses,
+		History:         history,
+	}, nil
+}
+
+// ==================== Helpers ====================
+
+func (s *pvpService) _CheckOwnership(playerID, characterID uint) error {
+	char, err := s.characterRepo.FindByID(characterID)
+	if err != nil || char == nil {
+		return apperrors.NotFoundError("character not found")
+	}
+	if char.PlayerID != playerID {
+		return apperrors.PermissionDeniedError("you are not the owner of this character")
+	}
+	return nil
+}
+
+func (s *pvpService) _GetOrDefaultRating(characterID uint) (*domain.CharacterPvpRating, error) {
+	rating, err := s.pvpRepo.FindRatingByCharacterID(characterID)
+	if err != nil {
+		s.appLogger.Error("Failed to load pvp rating", err, "character_id", characterID)
+		return nil, apperrors.SystemError("failed to load pvp rating")
+	}
+	if rating == nil {
+		rating = NewDefaultRating(characterID, s.gameDataRepo)
+	}
+	return rating, nil
+}
+
+// _SearchWindow = PVP_MATCH_WINDOW_BASE + PVP_MATCH_WINDOW_GROWTH_PER_SEC × วินาทีที่รอ (ไม่เกิน PVP_MATCH_WINDOW_MAX)
+func (s *pvpService) _SearchWindow(entry QueueEntry, now time.Time) float64 {
+	base := s._GetConfigFloat("PVP_MATCH_WINDOW_BASE", 100)
+	growth := s._GetConfigFloat("PVP_MATCH_WINDOW_GROWTH_PER_SEC", 10)
+	maxWindow := s._GetConfigFloat("PVP_MATCH_WINDOW_MAX", 800)
+
+	waited := now.Sub(entry.JoinedAt).Seconds()
+	if waited < 0 {
+		waited = 0
+	}
+	return math.Min(maxWindow, base+growth*waited)
+}
+
+func (s *pvpService) _GetConfigFloat(key string, defaultValue float64) float64 {
+	valueStr, _ := s.gameDataRepo.GetGameConfigValue(key)
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,180 @@
+package pvp
+
+import (
+	"sage-of-elements-backend/internal/domain"
+	"sage-of-elements-backend/internal/modules/game_data"
+	"sage-of-elements-backend/pkg/applogger"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestProcessQueuePairsWithinSearchWindow(t *testing.T) {
+	// window = 100 + 10 × วินาทีที่รอ (ไม่เกิน 800)
+	now := time.Now()
+	waited := func(seconds int) time.Time { return now.Add(-time.Duration(seconds) * time.Second) }
+
+	tests := []struct {
+		name    string
+		entries []QueueEntry
+		want    [][2]uint // [ผู้ท้าชิง, คู่ต่อสู้]
+	}{
+		{
+			name: "close ratings pair immediately",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 1, Rating: 1500, JoinedAt: waited(2)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1550, JoinedAt: waited(1)},
+			},
+			want: [][2]uint{{1, 2}},
+		},
+		{
+			name: "wide gap waits for the window to grow",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 1, Rating: 1500, JoinedAt: waited(2)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1800, JoinedAt: waited(1)},
+			},
+		},
+		{
+			name: "wide gap pairs after both waited long enough",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 1, Rating: 1500, JoinedAt: waited(31)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1800, JoinedAt: waited(30)},
+			},
+			want: [][2]uint{{1, 2}},
+		},
+		{
+			name: "gap must fit the newcomer's window too",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 1, Rating: 1500, JoinedAt: waited(60)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1800, JoinedAt: waited(1)},
+			},
+		},
+		{
+			name: "window stops growing at the cap",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 1, Rating: 1000, JoinedAt: waited(600)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1900, JoinedAt: waited(500)},
+			},
+		},
+		{
+			name: "characters of the same player never pair",
+			entries: []QueueEntry{
+				{CharacterID: 1, PlayerID: 7, Rating: 1500, JoinedAt: waited(2)},
+				{CharacterID: 2, PlayerID: 7, Rating: 1500, JoinedAt: waited(1)},
+			},
+		},
+		{
+			name: "longest waiter takes the closest rating",
+			entries: []QueueEntry{
+				{CharacterID: 3, PlayerID: 3, Rating: 1580, JoinedAt: waited(2)},
+				{CharacterID: 1, PlayerID: 1, Rating: 1500, JoinedAt: waited(5)},
+				{CharacterID: 2, PlayerID: 2, Rating: 1520, JoinedAt: waited(1)},
+			},
+			want: [][2]uint{{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newFakeQueue(tt.entries)
+			creator := &fakeMatchCreator{}
+			s := &pvpService{
+				appLogger:    applogger.NewNopLogger(),
+				queue:        queue,
+				gameDataRepo: fakeGameData{},
+				matchCreator: creator,
+			}
+
+			created, err := s.ProcessQueue()
+			if err != nil {
+				t.Fatalf("ProcessQueue() error = %v", err)
+			}
+			if created != len(tt.want) {
+				t.Fatalf("ProcessQueue() created %d matches (%v), want %d", created, creator.pairs, len(tt.want))
+			}
+			for i, pair := range tt.want {
+				if creator.pairs[i] != pair {
+					t.Errorf("match %d = %v, want %v", i, creator.pairs[i], pair)
+				}
+				for _, characterID := range pair {
+					if queue.entries[characterID] != nil {
+						t.Errorf("character %d is still queued after being matched", characterID)
+					}
+					if queue.matched[characterID] == "" {
+						t.Errorf("character %d has no match result", characterID)
+					}
+				}
+			}
+			if remaining := len(tt.entries) - 2*len(tt.want); len(queue.entries) != remaining {
+				t.Errorf("%d characters left in queue, want %d", len(queue.entries), remaining)
+			}
+		})
+	}
+}
+
+// --- Fakes ---
+
+type fakeQueue struct {
+	MatchmakingQueue
+	entries map[uint]*QueueEntry
+	matched map[uint]string
+}
+
+func newFakeQueue(entries []QueueEntry) *fakeQueue {
+	q := &fakeQueue{entries: make(map[uint]*QueueEntry), matched: make(map[uint]string)}
+	for i := range entries {
+		q.entries[entries[i].CharacterID] = &entries[i]
+	}
+	return q
+}
+
+func (q *fakeQueue) FindAll() ([]QueueEntry, error) {
+	all := make([]QueueEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		all = append(all, *entry)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CharacterID < all[j].CharacterID })
+	return all, nil
+}
+
+func (q *fakeQueue) ClaimPair(first, second uint) (bool, error) {
+	if q.entries[first] == nil || q.entries[second] == nil {
+		return false, nil
+	}
+	delete(q.entries, first)
+	delete(q.entries, second)
+	return true, nil
+}
+
+func (q *fakeQueue) SetMatched(characterID uint, matchID string, ttl time.Duration) error {
+	q.matched[characterID] = matchID
+	return nil
+}
+
+type fakeMatchCreator struct {
+	pairs [][2]uint
+}
+
+func (c *fakeMatchCreator) CreateRankedMatch(playerID, characterID, opponentCharacterID uint) (*domain.CombatMatch, error) {
+	c.pairs = append(c.pairs, [2]uint{characterID, opponentCharacterID})
+	return &domain.CombatMatch{ID: uuid.Must(uuid.NewV7())}, nil
+}
+
+func (c *fakeMatchCreator) GetPlayerActiveMatch(characterID uint) (*domain.CombatMatch, error) {
+	return nil, nil
+}
+
+type fakeGameData struct {
+	game_data.GameDataRepository
+}
+
+func (fakeGameData) GetGameConfigValue(key string) (string, error) {
+	configs := map[string]string{
+		"PVP_MATCH_WINDOW_BASE":           "100",
+		"PVP_MATCH_WINDOW_GROWTH_PER_SEC": "10",
+		"PVP_MATCH_WINDOW_MAX":            "800",
+	}
+	return configs[key], nil
+}
@@ -9,12 +9,14 @@ import (
 
 // Config คือ struct หลักที่เก็บทุกอย่าง
 type Config struct {
-	App      AppConfig     `mapstructure:"app"`
-	Server   ServerConfig  `mapstructure:"server"`
-	Cleanup  CleanupConfig `mapstructure:"cleanup"`
-	Postgres PostgresDbs   `mapstructure:"postgres"`
-	Auth     AuthConfig    `mapstructure:"auth"`
-	Redis    RedisConfig   `mapstructure:"redis"`
+	App         AppConfig         `mapstructure:"app"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Cleanup     CleanupConfig     `mapstructure:"cleanup"`
+	TurnTimer   TurnTimerConfig   `mapstructure:"turn_timer"`
+	Matchmaking MatchmakingConfig `mapstructure:"matchmaking"`
+	Postgres    PostgresDbs       `mapstructure:"postgres"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Redis       RedisConfig       `mapstructure:"redis"`
 }
 
 type AppConfig struct {
@@ -26,6 +28,7 @@ type ServerConfig struct {
 	Mode     string `mapstructure:"mode"`
 	AppPort  string `mapstructure:"appport"`  // ✨ ชัดเจน! นี่คือพอร์ตของ App ข้างใน
 	HostPort string `mapstructure:"hostport"` // ✨ ชัดเจน! นี่คือพอร์ตบน Host ข้างนอก
+	Storage  string `mapstructure:"storage"`  // postgres (ค่าเริ่มต้น) หรือ memory (ไม่ต้องใช้ Postgres/Redis)
 }
 
 type CleanupConfig struct {
@@ -33,6 +36,18 @@ type CleanupConfig struct {
 	TimeoutMinutes  int `mapstructure:"timeout_minutes"`  // abort match ที่ไม่มีความเคลื่อนไหวเกินกี่นาที
 }
 
+// TurnTimerConfig ตั้งค่า job ที่คอย END_TURN แทนผู้เล่นที่หมดเวลา
+// (ความยาวของเทิร์น/match อยู่ใน game config: COMBAT_TURN_TIMEOUT, COMBAT_MATCH_TIMEOUT)
+type TurnTimerConfig struct {
+	CheckIntervalSeconds int `mapstructure:"check_interval_seconds"` // ทุกกี่วินาทีให้ตรวจ match ที่หมดเวลา
+}
+
+// MatchmakingConfig ตั้งค่า job ที่จับคู่ผู้เล่นในคิว Ranked PvP
+// (ช่วงคะแนนที่ยอมรับอยู่ใน game config: PVP_MATCH_WINDOW_*)
+type MatchmakingConfig struct {
+	IntervalSeconds int `mapstructure:"interval_seconds"` // ทุกกี่วินาทีให้ลองจับคู่
+}
+
 type PostgresDbs struct {
 	Primary PostgresConfig `mapstructure:"primary"`
 	Logs    PostgresConfig `mapstructure:"logs"`
@@ -76,6 +91,9 @@ func LoadConfig() (*Config, error) {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	// ค่าเริ่มต้น (ต้องประกาศ key ไว้ Viper ถึงจะอ่าน Env Var เช่น SERVER_STORAGE ได้แม้ไม่มีในไฟล์)
+	viper.SetDefault("server.storage", "postgres")
+
 	// อ่านไฟล์ config.yml (เป็นค่าเริ่มต้น)
 	if err := viper.ReadInConfig(); err != nil {
 		fmt.Println("Info: No config file found, using environment variables only.")
@@ -0,0 +1,17 @@
+// pkg/logger/nop_logger.go
+package applogger
+
+type nopLogger struct{}
+
+// NewNopLogger คือนักข่าวที่ไม่รายงานอะไรเลย (สำหรับ tool/simulator ที่รันหลายพันรอบ)
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(msg string, args ...any)                   {}
+func (nopLogger) Info(msg string, args ...any)                    {}
+func (nopLogger) Success(msg string, args ...any)                 {}
+func (nopLogger) Warn(msg string, args ...any)                    {}
+func (nopLogger) Error(msg string, err error, args ...any)        {}
+func (nopLogger) Dump(a ...any)                                   {}
+func (nopLogger) Highlight(color string, msg string, data ...any) {}